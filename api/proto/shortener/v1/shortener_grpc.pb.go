@@ -0,0 +1,336 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: shortener/v1/shortener.proto
+
+// Package shortener.v1 là gRPC API của URL shortener, tương ứng với IURLUsecase
+// và các endpoint REST /api/v1/urls.
+
+package shortenerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	URLShortenerService_CreateShortURL_FullMethodName = "/shortener.v1.URLShortenerService/CreateShortURL"
+	URLShortenerService_GetURL_FullMethodName         = "/shortener.v1.URLShortenerService/GetURL"
+	URLShortenerService_GetURLStats_FullMethodName    = "/shortener.v1.URLShortenerService/GetURLStats"
+	URLShortenerService_DeleteURL_FullMethodName      = "/shortener.v1.URLShortenerService/DeleteURL"
+	URLShortenerService_ListClicks_FullMethodName     = "/shortener.v1.URLShortenerService/ListClicks"
+	URLShortenerService_StreamClicks_FullMethodName   = "/shortener.v1.URLShortenerService/StreamClicks"
+)
+
+// URLShortenerServiceClient is the client API for URLShortenerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// URLShortenerService tạo, tra cứu, thống kê và xóa short URL
+type URLShortenerServiceClient interface {
+	// CreateShortURL tạo short URL (POST /api/v1/urls)
+	CreateShortURL(ctx context.Context, in *CreateShortURLRequest, opts ...grpc.CallOption) (*CreateShortURLResponse, error)
+	// GetURL lấy URL gốc của short code đang active (GET /api/v1/urls/{short_code})
+	GetURL(ctx context.Context, in *GetURLRequest, opts ...grpc.CallOption) (*GetURLResponse, error)
+	// GetURLStats lấy thống kê tổng quan (GET /api/v1/urls/{short_code}/stats)
+	GetURLStats(ctx context.Context, in *GetURLStatsRequest, opts ...grpc.CallOption) (*GetURLStatsResponse, error)
+	// DeleteURL xóa URL (DELETE /api/v1/urls/{short_code})
+	DeleteURL(ctx context.Context, in *DeleteURLRequest, opts ...grpc.CallOption) (*DeleteURLResponse, error)
+	// ListClicks lấy lịch sử click, mới nhất trước (GET /api/v1/urls/{short_code}/clicks)
+	ListClicks(ctx context.Context, in *ListClicksRequest, opts ...grpc.CallOption) (*ListClicksResponse, error)
+	// StreamClicks nhận click realtime; last_event_id để nhận lại các click bị lỡ
+	// (GET /api/v1/urls/{short_code}/events)
+	StreamClicks(ctx context.Context, in *StreamClicksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamClicksResponse], error)
+}
+
+type uRLShortenerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewURLShortenerServiceClient(cc grpc.ClientConnInterface) URLShortenerServiceClient {
+	return &uRLShortenerServiceClient{cc}
+}
+
+func (c *uRLShortenerServiceClient) CreateShortURL(ctx context.Context, in *CreateShortURLRequest, opts ...grpc.CallOption) (*CreateShortURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateShortURLResponse)
+	err := c.cc.Invoke(ctx, URLShortenerService_CreateShortURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerServiceClient) GetURL(ctx context.Context, in *GetURLRequest, opts ...grpc.CallOption) (*GetURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetURLResponse)
+	err := c.cc.Invoke(ctx, URLShortenerService_GetURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerServiceClient) GetURLStats(ctx context.Context, in *GetURLStatsRequest, opts ...grpc.CallOption) (*GetURLStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetURLStatsResponse)
+	err := c.cc.Invoke(ctx, URLShortenerService_GetURLStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerServiceClient) DeleteURL(ctx context.Context, in *DeleteURLRequest, opts ...grpc.CallOption) (*DeleteURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteURLResponse)
+	err := c.cc.Invoke(ctx, URLShortenerService_DeleteURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerServiceClient) ListClicks(ctx context.Context, in *ListClicksRequest, opts ...grpc.CallOption) (*ListClicksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListClicksResponse)
+	err := c.cc.Invoke(ctx, URLShortenerService_ListClicks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerServiceClient) StreamClicks(ctx context.Context, in *StreamClicksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamClicksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &URLShortenerService_ServiceDesc.Streams[0], URLShortenerService_StreamClicks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamClicksRequest, StreamClicksResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortenerService_StreamClicksClient = grpc.ServerStreamingClient[StreamClicksResponse]
+
+// URLShortenerServiceServer is the server API for URLShortenerService service.
+// All implementations must embed UnimplementedURLShortenerServiceServer
+// for forward compatibility.
+//
+// URLShortenerService tạo, tra cứu, thống kê và xóa short URL
+type URLShortenerServiceServer interface {
+	// CreateShortURL tạo short URL (POST /api/v1/urls)
+	CreateShortURL(context.Context, *CreateShortURLRequest) (*CreateShortURLResponse, error)
+	// GetURL lấy URL gốc của short code đang active (GET /api/v1/urls/{short_code})
+	GetURL(context.Context, *GetURLRequest) (*GetURLResponse, error)
+	// GetURLStats lấy thống kê tổng quan (GET /api/v1/urls/{short_code}/stats)
+	GetURLStats(context.Context, *GetURLStatsRequest) (*GetURLStatsResponse, error)
+	// DeleteURL xóa URL (DELETE /api/v1/urls/{short_code})
+	DeleteURL(context.Context, *DeleteURLRequest) (*DeleteURLResponse, error)
+	// ListClicks lấy lịch sử click, mới nhất trước (GET /api/v1/urls/{short_code}/clicks)
+	ListClicks(context.Context, *ListClicksRequest) (*ListClicksResponse, error)
+	// StreamClicks nhận click realtime; last_event_id để nhận lại các click bị lỡ
+	// (GET /api/v1/urls/{short_code}/events)
+	StreamClicks(*StreamClicksRequest, grpc.ServerStreamingServer[StreamClicksResponse]) error
+	mustEmbedUnimplementedURLShortenerServiceServer()
+}
+
+// UnimplementedURLShortenerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedURLShortenerServiceServer struct{}
+
+func (UnimplementedURLShortenerServiceServer) CreateShortURL(context.Context, *CreateShortURLRequest) (*CreateShortURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShortURL not implemented")
+}
+func (UnimplementedURLShortenerServiceServer) GetURL(context.Context, *GetURLRequest) (*GetURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURL not implemented")
+}
+func (UnimplementedURLShortenerServiceServer) GetURLStats(context.Context, *GetURLStatsRequest) (*GetURLStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURLStats not implemented")
+}
+func (UnimplementedURLShortenerServiceServer) DeleteURL(context.Context, *DeleteURLRequest) (*DeleteURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteURL not implemented")
+}
+func (UnimplementedURLShortenerServiceServer) ListClicks(context.Context, *ListClicksRequest) (*ListClicksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClicks not implemented")
+}
+func (UnimplementedURLShortenerServiceServer) StreamClicks(*StreamClicksRequest, grpc.ServerStreamingServer[StreamClicksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamClicks not implemented")
+}
+func (UnimplementedURLShortenerServiceServer) mustEmbedUnimplementedURLShortenerServiceServer() {}
+func (UnimplementedURLShortenerServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeURLShortenerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to URLShortenerServiceServer will
+// result in compilation errors.
+type UnsafeURLShortenerServiceServer interface {
+	mustEmbedUnimplementedURLShortenerServiceServer()
+}
+
+func RegisterURLShortenerServiceServer(s grpc.ServiceRegistrar, srv URLShortenerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedURLShortenerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&URLShortenerService_ServiceDesc, srv)
+}
+
+func _URLShortenerService_CreateShortURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateShortURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServiceServer).CreateShortURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortenerService_CreateShortURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServiceServer).CreateShortURL(ctx, req.(*CreateShortURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortenerService_GetURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServiceServer).GetURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortenerService_GetURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServiceServer).GetURL(ctx, req.(*GetURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortenerService_GetURLStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServiceServer).GetURLStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortenerService_GetURLStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServiceServer).GetURLStats(ctx, req.(*GetURLStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortenerService_DeleteURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServiceServer).DeleteURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortenerService_DeleteURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServiceServer).DeleteURL(ctx, req.(*DeleteURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortenerService_ListClicks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClicksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServiceServer).ListClicks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortenerService_ListClicks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServiceServer).ListClicks(ctx, req.(*ListClicksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortenerService_StreamClicks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamClicksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(URLShortenerServiceServer).StreamClicks(m, &grpc.GenericServerStream[StreamClicksRequest, StreamClicksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortenerService_StreamClicksServer = grpc.ServerStreamingServer[StreamClicksResponse]
+
+// URLShortenerService_ServiceDesc is the grpc.ServiceDesc for URLShortenerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var URLShortenerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shortener.v1.URLShortenerService",
+	HandlerType: (*URLShortenerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateShortURL",
+			Handler:    _URLShortenerService_CreateShortURL_Handler,
+		},
+		{
+			MethodName: "GetURL",
+			Handler:    _URLShortenerService_GetURL_Handler,
+		},
+		{
+			MethodName: "GetURLStats",
+			Handler:    _URLShortenerService_GetURLStats_Handler,
+		},
+		{
+			MethodName: "DeleteURL",
+			Handler:    _URLShortenerService_DeleteURL_Handler,
+		},
+		{
+			MethodName: "ListClicks",
+			Handler:    _URLShortenerService_ListClicks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamClicks",
+			Handler:       _URLShortenerService_StreamClicks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "shortener/v1/shortener.proto",
+}
@@ -0,0 +1,69 @@
+// Command import nhập link từ file export của shortener khác, giữ nguyên short code.
+//
+//	go run ./cmd/import -file links.csv            # dry-run, chỉ in báo cáo
+//	go run ./cmd/import -file links.csv -apply     # ghi vào database
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/infrastructure/repositories"
+	"github.com/url-shorted2/internal/usecases"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func main() {
+	file := flag.String("file", "", "path to the CSV or JSON export file")
+	format := flag.String("format", "", "csv or json (detected from content when empty)")
+	apply := flag.Bool("apply", false, "write links to the database instead of a dry-run")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+
+	db, err := gorm.Open(sqlite.Open(cfg.Database.Path), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Failed to open database:", err)
+	}
+	if err := db.AutoMigrate(&entities.URL{}, &entities.Tag{}, &entities.Folder{}); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal("Failed to open import file:", err)
+	}
+	defer f.Close()
+
+	request := entities.ImportURLsRequest{Format: *format, Mode: entities.ImportModeDryRun}
+	if *apply {
+		request.Mode = entities.ImportModeApply
+	}
+
+	urlUsecase := usecases.NewURLUsecase(repositories.NewURLRepositoryImpl(db), repositories.NewWorkspaceRepositoryImpl(db), cfg.Server.BaseURL, cfg, nil)
+	report, err := urlUsecase.ImportURLs(usecases.WithSystemCaller(context.Background()), f, request)
+	if err != nil {
+		log.Fatal("Import failed:", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("Failed to write report:", err)
+	}
+	fmt.Fprintf(os.Stderr, "%s: %d rows, %d valid, %d created, %d invalid, %d conflicts\n",
+		report.Mode, report.Total, report.Valid, report.Created, report.Invalid, report.Conflicts)
+}
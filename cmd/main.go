@@ -1,33 +1,55 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 
 	"github.com/url-shorted2/internal/config"
 	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/infrastructure/grpcserver"
 	"github.com/url-shorted2/internal/infrastructure/handlers"
 	"github.com/url-shorted2/internal/infrastructure/middleware"
 	"github.com/url-shorted2/internal/infrastructure/repositories"
 	"github.com/url-shorted2/internal/infrastructure/routes"
 	"github.com/url-shorted2/internal/usecases"
+	"github.com/url-shorted2/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	// Khởi tạo tracing, flush span còn lại khi tắt
+	shutdownTracing, err := utils.InitTracing(
+		context.Background(),
+		cfg.Tracing.Exporter,
+		cfg.Tracing.OTLPEndpoint,
+		cfg.Tracing.OTLPInsecure,
+		cfg.Tracing.ServiceName,
+		cfg.Tracing.SampleRatio,
+	)
+	if err != nil {
+		log.Fatal("Failed to initialize tracing:", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Set Gin mode
 	gin.SetMode(cfg.Server.GinMode)
 
 	// Khởi tạo Gin router
 	router := gin.New()
 
-	// Thêm middleware
+	// Thêm middleware (otelgin tạo span cho mỗi request và đọc header traceparent)
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
 	router.Use(middleware.LoggerMiddleware())
+	router.Use(middleware.MetricsMiddleware())
 	router.Use(gin.Recovery())
 	router.Use(middleware.CORSMiddleware())
 
@@ -40,15 +62,54 @@ func main() {
 	// Khởi tạo dependencies theo Clean Architecture
 	// 1. Infrastructure layer (repositories)
 	urlRepo := repositories.NewURLRepositoryImpl(db)
+	webhookRepo := repositories.NewWebhookRepositoryImpl(db)
+	tagRepo := repositories.NewTagRepositoryImpl(db)
+	folderRepo := repositories.NewFolderRepositoryImpl(db)
+	apiKeyRepo := repositories.NewAPIKeyRepositoryImpl(db)
+	userRepo := repositories.NewUserRepositoryImpl(db)
+	workspaceRepo := repositories.NewWorkspaceRepositoryImpl(db)
 
 	// 2. Use case layer
-	urlUsecase := usecases.NewURLUsecase(urlRepo, cfg.Server.BaseURL, cfg)
+	// Job nền chạy với quyền hệ thống, không qua xác thực API key
+	systemCtx := usecases.WithSystemCaller(context.Background())
+
+	// Webhook worker chạy nền: gửi event trong hàng đợi delivery, retry với backoff
+	webhookUsecase := usecases.NewWebhookUsecase(webhookRepo, cfg)
+	go webhookUsecase.Start(systemCtx)
+
+	urlUsecase := usecases.NewURLUsecase(urlRepo, workspaceRepo, cfg.Server.BaseURL, cfg, webhookUsecase)
+	tagUsecase := usecases.NewTagUsecase(tagRepo, urlRepo, workspaceRepo, cfg.Server.BaseURL)
+	folderUsecase := usecases.NewFolderUsecase(folderRepo, tagRepo, urlRepo, workspaceRepo)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(apiKeyRepo, userRepo, cfg.Admin.Token)
+	userUsecase := usecases.NewUserUsecase(userRepo)
+	workspaceUsecase := usecases.NewWorkspaceUsecase(workspaceRepo, userRepo)
+
+	// Rollup job chạy nền: gom click thô vào bảng rollup và xóa click quá retention
+	rollupUsecase := usecases.NewAnalyticsRollupUsecase(urlRepo, cfg)
+	go rollupUsecase.Start(systemCtx)
+
+	// Metadata worker chạy nền: lấy title, favicon, Open Graph của trang đích cho link mới
+	if cfg.Metadata.Enabled {
+		metadataUsecase := usecases.NewMetadataUsecase(urlRepo, cfg)
+		go metadataUsecase.Start(systemCtx)
+	}
+
+	// gRPC API chạy song song REST trên port riêng, dùng chung usecase
+	if cfg.GRPC.Port != "" {
+		go serveGRPC(cfg.GRPC.Port, urlUsecase, apiKeyUsecase)
+	}
 
 	// 3. Infrastructure layer (handlers)
 	urlHandler := handlers.NewURLHandler(urlUsecase)
+	webhookHandler := handlers.NewWebhookHandler(webhookUsecase)
+	tagHandler := handlers.NewTagHandler(tagUsecase)
+	folderHandler := handlers.NewFolderHandler(folderUsecase)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyUsecase)
+	userHandler := handlers.NewUserHandler(userUsecase)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceUsecase)
 
 	// 4. Setup routes
-	routes.SetupRoutes(router, urlHandler)
+	routes.SetupRoutes(router, urlHandler, webhookHandler, tagHandler, folderHandler, apiKeyHandler, userHandler, workspaceHandler, apiKeyUsecase, cfg)
 
 	// Khởi động server
 	log.Printf("Server starting on port %s", cfg.Server.Port)
@@ -57,6 +118,19 @@ func main() {
 	}
 }
 
+// serveGRPC chạy gRPC server trên port riêng
+func serveGRPC(port string, urlUsecase usecases.IURLUsecase, apiKeyUsecase usecases.IAPIKeyUsecase) {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC:", err)
+	}
+
+	log.Printf("gRPC server starting on port %s", port)
+	if err := grpcserver.NewServer(urlUsecase, apiKeyUsecase).Serve(lis); err != nil {
+		log.Fatal("Failed to start gRPC server:", err)
+	}
+}
+
 // initDatabase khởi tạo database và migrate schema
 func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 	// Sử dụng SQLite cho demo, có thể thay bằng PostgreSQL/MySQL
@@ -68,6 +142,11 @@ func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// Tạo span cho mỗi query
+	if err := db.Use(gormtracing.NewPlugin(gormtracing.WithoutMetrics())); err != nil {
+		return nil, err
+	}
+
 	// Cấu hình connection pool cho SQLite
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -79,10 +158,26 @@ func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
 
+	// Expose connection pool stats qua /metrics
+	if err := utils.RegisterDBStats(sqlDB, "main"); err != nil {
+		return nil, err
+	}
+
 	// Auto migrate schema
 	err = db.AutoMigrate(
 		&entities.URL{},
 		&entities.Analytics{},
+		&entities.HourlyRollup{},
+		&entities.DailyRollup{},
+		&entities.RollupState{},
+		&entities.Webhook{},
+		&entities.WebhookDelivery{},
+		&entities.Tag{},
+		&entities.Folder{},
+		&entities.APIKey{},
+		&entities.User{},
+		&entities.Workspace{},
+		&entities.WorkspaceMember{},
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,15 @@
+// Package docs chứa OpenAPI document và trang tài liệu API, được nhúng vào binary.
+package docs
+
+import _ "embed"
+
+// OpenAPI là OpenAPI 3 document của mọi route trong routes.SetupRoutes.
+// Khi thêm hoặc đổi route phải cập nhật openapi.json; tests/openapi_test.go sẽ báo nếu lệch.
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// UI là trang HTML đọc OpenAPI document và hiển thị tài liệu, không cần tải file từ CDN
+//
+//go:embed index.html
+var UI []byte
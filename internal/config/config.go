@@ -3,15 +3,25 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config chứa tất cả cấu hình của ứng dụng
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Lock     LockConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Lock      LockConfig
+	Analytics AnalyticsConfig
+	Privacy   PrivacyConfig
+	Admin     AdminConfig
+	Webhook   WebhookConfig
+	Tracing   TracingConfig
+	Links     LinksConfig
+	QR        QRConfig
+	GRPC      GRPCConfig
+	Metadata  MetadataConfig
 }
 
 // ServerConfig cấu hình server
@@ -48,6 +58,86 @@ type LockConfig struct {
 	MaxTryTime time.Duration
 }
 
+// AnalyticsConfig cấu hình analytics
+type AnalyticsConfig struct {
+	// VisitorDayTTL là thời gian giữ HyperLogLog theo ngày, cũng là khoảng tính unique_visitors
+	VisitorDayTTL time.Duration
+	// RawRetention là thời gian giữ click thô, 0 nghĩa là giữ vĩnh viễn
+	RawRetention   time.Duration
+	RollupInterval time.Duration
+}
+
+// PrivacyConfig cấu hình lưu trữ dữ liệu cá nhân của click
+type PrivacyConfig struct {
+	// IPMode là full, truncate (/24 với IPv4, /48 với IPv6) hoặc hash
+	IPMode       string
+	IPHashSecret string
+	SaltRotation time.Duration
+	// HonorDNT bỏ qua dữ liệu định danh khi request có DNT: 1 hoặc Sec-GPC: 1
+	HonorDNT bool
+}
+
+// AdminConfig cấu hình các endpoint quản trị
+type AdminConfig struct {
+	// Token là bearer token có scope admin không lưu trong DB, dùng để tạo API key đầu tiên; để trống để tắt
+	Token string
+}
+
+// WebhookConfig cấu hình gửi webhook
+type WebhookConfig struct {
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// ClickMilestones là các mốc click_count phát event url.click_milestone
+	ClickMilestones []int64
+}
+
+// TracingConfig cấu hình OpenTelemetry tracing
+type TracingConfig struct {
+	// Exporter là none, stdout hoặc otlp
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	ServiceName  string
+	SampleRatio  float64
+}
+
+// LinksConfig cấu hình tạo link
+type LinksConfig struct {
+	// BatchMaxItems là số item tối đa của POST /api/v1/urls/batch
+	BatchMaxItems int
+	// ImportMaxRows là số dòng tối đa của một file import
+	ImportMaxRows int
+}
+
+// QRConfig cấu hình ảnh QR code
+type QRConfig struct {
+	// LogoPath là file PNG/JPEG đặt giữa QR code khi request có logo=true, rỗng để tắt
+	LogoPath string
+	// CacheMaxAge là max-age (giây) của header Cache-Control
+	CacheMaxAge int
+}
+
+// GRPCConfig cấu hình gRPC API
+type GRPCConfig struct {
+	// Port là port của gRPC server, rỗng để tắt
+	Port string
+}
+
+// MetadataConfig cấu hình lấy title, favicon, Open Graph của URL đích
+type MetadataConfig struct {
+	// Enabled bật fetch metadata cho link mới tạo
+	Enabled      bool
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// MaxBytes là số byte HTML tối đa được đọc, phần sau bị bỏ qua
+	MaxBytes int64
+	// AllowPrivateHosts cho phép fetch IP loopback/private (chỉ dùng khi dev, test)
+	AllowPrivateHosts bool
+}
+
 // LoadConfig load cấu hình từ environment variables
 func LoadConfig() *Config {
 	return &Config{
@@ -77,6 +167,53 @@ func LoadConfig() *Config {
 			MaxTime:    time.Duration(getEnvAsInt("LOCK_MAX_TIME", 30)) * time.Second,
 			MaxTryTime: time.Duration(getEnvAsInt("LOCK_MAX_TRY_TIME", 10)) * time.Second,
 		},
+		Analytics: AnalyticsConfig{
+			VisitorDayTTL:  time.Duration(getEnvAsInt("ANALYTICS_VISITOR_DAY_TTL_DAYS", 90)) * 24 * time.Hour,
+			RawRetention:   time.Duration(getEnvAsInt("ANALYTICS_RAW_RETENTION_DAYS", 0)) * 24 * time.Hour,
+			RollupInterval: time.Duration(getEnvAsInt("ANALYTICS_ROLLUP_INTERVAL_MINUTES", 60)) * time.Minute,
+		},
+		Privacy: PrivacyConfig{
+			IPMode:       getEnv("PRIVACY_IP_MODE", "truncate"),
+			IPHashSecret: getEnv("PRIVACY_IP_HASH_SECRET", ""),
+			SaltRotation: time.Duration(getEnvAsInt("PRIVACY_SALT_ROTATION_HOURS", 24)) * time.Hour,
+			HonorDNT:     getEnvAsBool("PRIVACY_HONOR_DNT", true),
+		},
+		Admin: AdminConfig{
+			Token: getEnv("ADMIN_TOKEN", ""),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:     getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			BackoffBase:     time.Duration(getEnvAsInt("WEBHOOK_BACKOFF_BASE_SECONDS", 30)) * time.Second,
+			BackoffMax:      time.Duration(getEnvAsInt("WEBHOOK_BACKOFF_MAX_SECONDS", 3600)) * time.Second,
+			Timeout:         time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+			PollInterval:    time.Duration(getEnvAsInt("WEBHOOK_POLL_INTERVAL_SECONDS", 5)) * time.Second,
+			ClickMilestones: getEnvAsInt64Slice("WEBHOOK_CLICK_MILESTONES", []int64{100, 1000, 10000, 100000}),
+		},
+		Links: LinksConfig{
+			BatchMaxItems: getEnvAsInt("LINKS_BATCH_MAX_ITEMS", 500),
+			ImportMaxRows: getEnvAsInt("LINKS_IMPORT_MAX_ROWS", 100000),
+		},
+		GRPC: GRPCConfig{
+			Port: getEnv("GRPC_PORT", "9090"),
+		},
+		Metadata: MetadataConfig{
+			Enabled:           getEnvAsBool("METADATA_FETCH_ENABLED", true),
+			Timeout:           time.Duration(getEnvAsInt("METADATA_TIMEOUT_SECONDS", 5)) * time.Second,
+			PollInterval:      time.Duration(getEnvAsInt("METADATA_POLL_INTERVAL_SECONDS", 5)) * time.Second,
+			MaxBytes:          int64(getEnvAsInt("METADATA_MAX_BYTES", 512*1024)),
+			AllowPrivateHosts: getEnvAsBool("METADATA_ALLOW_PRIVATE_HOSTS", false),
+		},
+		QR: QRConfig{
+			LogoPath:    getEnv("QR_LOGO_PATH", ""),
+			CacheMaxAge: getEnvAsInt("QR_CACHE_MAX_AGE_SECONDS", 86400),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getEnv("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			OTLPInsecure: getEnvAsBool("TRACING_OTLP_INSECURE", true),
+			ServiceName:  getEnv("TRACING_SERVICE_NAME", "url-shortener"),
+			SampleRatio:  getEnvAsFloat("TRACING_SAMPLE_RATIO", 1.0),
+		},
 	}
 }
 
@@ -97,3 +234,41 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsBool lấy environment variable dưới dạng bool với default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsFloat lấy environment variable dạng float
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsInt64Slice lấy environment variable dạng danh sách số phân tách bởi dấu phẩy
+func getEnvAsInt64Slice(key string, defaultValue []int64) []int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var values []int64
+	for _, part := range strings.Split(value, ",") {
+		intValue, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return defaultValue
+		}
+		values = append(values, intValue)
+	}
+	return values
+}
@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// Các scope của API key
+const (
+	ScopeLinksRead  = "links:read"
+	ScopeLinksWrite = "links:write"
+	ScopeStatsRead  = "stats:read"
+	// ScopeAdmin bao gồm mọi scope khác và các endpoint /api/v1/admin
+	ScopeAdmin = "admin"
+)
+
+// APIKey là key truy cập API. Chỉ lưu SHA-256 của key; key gốc chỉ trả về lúc tạo hoặc rotate.
+type APIKey struct {
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"not null;size:100"`
+	// UserID là user sở hữu key; link tạo bằng key thuộc về user này
+	UserID *uint `json:"user_id,omitempty" gorm:"index"`
+	// Prefix là phần đầu của key để nhận diện trong danh sách
+	Prefix     string     `json:"prefix" gorm:"not null;size:16"`
+	KeyHash    string     `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	Scopes     string     `json:"-" gorm:"not null;size:255"` // Danh sách scope, phân tách bởi dấu phẩy
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ScopeList tách Scopes thành danh sách
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// HasScope cho biết key có scope, scope admin bao gồm mọi scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKeyRequest là body tạo API key.
+// UserID bắt buộc với key không có scope admin.
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	UserID *uint    `json:"user_id"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+// APIKeyResponse là API key trả về qua API; Key chỉ có giá trị khi tạo hoặc rotate
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	UserID     *uint      `json:"user_id,omitempty"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
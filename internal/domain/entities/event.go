@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// ClickEvent là sự kiện click được publish cho live stream (không chứa IP và User-Agent)
+type ClickEvent struct {
+	ID              uint      `json:"id"`
+	URLID           uint      `json:"url_id"`
+	ShortCode       string    `json:"short_code"`
+	ClickedAt       time.Time `json:"clicked_at"`
+	Country         string    `json:"country,omitempty"`
+	ReferrerHost    string    `json:"referrer_host,omitempty"`
+	ReferrerChannel string    `json:"referrer_channel,omitempty"`
+	Browser         string    `json:"browser,omitempty"`
+	OS              string    `json:"os,omitempty"`
+	DeviceType      string    `json:"device_type,omitempty"`
+}
@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Folder là thư mục chứa URL; ParentID nil là folder gốc. Mỗi URL nằm trong tối đa một folder.
+type Folder struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null;size:100"`
+	ParentID  *uint     `json:"parent_id" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FolderRequest là body tạo hoặc cập nhật folder. Khi cập nhật, parent_id null đưa folder về gốc.
+type FolderRequest struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// FolderResponse là folder kèm đường dẫn đầy đủ và số URL nằm trực tiếp trong folder
+type FolderResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	ParentID  *uint     `json:"parent_id"`
+	Path      string    `json:"path"`
+	URLCount  int64     `json:"url_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FolderListResponse là danh sách tất cả folder, sắp xếp theo path
+type FolderListResponse struct {
+	Folders []FolderResponse `json:"folders"`
+}
+
+// SetURLFolderRequest là body chuyển URL vào folder; folder_id null đưa URL ra khỏi folder
+type SetURLFolderRequest struct {
+	FolderID *uint `json:"folder_id"`
+}
@@ -0,0 +1,47 @@
+package entities
+
+// Các định dạng file import link
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+// Các chế độ import: dry-run chỉ kiểm tra, apply mới ghi vào database
+const (
+	ImportModeDryRun = "dry-run"
+	ImportModeApply  = "apply"
+)
+
+// Trạng thái của một dòng không được import
+const (
+	ImportRowInvalid  = "invalid"
+	ImportRowConflict = "conflict"
+)
+
+// ImportURLsRequest là query params của POST /api/v1/admin/urls/import
+type ImportURLsRequest struct {
+	Format string `form:"format"`
+	Mode   string `form:"mode"`
+}
+
+// ImportRowIssue mô tả một dòng bị bỏ qua và lý do
+type ImportRowIssue struct {
+	Row         int    `json:"row"`
+	Code        string `json:"code,omitempty"`
+	OriginalURL string `json:"original_url,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error"`
+}
+
+// ImportReport là báo cáo kết quả import. Ở chế độ dry-run, Created luôn bằng 0
+// và Valid là số link sẽ được tạo khi apply.
+type ImportReport struct {
+	Mode      string           `json:"mode"`
+	Format    string           `json:"format"`
+	Total     int              `json:"total"`
+	Valid     int              `json:"valid"`
+	Created   int              `json:"created"`
+	Invalid   int              `json:"invalid"`
+	Conflicts int              `json:"conflicts"`
+	Issues    []ImportRowIssue `json:"issues"`
+}
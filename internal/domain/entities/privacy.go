@@ -0,0 +1,14 @@
+package entities
+
+// EraseAnalyticsRequest represents the request to erase click analytics of a data subject.
+// Cần ít nhất IPAddress hoặc Fingerprint; UserAgent đi kèm IPAddress để xóa theo fingerprint.
+type EraseAnalyticsRequest struct {
+	IPAddress   string `json:"ip_address"`
+	UserAgent   string `json:"user_agent"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// EraseAnalyticsResponse represents the result of an erasure
+type EraseAnalyticsResponse struct {
+	DeletedClicks int64 `json:"deleted_clicks"`
+}
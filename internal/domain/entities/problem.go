@@ -0,0 +1,33 @@
+package entities
+
+import "net/http"
+
+// ProblemContentType là media type của body lỗi theo RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// Code lỗi xác thực, trả về từ middleware trước khi tới usecase
+const (
+	ProblemCodeUnauthorized      = "unauthorized"
+	ProblemCodeInsufficientScope = "insufficient_scope"
+)
+
+// Problem là body lỗi theo RFC 7807 (problem details) kèm Code ổn định để client xử lý theo máy
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// NewProblem tạo Problem với type about:blank, title là reason phrase của status
+func NewProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
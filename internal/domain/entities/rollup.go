@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// ClickRollup là số click đã gom theo bucket thời gian (UTC) và các dimension của link
+type ClickRollup struct {
+	URLID           uint      `json:"url_id" gorm:"not null;uniqueIndex:,composite:rollup_key,priority:1"`
+	BucketStart     time.Time `json:"bucket_start" gorm:"not null;index;uniqueIndex:,composite:rollup_key,priority:2"`
+	Country         string    `json:"country" gorm:"size:2;uniqueIndex:,composite:rollup_key,priority:3"`
+	ReferrerHost    string    `json:"referrer_host" gorm:"size:255;uniqueIndex:,composite:rollup_key,priority:4"`
+	ReferrerChannel string    `json:"referrer_channel" gorm:"size:20;uniqueIndex:,composite:rollup_key,priority:5"`
+	DeviceType      string    `json:"device_type" gorm:"size:20;uniqueIndex:,composite:rollup_key,priority:6"`
+	Browser         string    `json:"browser" gorm:"size:50;uniqueIndex:,composite:rollup_key,priority:7"`
+	OS              string    `json:"os" gorm:"size:50;uniqueIndex:,composite:rollup_key,priority:8"`
+	Clicks          int64     `json:"clicks" gorm:"not null"`
+}
+
+// HourlyRollup là rollup theo giờ, dùng cho time series của khoảng thời gian cũ
+type HourlyRollup struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+	ClickRollup
+}
+
+// TableName trả về tên bảng rollup theo giờ
+func (HourlyRollup) TableName() string {
+	return "analytics_hourly"
+}
+
+// DailyRollup là rollup theo ngày, dùng cho các breakdown của khoảng thời gian cũ
+type DailyRollup struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+	ClickRollup
+}
+
+// TableName trả về tên bảng rollup theo ngày
+func (DailyRollup) TableName() string {
+	return "analytics_daily"
+}
+
+// RollupState lưu mốc mà click thô trước đó đã được rollup, theo từng granularity
+type RollupState struct {
+	Granularity string    `json:"granularity" gorm:"primaryKey;size:10"`
+	RolledUntil time.Time `json:"rolled_until" gorm:"not null"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName trả về tên bảng lưu trạng thái rollup
+func (RollupState) TableName() string {
+	return "analytics_rollup_states"
+}
+
+// RollupResult là kết quả một lần chạy rollup job
+type RollupResult struct {
+	HourlyRows int64
+	DailyRows  int64
+	PrunedRows int64
+}
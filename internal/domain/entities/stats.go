@@ -0,0 +1,120 @@
+package entities
+
+import "time"
+
+// Các interval hỗ trợ cho thống kê theo thời gian
+const (
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+	IntervalWeek = "week"
+)
+
+// Các dimension hỗ trợ breakdown, giá trị là tên cột trong bảng analytics
+const (
+	DimensionBrowser = "browser"
+	DimensionOS      = "os"
+	DimensionDevice  = "device_type"
+	DimensionCountry = "country"
+
+	DimensionReferrerHost    = "referrer_host"
+	DimensionReferrerChannel = "referrer_channel"
+)
+
+// DimensionCount là số click ứng với một giá trị của dimension
+type DimensionCount struct {
+	Value  string `json:"value"`
+	Clicks int64  `json:"clicks"`
+}
+
+// ReferrerStatsRequest là query params của GET /api/v1/urls/:shortCode/stats/referrers
+type ReferrerStatsRequest struct {
+	Limit int `form:"limit"`
+}
+
+// ReferrerCount là số click từ một referrer host
+type ReferrerCount struct {
+	Host    string `json:"host"`
+	Channel string `json:"channel"`
+	Clicks  int64  `json:"clicks"`
+}
+
+// ReferrerStatsResponse represents click counts grouped by referrer host and channel
+type ReferrerStatsResponse struct {
+	ShortCode   string           `json:"short_code"`
+	TotalClicks int64            `json:"total_clicks"`
+	Channels    []DimensionCount `json:"channels"`
+	Referrers   []ReferrerCount  `json:"referrers"`
+}
+
+// ClickListRequest là query params của GET /api/v1/urls/:shortCode/clicks
+type ClickListRequest struct {
+	Cursor   string `form:"cursor"`
+	Limit    int    `form:"limit"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Country  string `form:"country"`
+	Referrer string `form:"referrer"`
+}
+
+// Các định dạng export click
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// ClickExportRequest là query params của GET /api/v1/urls/:shortCode/clicks/export
+type ClickExportRequest struct {
+	Format   string `form:"format"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Country  string `form:"country"`
+	Referrer string `form:"referrer"`
+}
+
+// ClickFilter là điều kiện lọc analytics ở tầng repository.
+// Time zero nghĩa là không giới hạn; BeforeID là keyset cursor (lấy các id nhỏ hơn),
+// AfterID dùng để replay các click sau một event id (lấy các id lớn hơn).
+// Kết quả xếp theo id giảm dần, Ascending đổi sang id tăng dần.
+type ClickFilter struct {
+	From         time.Time
+	To           time.Time
+	Country      string
+	ReferrerHost string
+	BeforeID     uint
+	AfterID      uint
+	Ascending    bool
+	Limit        int
+}
+
+// ClickListResponse represents a page of click history, newest first
+type ClickListResponse struct {
+	ShortCode  string      `json:"short_code"`
+	Clicks     []Analytics `json:"clicks"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasMore    bool        `json:"has_more"`
+}
+
+// TimeSeriesRequest là query params của GET /api/v1/urls/:shortCode/stats/timeseries
+type TimeSeriesRequest struct {
+	Interval string `form:"interval"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+	TZ       string `form:"tz"`
+}
+
+// TimeSeriesBucket là số click trong một bucket thời gian
+type TimeSeriesBucket struct {
+	Start  time.Time `json:"start"`
+	Clicks int64     `json:"clicks"`
+}
+
+// TimeSeriesResponse represents click counts grouped by time bucket
+type TimeSeriesResponse struct {
+	ShortCode   string             `json:"short_code"`
+	Interval    string             `json:"interval"`
+	Timezone    string             `json:"timezone"`
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	TotalClicks int64              `json:"total_clicks"`
+	Buckets     []TimeSeriesBucket `json:"buckets"`
+}
@@ -0,0 +1,76 @@
+package entities
+
+import "time"
+
+// Tag là nhãn gắn cho URL (quan hệ nhiều-nhiều qua bảng url_tags), ví dụ campaign:black-friday.
+// Name luôn được chuẩn hóa về chữ thường.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;not null;size:64"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagRequest là body tạo hoặc đổi tên tag
+type TagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// TagResponse là tag kèm số URL đang gắn tag
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	URLCount  int64     `json:"url_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagListResponse là danh sách tất cả tag
+type TagListResponse struct {
+	Tags []TagResponse `json:"tags"`
+}
+
+// TagStats là số liệu tổng hợp của mọi URL gắn một tag, tính ở tầng repository
+type TagStats struct {
+	URLCount       int64
+	ActiveURLCount int64
+	TotalClicks    int64
+	LastClicked    *time.Time
+}
+
+// TagStatsRequest là query params của GET /api/v1/tags/:tag/stats
+type TagStatsRequest struct {
+	WorkspaceID *uint `form:"workspace_id"`
+}
+
+// URLScope giới hạn tập URL tính trong thống kê tổng hợp; field nil nghĩa là không giới hạn
+type URLScope struct {
+	OwnerID     *uint
+	WorkspaceID *uint // 0 nghĩa là link cá nhân, không thuộc workspace nào
+}
+
+// IsAll cho biết scope không giới hạn URL nào
+func (s URLScope) IsAll() bool {
+	return s.OwnerID == nil && s.WorkspaceID == nil
+}
+
+// TagStatsResponse là thống kê tổng hợp trên mọi URL gắn tag
+type TagStatsResponse struct {
+	Tag            string        `json:"tag"`
+	URLCount       int64         `json:"url_count"`
+	ActiveURLCount int64         `json:"active_url_count"`
+	TotalClicks    int64         `json:"total_clicks"`
+	UniqueVisitors int64         `json:"unique_visitors"` // Gần đúng, theo fingerprint của click còn trong retention
+	LastClicked    *time.Time    `json:"last_clicked,omitempty"`
+	TopURLs        []URLListItem `json:"top_urls"`
+}
+
+// SetURLTagsRequest là body thay toàn bộ tag của một URL; tag chưa có được tạo mới
+type SetURLTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// URLOrganizationResponse là folder và tag hiện tại của một URL
+type URLOrganizationResponse struct {
+	ShortCode string   `json:"short_code"`
+	FolderID  *uint    `json:"folder_id"`
+	Tags      []string `json:"tags"`
+}
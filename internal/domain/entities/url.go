@@ -11,28 +11,134 @@ type URL struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	IsActive    bool      `json:"is_active" gorm:"default:true"`
 	ClickCount  int64     `json:"click_count" gorm:"default:0"`
+	FolderID    *uint     `json:"folder_id,omitempty" gorm:"index"`
+	// OwnerID là user tạo link; nil với link tạo bằng ADMIN_TOKEN hoặc trước khi có user
+	OwnerID *uint `json:"owner_id,omitempty" gorm:"index"`
+	// WorkspaceID là workspace chứa link; nil là link cá nhân của owner
+	WorkspaceID *uint `json:"workspace_id,omitempty" gorm:"index"`
+	// RedirectType là HTTP status khi redirect (301, 302, 307, 308)
+	RedirectType int `json:"redirect_type" gorm:"not null;default:301"`
+
+	// Metadata của trang đích (title, favicon, Open Graph), lấy nền sau khi tạo link
+	Metadata URLMetadata `json:"metadata" gorm:"embedded;embeddedPrefix:meta_"`
+
+	// Preview là social preview tùy chỉnh, trả cho bot unfurl thay vì redirect
+	Preview URLPreview `json:"preview" gorm:"embedded;embeddedPrefix:preview_"`
+
+	// Tag relationship (bảng nối url_tags)
+	Tags []Tag `json:"tags,omitempty" gorm:"many2many:url_tags;"`
 
 	// Analytics relationship
 	Analytics []Analytics `json:"analytics,omitempty" gorm:"foreignKey:URLID"`
 }
 
+// RedirectStatus trả về HTTP status khi redirect; link cũ chưa có redirect type dùng 301
+func (u *URL) RedirectStatus() int {
+	if u.RedirectType == 0 {
+		return DefaultRedirectType
+	}
+	return u.RedirectType
+}
+
+// Trạng thái fetch metadata của URL đích. Rỗng nghĩa là không fetch (link import, tính năng tắt).
+const (
+	MetadataStatusPending = "pending"
+	MetadataStatusFetched = "fetched"
+	MetadataStatusFailed  = "failed"
+)
+
+// URLMetadata là thông tin preview của trang đích, lưu cùng bảng urls với prefix meta_
+type URLMetadata struct {
+	Status      string     `json:"status" gorm:"size:10;index"`
+	Title       string     `json:"title,omitempty" gorm:"size:300"`
+	Description string     `json:"description,omitempty" gorm:"size:1000"`
+	ImageURL    string     `json:"image_url,omitempty" gorm:"size:2048"`
+	SiteName    string     `json:"site_name,omitempty" gorm:"size:200"`
+	FaviconURL  string     `json:"favicon_url,omitempty" gorm:"size:2048"`
+	Error       string     `json:"error,omitempty" gorm:"size:500"`
+	FetchedAt   *time.Time `json:"fetched_at,omitempty"`
+}
+
+// URLPreview là title, description, ảnh do người dùng đặt cho social preview của short link.
+// Field rỗng được lấy từ metadata của trang đích.
+type URLPreview struct {
+	Title       string `json:"title,omitempty" gorm:"size:300"`
+	Description string `json:"description,omitempty" gorm:"size:1000"`
+	ImageURL    string `json:"image_url,omitempty" gorm:"size:2048"`
+}
+
+// IsEmpty cho biết link chưa có social preview tùy chỉnh
+func (p URLPreview) IsEmpty() bool {
+	return p.Title == "" && p.Description == "" && p.ImageURL == ""
+}
+
+// URLPreviewRequest là body của PUT /api/v1/urls/:shortCode/preview; mọi field rỗng là xóa preview
+type URLPreviewRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url"`
+}
+
 // Analytics represents click analytics for a URL
 type Analytics struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
-	URLID     uint      `json:"url_id" gorm:"not null;index"`
+	URLID     uint      `json:"url_id" gorm:"not null;index;index:idx_analytics_url_clicked,priority:1"`
 	IPAddress string    `json:"ip_address" gorm:"size:45"` // IPv6 compatible
 	UserAgent string    `json:"user_agent" gorm:"size:500"`
 	Referer   string    `json:"referer" gorm:"size:500"`
 	Country   string    `json:"country" gorm:"size:2"`
 	City      string    `json:"city" gorm:"size:100"`
-	ClickedAt time.Time `json:"clicked_at"`
+	ClickedAt time.Time `json:"clicked_at" gorm:"index;index:idx_analytics_url_clicked,priority:2"`
+
+	// Dimensions parse từ UserAgent
+	Browser        string `json:"browser" gorm:"size:50"`
+	BrowserVersion string `json:"browser_version" gorm:"size:50"`
+	OS             string `json:"os" gorm:"size:50"`
+	DeviceType     string `json:"device_type" gorm:"size:20"`
+
+	// Referer đã chuẩn hóa
+	ReferrerHost    string `json:"referrer_host" gorm:"size:255"`
+	ReferrerChannel string `json:"referrer_channel" gorm:"size:20"`
+
+	// VisitorHash là HMAC của IP + User-Agent với salt theo ngày, dùng đếm unique visitor.
+	// Không trả ra API để không liên kết được click giữa các export.
+	VisitorHash string `json:"-" gorm:"size:64;index"`
 
 	// Relationship
-	URL URL `json:"url,omitempty" gorm:"foreignKey:URLID"`
+	URL *URL `json:"url,omitempty" gorm:"foreignKey:URLID"`
 }
 
 type CreateURLRequest struct {
 	OriginalURL string `json:"url"`
+	// WorkspaceID tạo link trong workspace (cần role editor), áp dụng settings mặc định của workspace
+	WorkspaceID *uint `json:"workspace_id,omitempty"`
+	// RedirectType ghi đè redirect type mặc định của workspace
+	RedirectType int `json:"redirect_type,omitempty"`
+}
+
+// RedirectTarget là URL đích và HTTP status dùng để redirect
+type RedirectTarget struct {
+	URL        string
+	StatusCode int
+}
+
+// BatchCreateURLRequest represents the request to create many short URLs at once
+type BatchCreateURLRequest struct {
+	Items []CreateURLRequest `json:"items" binding:"required,min=1"`
+}
+
+// BatchCreateURLResult là kết quả của một item, theo đúng thứ tự trong request
+type BatchCreateURLResult struct {
+	Index int                `json:"index"`
+	URL   *CreateURLResponse `json:"url,omitempty"`
+	Error string             `json:"error,omitempty"`
+}
+
+// BatchCreateURLResponse represents the per-item results of a batch create
+type BatchCreateURLResponse struct {
+	Created int                    `json:"created"`
+	Failed  int                    `json:"failed"`
+	Results []BatchCreateURLResult `json:"results"`
 }
 
 // CreateURLResponse represents the response after creating a new URL
@@ -45,10 +151,105 @@ type CreateURLResponse struct {
 
 // URLStatsResponse represents analytics data for a URL
 type URLStatsResponse struct {
+	ShortCode      string     `json:"short_code"`
+	OriginalURL    string     `json:"original_url"`
+	TotalClicks    int64      `json:"total_clicks"`
+	UniqueVisitors int64      `json:"unique_visitors"` // Gần đúng, tổng visitor duy nhất của từng ngày UTC trong ANALYTICS_VISITOR_DAY_TTL_DAYS ngày gần nhất
+	CreatedAt      time.Time  `json:"created_at"`
+	LastClicked    *time.Time `json:"last_clicked,omitempty"`
+
+	// Breakdown theo từng dimension
+	Browsers         []DimensionCount `json:"browsers"`
+	OperatingSystems []DimensionCount `json:"operating_systems"`
+	Devices          []DimensionCount `json:"devices"`
+}
+
+// Các kiểu sắp xếp danh sách URL
+const (
+	URLSortCreated = "created"
+	URLSortClicks  = "clicks"
+)
+
+// URLListRequest là query params của GET /api/v1/urls
+type URLListRequest struct {
+	Active      *bool    `form:"active"`
+	CreatedFrom string   `form:"created_from"`
+	CreatedTo   string   `form:"created_to"`
+	Host        string   `form:"host"`
+	Tags        []string `form:"tag"`
+	FolderID    *uint    `form:"folder_id"`
+	Owner       string   `form:"owner"`
+	WorkspaceID *uint    `form:"workspace_id"`
+	Q           string   `form:"q"`
+	Sort        string   `form:"sort"`
+	Order       string   `form:"order"`
+	Cursor      string   `form:"cursor"`
+	Limit       int      `form:"limit"`
+}
+
+// URLCursor là vị trí keyset: giá trị cột sắp xếp và id của URL cuối trang trước
+type URLCursor struct {
+	CreatedAt  time.Time
+	ClickCount int64
+	ID         uint
+}
+
+// URLFilter là điều kiện lọc danh sách URL ở tầng repository.
+// Time zero nghĩa là không giới hạn; After là keyset cursor theo SortBy.
+type URLFilter struct {
+	Active      *bool
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Host        string
+	Tags        []string // URL phải có đủ mọi tag
+	FolderID    *uint    // 0 nghĩa là URL không nằm trong folder nào
+	OwnerID     *uint    // nil nghĩa là mọi owner
+	WorkspaceID *uint    // 0 nghĩa là link cá nhân, không thuộc workspace nào
+	Search      string
+	SortBy      string
+	Ascending   bool
+	After       *URLCursor
+	Limit       int
+}
+
+// URLListItem là một URL trong danh sách
+type URLListItem struct {
+	ID           uint        `json:"id"`
 	ShortCode    string      `json:"short_code"`
+	ShortURL     string      `json:"short_url"`
 	OriginalURL  string      `json:"original_url"`
-	TotalClicks  int64       `json:"total_clicks"`
+	IsActive     bool        `json:"is_active"`
+	ClickCount   int64       `json:"click_count"`
+	FolderID     *uint       `json:"folder_id"`
+	OwnerID      *uint       `json:"owner_id"`
+	WorkspaceID  *uint       `json:"workspace_id"`
+	RedirectType int         `json:"redirect_type"`
+	Tags         []string    `json:"tags"`
+	Metadata     URLMetadata `json:"metadata"`
+	Preview      URLPreview  `json:"preview"`
 	CreatedAt    time.Time   `json:"created_at"`
-	LastClicked  *time.Time  `json:"last_clicked,omitempty"`
-	ClickHistory []Analytics `json:"click_history,omitempty"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// URLListResponse represents a page of URLs
+type URLListResponse struct {
+	URLs       []URLListItem `json:"urls"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	HasMore    bool          `json:"has_more"`
+}
+
+// Các định dạng ảnh QR code
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// QRCodeRequest là query params của GET /api/v1/urls/:shortCode/qr
+type QRCodeRequest struct {
+	Format string `form:"format"`
+	Size   int    `form:"size"`
+	ECC    string `form:"ecc"`
+	FG     string `form:"fg"`
+	BG     string `form:"bg"`
+	Logo   bool   `form:"logo"`
 }
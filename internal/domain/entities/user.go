@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// User là chủ sở hữu link. Request được gắn với user qua API key của user đó.
+type User struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null;size:100"`
+	Email     string    `json:"email" gorm:"uniqueIndex;not null;size:254"` // Luôn chuẩn hóa về chữ thường
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateUserRequest là body tạo user
+type CreateUserRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required"`
+}
+
+// UserListResponse là danh sách tất cả user
+type UserListResponse struct {
+	Users []User `json:"users"`
+}
@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Các loại event gửi qua webhook
+const (
+	WebhookEventURLCreated     = "url.created"
+	WebhookEventURLDeleted     = "url.deleted"
+	WebhookEventClickMilestone = "url.click_milestone"
+)
+
+// Trạng thái của một delivery
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	// DeliveryStatusDead là delivery đã hết số lần retry (dead-letter)
+	DeliveryStatusDead = "dead"
+)
+
+// Webhook là endpoint đăng ký nhận event
+type Webhook struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	URL       string    `json:"url" gorm:"not null;size:2048"`
+	Secret    string    `json:"-" gorm:"not null;size:100"`
+	Events    string    `json:"-" gorm:"not null;size:255"` // Danh sách event, phân tách bởi dấu phẩy
+	IsActive  bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery là một event trong hàng đợi gửi tới webhook, đồng thời là log của lần gửi
+type WebhookDelivery struct {
+	ID             uint            `json:"id" gorm:"primaryKey"`
+	WebhookID      uint            `json:"webhook_id" gorm:"not null;index"`
+	EventID        string          `json:"event_id" gorm:"not null;size:36"`
+	EventType      string          `json:"event_type" gorm:"not null;size:50"`
+	Payload        json.RawMessage `json:"payload" gorm:"type:text;not null"`
+	Status         string          `json:"status" gorm:"not null;size:20;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int             `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" gorm:"index:idx_webhook_deliveries_due,priority:2"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty" gorm:"size:500"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// WebhookEvent là envelope JSON được POST tới webhook
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// CreateWebhookRequest represents the request to register a webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required,min=1"`
+}
+
+// WebhookResponse represents a registered webhook. Secret chỉ trả về khi tạo mới.
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"is_active"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDeliveryListRequest represents query parameters of the delivery log
+type WebhookDeliveryListRequest struct {
+	Status string `form:"status"`
+	Limit  int    `form:"limit"`
+}
+
+// URLEventData là data của event url.created và url.deleted
+type URLEventData struct {
+	ShortCode   string    `json:"short_code"`
+	ShortURL    string    `json:"short_url"`
+	OriginalURL string    `json:"original_url"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// ClickMilestoneEventData là data của event url.click_milestone
+type ClickMilestoneEventData struct {
+	ShortCode   string    `json:"short_code"`
+	ShortURL    string    `json:"short_url"`
+	OriginalURL string    `json:"original_url"`
+	Milestone   int64     `json:"milestone"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,115 @@
+package entities
+
+import "time"
+
+// Role của member trong workspace, xếp từ cao xuống thấp
+const (
+	WorkspaceRoleOwner   = "owner"   // Mọi quyền, quản lý member và settings
+	WorkspaceRoleEditor  = "editor"  // Tạo, sửa, xóa link và xem thống kê
+	WorkspaceRoleAnalyst = "analyst" // Xem link và thống kê
+	WorkspaceRoleViewer  = "viewer"  // Chỉ xem link
+)
+
+// workspaceRoleRanks xếp hạng role; role cao hơn có mọi quyền của role thấp hơn
+var workspaceRoleRanks = map[string]int{
+	WorkspaceRoleViewer:  1,
+	WorkspaceRoleAnalyst: 2,
+	WorkspaceRoleEditor:  3,
+	WorkspaceRoleOwner:   4,
+}
+
+// IsValidWorkspaceRole cho biết role có tồn tại không
+func IsValidWorkspaceRole(role string) bool {
+	_, ok := workspaceRoleRanks[role]
+	return ok
+}
+
+// Redirect type hỗ trợ cho link, mặc định 301
+var RedirectTypes = []int{301, 302, 307, 308}
+
+// DefaultRedirectType dùng cho link và workspace chưa đặt redirect type
+const DefaultRedirectType = 301
+
+// Workspace là nhóm user cùng quản lý link
+type Workspace struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	Name      string            `json:"name" gorm:"not null;size:100"`
+	Settings  WorkspaceSettings `json:"settings" gorm:"embedded;embeddedPrefix:setting_"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// WorkspaceSettings là giá trị mặc định áp dụng cho link mới tạo trong workspace
+type WorkspaceSettings struct {
+	DefaultRedirectType int       `json:"default_redirect_type" gorm:"not null;default:301"`
+	DefaultUTM          UTMParams `json:"default_utm" gorm:"embedded;embeddedPrefix:utm_"`
+}
+
+// UTMParams là các tham số utm_* được thêm vào URL đích nếu URL chưa có
+type UTMParams struct {
+	Source   string `json:"source,omitempty" gorm:"size:100"`
+	Medium   string `json:"medium,omitempty" gorm:"size:100"`
+	Campaign string `json:"campaign,omitempty" gorm:"size:100"`
+	Term     string `json:"term,omitempty" gorm:"size:100"`
+	Content  string `json:"content,omitempty" gorm:"size:100"`
+}
+
+// Query trả về các tham số khác rỗng theo tên query utm_*
+func (p UTMParams) Query() map[string]string {
+	query := map[string]string{}
+	for name, value := range map[string]string{
+		"utm_source":   p.Source,
+		"utm_medium":   p.Medium,
+		"utm_campaign": p.Campaign,
+		"utm_term":     p.Term,
+		"utm_content":  p.Content,
+	} {
+		if value != "" {
+			query[name] = value
+		}
+	}
+	return query
+}
+
+// WorkspaceMember là quan hệ user - workspace kèm role
+type WorkspaceMember struct {
+	WorkspaceID uint      `json:"workspace_id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"primaryKey;index"`
+	Role        string    `json:"role" gorm:"not null;size:20"`
+	User        *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// HasRole cho biết member có role bằng hoặc cao hơn role yêu cầu
+func (m *WorkspaceMember) HasRole(role string) bool {
+	return workspaceRoleRanks[m.Role] >= workspaceRoleRanks[role]
+}
+
+// CreateWorkspaceRequest là body tạo workspace. OwnerID mặc định là user của API key;
+// key có scope admin có thể tạo workspace cho user khác.
+type CreateWorkspaceRequest struct {
+	Name    string `json:"name" binding:"required"`
+	OwnerID *uint  `json:"owner_id"`
+}
+
+// WorkspaceListResponse là danh sách workspace người gọi là member (admin thấy tất cả)
+type WorkspaceListResponse struct {
+	Workspaces []Workspace `json:"workspaces"`
+}
+
+// InviteMemberRequest là body thêm user (theo email) vào workspace
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// UpdateMemberRequest là body đổi role của member
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// WorkspaceMemberListResponse là danh sách member của workspace
+type WorkspaceMemberListResponse struct {
+	Members []WorkspaceMember `json:"members"`
+}
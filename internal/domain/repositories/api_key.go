@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+)
+
+// IAPIKeyRepository định nghĩa interface cho API key repository
+type IAPIKeyRepository interface {
+	// WithContext trả về repository chạy query với ctx (trace, cancel) như gorm.DB.WithContext
+	WithContext(ctx context.Context) IAPIKeyRepository
+	CreateAPIKey(key *entities.APIKey) error
+	GetAPIKey(id uint) (*entities.APIKey, error)
+	GetAPIKeyByHash(keyHash string) (*entities.APIKey, error)
+	ListAPIKeys() ([]entities.APIKey, error)
+	UpdateAPIKey(key *entities.APIKey) error
+	TouchAPIKey(id uint, usedAt time.Time) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/url-shorted2/internal/domain/entities"
+)
+
+// IFolderRepository định nghĩa interface cho folder repository
+type IFolderRepository interface {
+	// WithContext trả về repository chạy query với ctx (trace, cancel) như gorm.DB.WithContext
+	WithContext(ctx context.Context) IFolderRepository
+	CreateFolder(folder *entities.Folder) error
+	GetFolder(id uint) (*entities.Folder, error)
+	ListFolders() ([]entities.Folder, error)
+	// scope giới hạn số URL được đếm; URLScope rỗng đếm mọi URL
+	CountURLsByFolder(scope entities.URLScope) (map[uint]int64, error)
+	UpdateFolder(folder *entities.Folder) error
+	DeleteFolder(id uint) error
+	SetURLFolder(urlID uint, folderID *uint) error
+}
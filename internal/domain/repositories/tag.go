@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/url-shorted2/internal/domain/entities"
+)
+
+// ITagRepository định nghĩa interface cho tag repository
+type ITagRepository interface {
+	// WithContext trả về repository chạy query với ctx (trace, cancel) như gorm.DB.WithContext
+	WithContext(ctx context.Context) ITagRepository
+	CreateTag(tag *entities.Tag) error
+	GetTagByName(name string) (*entities.Tag, error)
+	ListTags() ([]entities.Tag, error)
+	// scope giới hạn số URL được đếm; URLScope rỗng đếm mọi URL
+	CountURLsByTag(scope entities.URLScope) (map[uint]int64, error)
+	UpdateTag(tag *entities.Tag) error
+	DeleteTag(id uint) error
+	FindOrCreateTags(names []string) ([]entities.Tag, error)
+	ReplaceURLTags(urlID uint, tags []entities.Tag) error
+	GetURLTags(urlID uint) ([]entities.Tag, error)
+	// scope giới hạn thống kê tag trong các URL của owner, workspace
+	GetTagStats(tagID uint, scope entities.URLScope) (*entities.TagStats, error)
+	ListTopURLsByTag(tagID uint, scope entities.URLScope, limit int) ([]entities.URL, error)
+	CountTagUniqueVisitors(tagID uint, scope entities.URLScope) (int64, error)
+}
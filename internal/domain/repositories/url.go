@@ -1,16 +1,40 @@
 package repositories
 
-import "github.com/url-shorted2/internal/domain/entities"
+import (
+	"context"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+)
 
 // URLRepository định nghĩa interface cho URL repository
 type IURLRepository interface {
+	// WithContext trả về repository chạy query với ctx (trace, cancel) như gorm.DB.WithContext
+	WithContext(ctx context.Context) IURLRepository
 	Create(url *entities.URL) error
+	CreateBatch(urls []*entities.URL) error
 	GetByShortCode(shortCode string) (*entities.URL, error)
+	FindExistingShortCodes(codes []string) ([]string, error)
 	GetByID(id uint) (*entities.URL, error)
 	Update(url *entities.URL) error
 	Delete(id uint) error
-	IncrementClickCount(shortCode string) error
-	GetAnalytics(urlID uint) ([]entities.Analytics, error)
+	IncrementClickCount(shortCode string) (int64, error)
+	ListURLs(filter entities.URLFilter) ([]entities.URL, error)
+	ListAnalytics(urlID uint, filter entities.ClickFilter) ([]entities.Analytics, error)
+	StreamAnalytics(urlID uint, filter entities.ClickFilter, fn func(*entities.Analytics) error) error
+	GetLastClickedAt(urlID uint) (*time.Time, error)
 	AddAnalytics(analytics *entities.Analytics) error
 	GetLastID() (uint, error)
+	GetClickTimeSeries(urlID uint, from, to time.Time, interval string, loc *time.Location, rollupBefore time.Time) ([]entities.TimeSeriesBucket, error)
+	GetClickBreakdown(urlID uint, dimension string, rollupBefore time.Time, limit int) ([]entities.DimensionCount, error)
+	GetTopReferrers(urlID uint, rollupBefore time.Time, limit int) ([]entities.ReferrerCount, error)
+	CountUniqueVisitors(urlID uint, from, to time.Time) (int64, error)
+	RollupAnalytics(granularity string, from, to time.Time) (int64, error)
+	GetRollupWatermark(granularity string) (*time.Time, error)
+	GetOldestClickedAt() (*time.Time, error)
+	PruneAnalytics(before time.Time) (int64, error)
+	DeleteAnalytics(ipAddresses, fingerprints []string) (int64, error)
+	ListPendingMetadata(limit int) ([]entities.URL, error)
+	UpdateMetadata(id uint, metadata entities.URLMetadata) error
+	UpdatePreview(id uint, preview entities.URLPreview) error
 }
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/url-shorted2/internal/domain/entities"
+)
+
+// IUserRepository định nghĩa interface cho user repository
+type IUserRepository interface {
+	// WithContext trả về repository chạy query với ctx (trace, cancel) như gorm.DB.WithContext
+	WithContext(ctx context.Context) IUserRepository
+	CreateUser(user *entities.User) error
+	GetUser(id uint) (*entities.User, error)
+	GetUserByEmail(email string) (*entities.User, error)
+	ListUsers() ([]entities.User, error)
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+)
+
+// IWebhookRepository định nghĩa interface cho webhook repository
+type IWebhookRepository interface {
+	// WithContext trả về repository chạy query với ctx (trace, cancel) như gorm.DB.WithContext
+	WithContext(ctx context.Context) IWebhookRepository
+	CreateWebhook(webhook *entities.Webhook) error
+	GetWebhook(id uint) (*entities.Webhook, error)
+	ListWebhooks() ([]entities.Webhook, error)
+	ListActiveWebhooks() ([]entities.Webhook, error)
+	DeleteWebhook(id uint) error
+	EnqueueDeliveries(deliveries []entities.WebhookDelivery) error
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]entities.WebhookDelivery, error)
+	UpdateDelivery(delivery *entities.WebhookDelivery) error
+	GetDelivery(webhookID, deliveryID uint) (*entities.WebhookDelivery, error)
+	ListDeliveries(webhookID uint, status string, limit int) ([]entities.WebhookDelivery, error)
+}
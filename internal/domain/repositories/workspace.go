@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/url-shorted2/internal/domain/entities"
+)
+
+// IWorkspaceRepository định nghĩa interface cho workspace và member của workspace
+type IWorkspaceRepository interface {
+	// WithContext trả về repository chạy query với ctx (trace, cancel) như gorm.DB.WithContext
+	WithContext(ctx context.Context) IWorkspaceRepository
+	// CreateWorkspace tạo workspace cùng member owner đầu tiên trong một transaction
+	CreateWorkspace(workspace *entities.Workspace, owner *entities.WorkspaceMember) error
+	GetWorkspace(id uint) (*entities.Workspace, error)
+	// ListWorkspaces lấy workspace userID là member, nil là mọi workspace
+	ListWorkspaces(userID *uint) ([]entities.Workspace, error)
+	UpdateWorkspace(workspace *entities.Workspace) error
+	GetMember(workspaceID, userID uint) (*entities.WorkspaceMember, error)
+	// ListMembers lấy member của workspace kèm thông tin user
+	ListMembers(workspaceID uint) ([]entities.WorkspaceMember, error)
+	CreateMember(member *entities.WorkspaceMember) error
+	// UpdateMember và DeleteMember không hạ role hoặc xóa owner cuối cùng; khi đó, hoặc khi member
+	// không tồn tại, trả về gorm.ErrRecordNotFound
+	UpdateMember(member *entities.WorkspaceMember) error
+	DeleteMember(workspaceID, userID uint) error
+}
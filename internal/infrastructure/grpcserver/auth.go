@@ -0,0 +1,85 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	shortenerv1 "github.com/url-shorted2/api/proto/shortener/v1"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// methodScopes là scope cần cho từng RPC, giống scope của endpoint REST tương ứng.
+// RPC không có trong map (health, reflection) không cần API key.
+var methodScopes = map[string]string{
+	shortenerv1.URLShortenerService_CreateShortURL_FullMethodName: entities.ScopeLinksWrite,
+	shortenerv1.URLShortenerService_GetURL_FullMethodName:         entities.ScopeLinksRead,
+	shortenerv1.URLShortenerService_GetURLStats_FullMethodName:    entities.ScopeStatsRead,
+	shortenerv1.URLShortenerService_DeleteURL_FullMethodName:      entities.ScopeLinksWrite,
+	shortenerv1.URLShortenerService_ListClicks_FullMethodName:     entities.ScopeStatsRead,
+	shortenerv1.URLShortenerService_StreamClicks_FullMethodName:   entities.ScopeStatsRead,
+}
+
+// authenticate đọc metadata authorization: Bearer <key>, kiểm tra scope của method
+// và trả về ctx đã gắn API key
+func authenticate(ctx context.Context, apiKeys usecases.IAPIKeyUsecase, method string) (context.Context, error) {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return ctx, nil
+	}
+
+	var provided string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			provided, _ = strings.CutPrefix(values[0], "Bearer ")
+		}
+	}
+	if provided == "" {
+		return nil, toStatus(usecases.ErrUnauthorized)
+	}
+
+	key, err := apiKeys.Authenticate(ctx, provided)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	ctx = usecases.WithAPIKey(ctx, key)
+	if err := usecases.RequireScope(ctx, scope); err != nil {
+		return nil, toStatus(err)
+	}
+	return ctx, nil
+}
+
+// unaryAuthInterceptor xác thực API key cho unary RPC
+func unaryAuthInterceptor(apiKeys usecases.IAPIKeyUsecase) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, apiKeys, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor xác thực API key cho streaming RPC
+func streamAuthInterceptor(apiKeys usecases.IAPIKeyUsecase) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), apiKeys, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticatedStream thay context của stream bằng context đã gắn API key
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
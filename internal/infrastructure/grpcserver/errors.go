@@ -0,0 +1,56 @@
+package grpcserver
+
+import (
+	"fmt"
+
+	"github.com/url-shorted2/internal/usecases"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain là domain của ErrorInfo gắn vào status lỗi
+const errorDomain = "url-shortener"
+
+// grpcCodes map loại lỗi domain sang gRPC status code, tương ứng với HTTP status của REST
+var grpcCodes = map[usecases.ErrorKind]codes.Code{
+	usecases.KindValidation:   codes.InvalidArgument,
+	usecases.KindNotFound:     codes.NotFound,
+	usecases.KindConflict:     codes.AlreadyExists,
+	usecases.KindPrecondition: codes.FailedPrecondition,
+	usecases.KindExpired:      codes.FailedPrecondition,
+	usecases.KindUnavailable:  codes.Unavailable,
+	usecases.KindUnauthorized: codes.Unauthenticated,
+	usecases.KindForbidden:    codes.PermissionDenied,
+	usecases.KindInternal:     codes.Internal,
+}
+
+// toStatus chuyển lỗi usecase thành gRPC status; code ổn định của lỗi (giống field code của
+// problem+json) nằm trong ErrorInfo.Reason
+func toStatus(err error) error {
+	domainErr := usecases.AsDomainError(err)
+	code, ok := grpcCodes[domainErr.Kind]
+	if !ok {
+		code = codes.Internal
+	}
+
+	// Message chỉ lấy từ DomainError, không lấy chuỗi wrap
+	message := domainErr.Message
+	if domainErr.Kind == usecases.KindInternal {
+		fmt.Printf("gRPC internal error: %v\n", err)
+	}
+	st, detailErr := status.New(code, message).WithDetails(&errdetails.ErrorInfo{
+		Reason: domainErr.Code,
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(code, message)
+	}
+	return st.Err()
+}
+
+// invalidArgument trả về lỗi InvalidArgument với reason invalid_request như REST
+func invalidArgument(message string) error {
+	return toStatus(usecases.ErrInvalidRequest.WithMessage("%s", message))
+}
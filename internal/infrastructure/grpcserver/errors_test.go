@@ -0,0 +1,41 @@
+package grpcserver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"Tài nguyên đã tồn tại", usecases.ErrTagExists, codes.AlreadyExists},
+		{"Trạng thái không cho phép", usecases.ErrFolderNotEmpty, codes.FailedPrecondition},
+		{"Link hết hạn", usecases.ErrURLInactive, codes.FailedPrecondition},
+		{"Lỗi được wrap", fmt.Errorf("create tag: %w", usecases.ErrTagExists), codes.AlreadyExists},
+		{"Lỗi không phân loại", errors.New("database is locked"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status.Code(toStatus(tt.err)))
+		})
+	}
+}
+
+func TestToStatus_MessageHasNoCause(t *testing.T) {
+	err := fmt.Errorf("create url: %w", usecases.ErrLockUnavailable)
+
+	st := status.Convert(toStatus(err))
+	assert.Equal(t, codes.Unavailable, st.Code())
+	assert.Equal(t, usecases.ErrLockUnavailable.Message, st.Message())
+}
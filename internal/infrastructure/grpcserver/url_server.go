@@ -0,0 +1,200 @@
+package grpcserver
+
+import (
+	"context"
+
+	shortenerv1 "github.com/url-shorted2/api/proto/shortener/v1"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// URLServer implement URLShortenerService trên cùng usecase với REST handler
+type URLServer struct {
+	shortenerv1.UnimplementedURLShortenerServiceServer
+	urlUsecase usecases.IURLUsecase
+}
+
+// NewURLServer tạo instance mới của URLServer
+func NewURLServer(urlUsecase usecases.IURLUsecase) *URLServer {
+	return &URLServer{
+		urlUsecase: urlUsecase,
+	}
+}
+
+// NewServer tạo gRPC server đã đăng ký URLShortenerService, health check và reflection (cho grpcurl).
+// URLShortenerService cần API key trong metadata authorization như REST.
+func NewServer(urlUsecase usecases.IURLUsecase, apiKeys usecases.IAPIKeyUsecase) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(unaryAuthInterceptor(apiKeys)),
+		grpc.StreamInterceptor(streamAuthInterceptor(apiKeys)),
+	)
+	shortenerv1.RegisterURLShortenerServiceServer(server, NewURLServer(urlUsecase))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	return server
+}
+
+// CreateShortURL tạo short URL
+func (s *URLServer) CreateShortURL(ctx context.Context, req *shortenerv1.CreateShortURLRequest) (*shortenerv1.CreateShortURLResponse, error) {
+	response, err := s.urlUsecase.CreateShortURL(ctx, entities.CreateURLRequest{OriginalURL: req.GetUrl()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &shortenerv1.CreateShortURLResponse{
+		ShortCode:   response.ShortCode,
+		ShortUrl:    response.ShortURL,
+		OriginalUrl: response.OriginalURL,
+		CreatedAt:   timestamppb.New(response.CreatedAt),
+	}, nil
+}
+
+// GetURL lấy URL gốc của short code
+func (s *URLServer) GetURL(ctx context.Context, req *shortenerv1.GetURLRequest) (*shortenerv1.GetURLResponse, error) {
+	if req.GetShortCode() == "" {
+		return nil, invalidArgument("short code is required")
+	}
+
+	originalURL, err := s.urlUsecase.GetOriginalURL(ctx, req.GetShortCode())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &shortenerv1.GetURLResponse{
+		ShortCode:   req.GetShortCode(),
+		OriginalUrl: originalURL,
+	}, nil
+}
+
+// GetURLStats lấy thống kê tổng quan của URL
+func (s *URLServer) GetURLStats(ctx context.Context, req *shortenerv1.GetURLStatsRequest) (*shortenerv1.GetURLStatsResponse, error) {
+	if req.GetShortCode() == "" {
+		return nil, invalidArgument("short code is required")
+	}
+
+	stats, err := s.urlUsecase.GetURLStats(ctx, req.GetShortCode())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &shortenerv1.GetURLStatsResponse{
+		ShortCode:        stats.ShortCode,
+		OriginalUrl:      stats.OriginalURL,
+		TotalClicks:      stats.TotalClicks,
+		UniqueVisitors:   stats.UniqueVisitors,
+		CreatedAt:        timestamppb.New(stats.CreatedAt),
+		Browsers:         toDimensionCounts(stats.Browsers),
+		OperatingSystems: toDimensionCounts(stats.OperatingSystems),
+		Devices:          toDimensionCounts(stats.Devices),
+	}
+	if stats.LastClicked != nil {
+		response.LastClicked = timestamppb.New(*stats.LastClicked)
+	}
+	return response, nil
+}
+
+// DeleteURL xóa URL
+func (s *URLServer) DeleteURL(ctx context.Context, req *shortenerv1.DeleteURLRequest) (*shortenerv1.DeleteURLResponse, error) {
+	if req.GetShortCode() == "" {
+		return nil, invalidArgument("short code is required")
+	}
+
+	if err := s.urlUsecase.DeleteURL(ctx, req.GetShortCode()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &shortenerv1.DeleteURLResponse{}, nil
+}
+
+// ListClicks lấy lịch sử click theo trang
+func (s *URLServer) ListClicks(ctx context.Context, req *shortenerv1.ListClicksRequest) (*shortenerv1.ListClicksResponse, error) {
+	if req.GetShortCode() == "" {
+		return nil, invalidArgument("short code is required")
+	}
+
+	clicks, err := s.urlUsecase.ListClicks(ctx, req.GetShortCode(), entities.ClickListRequest{
+		Cursor:   req.GetCursor(),
+		Limit:    int(req.GetLimit()),
+		From:     req.GetFrom(),
+		To:       req.GetTo(),
+		Country:  req.GetCountry(),
+		Referrer: req.GetReferrer(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &shortenerv1.ListClicksResponse{
+		ShortCode:  clicks.ShortCode,
+		Clicks:     make([]*shortenerv1.Click, 0, len(clicks.Clicks)),
+		NextCursor: clicks.NextCursor,
+		HasMore:    clicks.HasMore,
+	}
+	for _, click := range clicks.Clicks {
+		response.Clicks = append(response.Clicks, &shortenerv1.Click{
+			Id:              uint64(click.ID),
+			ClickedAt:       timestamppb.New(click.ClickedAt),
+			IpAddress:       click.IPAddress,
+			UserAgent:       click.UserAgent,
+			Referer:         click.Referer,
+			ReferrerHost:    click.ReferrerHost,
+			ReferrerChannel: click.ReferrerChannel,
+			Country:         click.Country,
+			City:            click.City,
+			Browser:         click.Browser,
+			BrowserVersion:  click.BrowserVersion,
+			Os:              click.OS,
+			DeviceType:      click.DeviceType,
+		})
+	}
+	return response, nil
+}
+
+// StreamClicks gửi click realtime tới client cho tới khi client hủy stream
+func (s *URLServer) StreamClicks(req *shortenerv1.StreamClicksRequest, stream grpc.ServerStreamingServer[shortenerv1.StreamClicksResponse]) error {
+	if req.GetShortCode() == "" {
+		return invalidArgument("short code is required")
+	}
+
+	// events bị đóng khi context của stream bị hủy
+	events, err := s.urlUsecase.StreamClicks(stream.Context(), req.GetShortCode(), uint(req.GetLastEventId()))
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for event := range events {
+		err := stream.Send(&shortenerv1.StreamClicksResponse{
+			Click: &shortenerv1.ClickEvent{
+				Id:              uint64(event.ID),
+				ShortCode:       event.ShortCode,
+				ClickedAt:       timestamppb.New(event.ClickedAt),
+				Country:         event.Country,
+				ReferrerHost:    event.ReferrerHost,
+				ReferrerChannel: event.ReferrerChannel,
+				Browser:         event.Browser,
+				Os:              event.OS,
+				DeviceType:      event.DeviceType,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toDimensionCounts chuyển breakdown sang message protobuf
+func toDimensionCounts(counts []entities.DimensionCount) []*shortenerv1.DimensionCount {
+	result := make([]*shortenerv1.DimensionCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, &shortenerv1.DimensionCount{Value: count.Value, Clicks: count.Clicks})
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler xử lý các request quản lý API key
+type APIKeyHandler struct {
+	apiKeyUsecase usecases.IAPIKeyUsecase
+}
+
+// NewAPIKeyHandler tạo instance mới của APIKeyHandler
+func NewAPIKeyHandler(apiKeyUsecase usecases.IAPIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+	}
+}
+
+// CreateAPIKey xử lý POST /api/v1/admin/api-keys
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var request entities.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.apiKeyUsecase.CreateAPIKey(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListAPIKeys xử lý GET /api/v1/admin/api-keys
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyUsecase.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+// RotateAPIKey xử lý POST /api/v1/admin/api-keys/:id/rotate
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	response, err := h.apiKeyUsecase.RotateAPIKey(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey xử lý DELETE /api/v1/admin/api-keys/:id
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	response, err := h.apiKeyUsecase.RevokeAPIKey(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
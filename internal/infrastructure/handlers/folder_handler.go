@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FolderHandler xử lý các request quản lý folder và chuyển URL giữa các folder
+type FolderHandler struct {
+	folderUsecase usecases.IFolderUsecase
+}
+
+// NewFolderHandler tạo instance mới của FolderHandler
+func NewFolderHandler(folderUsecase usecases.IFolderUsecase) *FolderHandler {
+	return &FolderHandler{
+		folderUsecase: folderUsecase,
+	}
+}
+
+// CreateFolder xử lý POST /api/v1/folders
+func (h *FolderHandler) CreateFolder(c *gin.Context) {
+	var request entities.FolderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.folderUsecase.CreateFolder(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListFolders xử lý GET /api/v1/folders
+func (h *FolderHandler) ListFolders(c *gin.Context) {
+	response, err := h.folderUsecase.ListFolders(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateFolder xử lý PUT /api/v1/folders/:id
+func (h *FolderHandler) UpdateFolder(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var request entities.FolderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.folderUsecase.UpdateFolder(c.Request.Context(), id, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteFolder xử lý DELETE /api/v1/folders/:id
+func (h *FolderHandler) DeleteFolder(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.folderUsecase.DeleteFolder(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Folder deleted successfully",
+	})
+}
+
+// SetURLFolder xử lý PUT /api/v1/urls/:shortCode/folder
+func (h *FolderHandler) SetURLFolder(c *gin.Context) {
+	var request entities.SetURLFolderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.folderUsecase.SetURLFolder(c.Request.Context(), c.Param("shortCode"), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpStatuses map loại lỗi domain sang HTTP status
+var httpStatuses = map[usecases.ErrorKind]int{
+	usecases.KindValidation:   http.StatusBadRequest,
+	usecases.KindNotFound:     http.StatusNotFound,
+	usecases.KindConflict:     http.StatusConflict,
+	usecases.KindPrecondition: http.StatusConflict,
+	usecases.KindExpired:      http.StatusGone,
+	usecases.KindUnavailable:  http.StatusServiceUnavailable,
+	usecases.KindUnauthorized: http.StatusUnauthorized,
+	usecases.KindForbidden:    http.StatusForbidden,
+	usecases.KindInternal:     http.StatusInternalServerError,
+}
+
+// respondError map lỗi usecase sang HTTP status và trả về problem+json.
+// Detail chỉ lấy từ message của DomainError, không lấy chuỗi wrap; lỗi internal được ghi vào c.Errors để log.
+func respondError(c *gin.Context, err error) {
+	domainErr := usecases.AsDomainError(err)
+	status, ok := httpStatuses[domainErr.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	if domainErr.Kind == usecases.KindInternal {
+		_ = c.Error(err)
+	}
+	respondProblem(c, status, domainErr.Code, domainErr.Message)
+}
+
+// respondInvalidRequest trả về 400 cho body, query hoặc path param không parse được
+func respondInvalidRequest(c *gin.Context, detail string) {
+	respondError(c, usecases.ErrInvalidRequest.WithMessage("%s", detail))
+}
+
+// respondProblem ghi body problem+json với instance là path của request
+func respondProblem(c *gin.Context, status int, code, detail string) {
+	problem := entities.NewProblem(status, code, detail)
+	problem.Instance = c.Request.URL.Path
+	c.Header("Content-Type", entities.ProblemContentType)
+	c.AbortWithStatusJSON(status, problem)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler xử lý các request quản lý tag và gắn tag cho URL
+type TagHandler struct {
+	tagUsecase usecases.ITagUsecase
+}
+
+// NewTagHandler tạo instance mới của TagHandler
+func NewTagHandler(tagUsecase usecases.ITagUsecase) *TagHandler {
+	return &TagHandler{
+		tagUsecase: tagUsecase,
+	}
+}
+
+// CreateTag xử lý POST /api/v1/tags
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var request entities.TagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.tagUsecase.CreateTag(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListTags xử lý GET /api/v1/tags
+func (h *TagHandler) ListTags(c *gin.Context) {
+	response, err := h.tagUsecase.ListTags(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RenameTag xử lý PUT /api/v1/tags/:tag
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	var request entities.TagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.tagUsecase.RenameTag(c.Request.Context(), c.Param("tag"), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTag xử lý DELETE /api/v1/tags/:tag
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	if err := h.tagUsecase.DeleteTag(c.Request.Context(), c.Param("tag")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tag deleted successfully",
+	})
+}
+
+// GetTagStats xử lý GET /api/v1/tags/:tag/stats
+func (h *TagHandler) GetTagStats(c *gin.Context) {
+	var request entities.TagStatsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.tagUsecase.GetTagStats(c.Request.Context(), c.Param("tag"), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetURLTags xử lý PUT /api/v1/urls/:shortCode/tags
+func (h *TagHandler) SetURLTags(c *gin.Context) {
+	var request entities.SetURLTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.tagUsecase.SetURLTags(c.Request.Context(), c.Param("shortCode"), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
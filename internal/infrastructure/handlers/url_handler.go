@@ -1,14 +1,33 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/url-shorted2/internal/domain/entities"
 	"github.com/url-shorted2/internal/usecases"
+	"github.com/url-shorted2/internal/utils"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// sseHeartbeatInterval giữ kết nối SSE qua proxy/load balancer khi không có click
+	sseHeartbeatInterval = 15 * time.Second
+	// sseRetryMillis là thời gian client chờ trước khi tự reconnect
+	sseRetryMillis = 3000
+	// maxImportBytes giới hạn kích thước file import
+	maxImportBytes = 64 << 20
+)
+
 // URLHandler xử lý các request liên quan đến URL
 type URLHandler struct {
 	urlUsecase usecases.IURLUsecase
@@ -26,32 +45,107 @@ func (h *URLHandler) CreateShortURL(c *gin.Context) {
 	var request entities.CreateURLRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondInvalidRequest(c, err.Error())
 		return
 	}
 	// Create short URL
-	response, err := h.urlUsecase.CreateShortURL(request)
+	response, err := h.urlUsecase.CreateShortURL(c.Request.Context(), request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Failed to create short URL",
-			"details": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, response)
 }
 
+// CreateShortURLs xử lý POST /api/v1/urls/batch.
+// Luôn trả 200 khi batch hợp lệ; kết quả từng item nằm trong results
+func (h *URLHandler) CreateShortURLs(c *gin.Context) {
+	var request entities.BatchCreateURLRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.urlUsecase.CreateShortURLs(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ImportURLs xử lý POST /api/v1/admin/urls/import.
+// File gửi qua multipart field "file" hoặc trực tiếp trong body.
+func (h *URLHandler) ImportURLs(c *gin.Context) {
+	var request entities.ImportURLsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	body := io.Reader(c.Request.Body)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			respondInvalidRequest(c, err.Error())
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			respondInvalidRequest(c, err.Error())
+			return
+		}
+		defer file.Close()
+		body = file
+
+		if request.Format == "" {
+			switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+			case ".csv":
+				request.Format = entities.ImportFormatCSV
+			case ".json":
+				request.Format = entities.ImportFormatJSON
+			}
+		}
+	}
+
+	report, err := h.urlUsecase.ImportURLs(c.Request.Context(), body, request)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = usecases.ErrInvalidImport.WithMessage("import file exceeds %d bytes", maxBytesErr.Limit)
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListURLs xử lý GET /api/v1/urls
+func (h *URLHandler) ListURLs(c *gin.Context) {
+	var request entities.URLListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	urls, err := h.urlUsecase.ListURLs(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, urls)
+}
+
 // Redirect xử lý GET /:shortCode
 func (h *URLHandler) Redirect(c *gin.Context) {
 	shortCode := c.Param("shortCode")
 	if shortCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Short code is required",
-		})
+		respondInvalidRequest(c, "short code is required")
 		return
 	}
 
@@ -59,58 +153,239 @@ func (h *URLHandler) Redirect(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
 	referer := c.GetHeader("Referer")
+	doNotTrack := utils.DoNotTrack(c.GetHeader("DNT"), c.GetHeader("Sec-GPC"))
+
+	// Bot nhận trang preview, người dùng nhận redirect: cache phải tách response theo User-Agent
+	c.Header("Vary", "User-Agent")
+
+	// Bot unfurl của mạng xã hội / app chat nhận trang Open Graph nếu link có preview tùy chỉnh
+	if utils.IsUnfurlBot(userAgent) {
+		preview, err := h.urlUsecase.GetSocialPreview(c.Request.Context(), shortCode)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if preview != nil {
+			c.Header("Cache-Control", "no-cache")
+			c.Data(http.StatusOK, "text/html; charset=utf-8", preview.HTML)
+			return
+		}
+	}
 
 	// Redirect
-	originalURL, err := h.urlUsecase.Redirect(shortCode, ipAddress, userAgent, referer)
+	target, err := h.urlUsecase.Redirect(c.Request.Context(), shortCode, ipAddress, userAgent, referer, doNotTrack)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Redirect(target.StatusCode, target.URL)
+}
+
+// SetPreview xử lý PUT /api/v1/urls/:shortCode/preview
+func (h *URLHandler) SetPreview(c *gin.Context) {
+	var request entities.URLPreviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	preview, err := h.urlUsecase.SetPreview(c.Request.Context(), c.Param("shortCode"), request)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "URL not found or expired",
-			"details": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
-	c.Redirect(http.StatusMovedPermanently, originalURL)
+	c.JSON(http.StatusOK, preview)
 }
 
 // GetURLStats xử lý GET /api/v1/urls/:shortCode/stats
 func (h *URLHandler) GetURLStats(c *gin.Context) {
 	shortCode := c.Param("shortCode")
 	if shortCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Short code is required",
-		})
+		respondInvalidRequest(c, "short code is required")
+		return
+	}
+
+	stats, err := h.urlUsecase.GetURLStats(c.Request.Context(), shortCode)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetClickTimeSeries xử lý GET /api/v1/urls/:shortCode/stats/timeseries
+func (h *URLHandler) GetClickTimeSeries(c *gin.Context) {
+	shortCode := c.Param("shortCode")
+	if shortCode == "" {
+		respondInvalidRequest(c, "short code is required")
+		return
+	}
+
+	var request entities.TimeSeriesRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	series, err := h.urlUsecase.GetClickTimeSeries(c.Request.Context(), shortCode, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// GetReferrerStats xử lý GET /api/v1/urls/:shortCode/stats/referrers
+func (h *URLHandler) GetReferrerStats(c *gin.Context) {
+	shortCode := c.Param("shortCode")
+	if shortCode == "" {
+		respondInvalidRequest(c, "short code is required")
+		return
+	}
+
+	var request entities.ReferrerStatsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
 		return
 	}
 
-	stats, err := h.urlUsecase.GetURLStats(shortCode)
+	stats, err := h.urlUsecase.GetReferrerStats(c.Request.Context(), shortCode, request)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "URL not found",
-			"details": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
 
+// ListClicks xử lý GET /api/v1/urls/:shortCode/clicks
+func (h *URLHandler) ListClicks(c *gin.Context) {
+	shortCode := c.Param("shortCode")
+	if shortCode == "" {
+		respondInvalidRequest(c, "short code is required")
+		return
+	}
+
+	var request entities.ClickListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	clicks, err := h.urlUsecase.ListClicks(c.Request.Context(), shortCode, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, clicks)
+}
+
+// ExportClicks xử lý GET /api/v1/urls/:shortCode/clicks/export
+func (h *URLHandler) ExportClicks(c *gin.Context) {
+	shortCode := c.Param("shortCode")
+	if shortCode == "" {
+		respondInvalidRequest(c, "short code is required")
+		return
+	}
+
+	var request entities.ClickExportRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	export, err := h.urlUsecase.ExportClicks(c.Request.Context(), shortCode, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.Filename}))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	// Header đã gửi nên lỗi giữa chừng chỉ có thể ghi log
+	if err := export.Stream(c.Writer); err != nil {
+		_ = c.Error(fmt.Errorf("export clicks interrupted: %w", err))
+	}
+}
+
+// StreamClicks xử lý GET /api/v1/urls/:shortCode/events (Server-Sent Events).
+// Client reconnect với header Last-Event-ID (hoặc query last_event_id) sẽ nhận lại các click bị lỡ.
+func (h *URLHandler) StreamClicks(c *gin.Context) {
+	shortCode := c.Param("shortCode")
+	if shortCode == "" {
+		respondInvalidRequest(c, "short code is required")
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var afterID uint64
+	if lastEventID != "" {
+		var err error
+		afterID, err = strconv.ParseUint(lastEventID, 10, 0)
+		if err != nil {
+			respondInvalidRequest(c, "invalid Last-Event-ID: "+err.Error())
+			return
+		}
+	}
+
+	events, err := h.urlUsecase.StreamClicks(c.Request.Context(), shortCode, uint(afterID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Render(-1, sse.Event{Retry: sseRetryMillis, Data: ""})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	// events bị đóng khi client ngắt kết nối
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatUint(uint64(event.ID), 10),
+				Event: "click",
+				Data:  event,
+			})
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
 // DeleteURL xử lý DELETE /api/v1/urls/:shortCode
 func (h *URLHandler) DeleteURL(c *gin.Context) {
 	shortCode := c.Param("shortCode")
 	if shortCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Short code is required",
-		})
+		respondInvalidRequest(c, "short code is required")
 		return
 	}
 
-	err := h.urlUsecase.DeleteURL(shortCode)
+	err := h.urlUsecase.DeleteURL(c.Request.Context(), shortCode)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "URL not found",
-			"details": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -119,22 +394,69 @@ func (h *URLHandler) DeleteURL(c *gin.Context) {
 	})
 }
 
+// EraseAnalytics xử lý POST /api/v1/admin/analytics/erase
+func (h *URLHandler) EraseAnalytics(c *gin.Context) {
+	var request entities.EraseAnalyticsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.urlUsecase.EraseAnalytics(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetQRCode xử lý GET /api/v1/urls/:shortCode/qr
+func (h *URLHandler) GetQRCode(c *gin.Context) {
+	shortCode := c.Param("shortCode")
+	if shortCode == "" {
+		respondInvalidRequest(c, "short code is required")
+		return
+	}
+
+	var request entities.QRCodeRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	qr, err := h.urlUsecase.GetQRCode(c.Request.Context(), shortCode, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Ảnh chỉ phụ thuộc short URL và query params nên CDN có thể cache theo URL đầy đủ
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", qr.MaxAge))
+	c.Header("ETag", qr.ETag)
+	for _, match := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		match = strings.TrimPrefix(strings.TrimSpace(match), "W/")
+		if match == qr.ETag || match == "*" {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": qr.Filename}))
+	c.Data(http.StatusOK, qr.ContentType, qr.Data)
+}
+
 // GetURLInfo xử lý GET /api/v1/urls/:shortCode
 func (h *URLHandler) GetURLInfo(c *gin.Context) {
 	shortCode := c.Param("shortCode")
 	if shortCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Short code is required",
-		})
+		respondInvalidRequest(c, "short code is required")
 		return
 	}
 
-	originalURL, err := h.urlUsecase.GetOriginalURL(shortCode)
+	originalURL, err := h.urlUsecase.GetOriginalURL(c.Request.Context(), shortCode)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "URL not found or expired",
-			"details": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler xử lý các request quản lý user
+type UserHandler struct {
+	userUsecase usecases.IUserUsecase
+}
+
+// NewUserHandler tạo instance mới của UserHandler
+func NewUserHandler(userUsecase usecases.IUserUsecase) *UserHandler {
+	return &UserHandler{
+		userUsecase: userUsecase,
+	}
+}
+
+// CreateUser xử lý POST /api/v1/admin/users
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var request entities.CreateUserRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.userUsecase.CreateUser(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// ListUsers xử lý GET /api/v1/admin/users
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	response, err := h.userUsecase.ListUsers(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
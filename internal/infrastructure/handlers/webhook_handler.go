@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler xử lý các request quản lý webhook
+type WebhookHandler struct {
+	webhookUsecase usecases.IWebhookUsecase
+}
+
+// NewWebhookHandler tạo instance mới của WebhookHandler
+func NewWebhookHandler(webhookUsecase usecases.IWebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUsecase: webhookUsecase,
+	}
+}
+
+// CreateWebhook xử lý POST /api/v1/admin/webhooks
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var request entities.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.webhookUsecase.CreateWebhook(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListWebhooks xử lý GET /api/v1/admin/webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookUsecase.ListWebhooks(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+// DeleteWebhook xử lý DELETE /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookUsecase.DeleteWebhook(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// ListDeliveries xử lý GET /api/v1/admin/webhooks/:id/deliveries
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var request entities.WebhookDeliveryListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	deliveries, err := h.webhookUsecase.ListDeliveries(c.Request.Context(), id, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// RetryDelivery xử lý POST /api/v1/admin/webhooks/:id/deliveries/:deliveryId/retry
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseIDParam(c, "deliveryId")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookUsecase.RetryDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// parseIDParam đọc path param dạng số, trả về 400 nếu không hợp lệ
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		respondInvalidRequest(c, "invalid "+name)
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkspaceHandler xử lý các request quản lý workspace, settings và member
+type WorkspaceHandler struct {
+	workspaceUsecase usecases.IWorkspaceUsecase
+}
+
+// NewWorkspaceHandler tạo instance mới của WorkspaceHandler
+func NewWorkspaceHandler(workspaceUsecase usecases.IWorkspaceUsecase) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceUsecase: workspaceUsecase,
+	}
+}
+
+// CreateWorkspace xử lý POST /api/v1/workspaces
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	var request entities.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.workspaceUsecase.CreateWorkspace(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListWorkspaces xử lý GET /api/v1/workspaces
+func (h *WorkspaceHandler) ListWorkspaces(c *gin.Context) {
+	response, err := h.workspaceUsecase.ListWorkspaces(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWorkspace xử lý GET /api/v1/workspaces/:id
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	response, err := h.workspaceUsecase.GetWorkspace(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateSettings xử lý PUT /api/v1/workspaces/:id/settings
+func (h *WorkspaceHandler) UpdateSettings(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var request entities.WorkspaceSettings
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.workspaceUsecase.UpdateSettings(c.Request.Context(), id, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListMembers xử lý GET /api/v1/workspaces/:id/members
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	response, err := h.workspaceUsecase.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// InviteMember xử lý POST /api/v1/workspaces/:id/members
+func (h *WorkspaceHandler) InviteMember(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var request entities.InviteMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.workspaceUsecase.InviteMember(c.Request.Context(), id, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateMember xử lý PUT /api/v1/workspaces/:id/members/:userId
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	var request entities.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalidRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.workspaceUsecase.UpdateMember(c.Request.Context(), id, userID, request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RemoveMember xử lý DELETE /api/v1/workspaces/:id/members/:userId
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceUsecase.RemoveMember(c.Request.Context(), id, userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member removed successfully",
+	})
+}
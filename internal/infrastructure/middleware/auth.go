@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyAuthMiddleware yêu cầu header Authorization: Bearer <key> và gắn API key vào context của request
+// để RequireScope và usecase biết ai đang gọi
+func APIKeyAuthMiddleware(apiKeys usecases.IAPIKeyUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || provided == "" {
+			abortUnauthorized(c, "Missing API key")
+			return
+		}
+
+		key, err := apiKeys.Authenticate(c.Request.Context(), provided)
+		if err != nil {
+			if errors.Is(err, usecases.ErrUnauthorized) {
+				abortUnauthorized(c, "Invalid API key")
+				return
+			}
+			_ = c.Error(err)
+			abortWithProblem(c, http.StatusInternalServerError, usecases.CodeInternal, "internal server error")
+			return
+		}
+
+		c.Request = c.Request.WithContext(usecases.WithAPIKey(c.Request.Context(), key))
+		c.Next()
+	}
+}
+
+// RequireScope chặn request khi API key của request (gắn bởi APIKeyAuthMiddleware) không có scope
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := usecases.RequireScope(c.Request.Context(), scope); err != nil {
+			if errors.Is(err, usecases.ErrUnauthorized) {
+				abortUnauthorized(c, "Missing API key")
+				return
+			}
+			abortWithProblem(c, http.StatusForbidden, entities.ProblemCodeInsufficientScope, err.Error())
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// abortUnauthorized trả về 401 kèm header WWW-Authenticate
+func abortUnauthorized(c *gin.Context, detail string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	abortWithProblem(c, http.StatusUnauthorized, entities.ProblemCodeUnauthorized, detail)
+}
+
+// abortWithProblem dừng request với body problem+json (RFC 7807)
+func abortWithProblem(c *gin.Context, status int, code, detail string) {
+	problem := entities.NewProblem(status, code, detail)
+	problem.Instance = c.Request.URL.Path
+	c.Header("Content-Type", entities.ProblemContentType)
+	c.AbortWithStatusJSON(status, problem)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/url-shorted2/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware ghi số request và latency theo route template (không theo path thật
+// để tránh bùng nổ label, ví dụ /:shortCode thay vì /abc123)
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		utils.HTTPRequestsTotal.WithLabelValues(c.Request.Method, route, status).Inc()
+		utils.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
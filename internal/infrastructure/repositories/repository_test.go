@@ -47,13 +47,13 @@ func (m *MockURLRepository) Delete(id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockURLRepository) IncrementClickCount(shortCode string) error {
+func (m *MockURLRepository) IncrementClickCount(shortCode string) (int64, error) {
 	args := m.Called(shortCode)
-	return args.Error(0)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockURLRepository) GetAnalytics(urlID uint) ([]entities.Analytics, error) {
-	args := m.Called(urlID)
+func (m *MockURLRepository) ListAnalytics(urlID uint, filter entities.ClickFilter) ([]entities.Analytics, error) {
+	args := m.Called(urlID, filter)
 	return args.Get(0).([]entities.Analytics), args.Error(1)
 }
 
@@ -351,7 +351,7 @@ func TestURLRepositoryImpl_IncrementClickCount(t *testing.T) {
 			name:      "Tăng click count thành công",
 			shortCode: "abc123",
 			setup: func(mockRepo *MockURLRepository) {
-				mockRepo.On("IncrementClickCount", "abc123").Return(nil)
+				mockRepo.On("IncrementClickCount", "abc123").Return(int64(1), nil)
 			},
 			wantErr: false,
 		},
@@ -359,7 +359,7 @@ func TestURLRepositoryImpl_IncrementClickCount(t *testing.T) {
 			name:      "Tăng click count thất bại",
 			shortCode: "notfound",
 			setup: func(mockRepo *MockURLRepository) {
-				mockRepo.On("IncrementClickCount", "notfound").Return(gorm.ErrRecordNotFound)
+				mockRepo.On("IncrementClickCount", "notfound").Return(int64(0), gorm.ErrRecordNotFound)
 			},
 			wantErr: true,
 		},
@@ -370,7 +370,7 @@ func TestURLRepositoryImpl_IncrementClickCount(t *testing.T) {
 			mockRepo := &MockURLRepository{}
 			tt.setup(mockRepo)
 
-			err := mockRepo.IncrementClickCount(tt.shortCode)
+			_, err := mockRepo.IncrementClickCount(tt.shortCode)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -383,8 +383,8 @@ func TestURLRepositoryImpl_IncrementClickCount(t *testing.T) {
 	}
 }
 
-// TestURLRepositoryImpl_GetAnalytics tests GetAnalytics method
-func TestURLRepositoryImpl_GetAnalytics(t *testing.T) {
+// TestURLRepositoryImpl_ListAnalytics tests ListAnalytics method
+func TestURLRepositoryImpl_ListAnalytics(t *testing.T) {
 	tests := []struct {
 		name    string
 		urlID   uint
@@ -404,7 +404,7 @@ func TestURLRepositoryImpl_GetAnalytics(t *testing.T) {
 						ClickedAt: time.Now(),
 					},
 				}
-				mockRepo.On("GetAnalytics", uint(1)).Return(analytics, nil)
+				mockRepo.On("ListAnalytics", uint(1), entities.ClickFilter{Limit: 50}).Return(analytics, nil)
 			},
 			want: []entities.Analytics{
 				{
@@ -419,7 +419,7 @@ func TestURLRepositoryImpl_GetAnalytics(t *testing.T) {
 			name:  "Không có analytics",
 			urlID: 1,
 			setup: func(mockRepo *MockURLRepository) {
-				mockRepo.On("GetAnalytics", uint(1)).Return([]entities.Analytics{}, gorm.ErrRecordNotFound)
+				mockRepo.On("ListAnalytics", uint(1), entities.ClickFilter{Limit: 50}).Return([]entities.Analytics{}, gorm.ErrRecordNotFound)
 			},
 			want:    []entities.Analytics{},
 			wantErr: true,
@@ -431,7 +431,7 @@ func TestURLRepositoryImpl_GetAnalytics(t *testing.T) {
 			mockRepo := &MockURLRepository{}
 			tt.setup(mockRepo)
 
-			got, err := mockRepo.GetAnalytics(tt.urlID)
+			got, err := mockRepo.ListAnalytics(tt.urlID, entities.ClickFilter{Limit: 50})
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -0,0 +1,302 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+// timeSeriesSlotSeconds là độ rộng slot gom nhóm trong SQL (15 phút).
+// SQLite không có tz database nên ta GROUP BY theo slot UTC rồi gộp slot vào
+// bucket theo múi giờ của client; mọi múi giờ thực tế đều lệch bội số 15 phút.
+const timeSeriesSlotSeconds = 900
+
+// streamPageSize là số dòng mỗi trang của StreamAnalytics
+const streamPageSize = 500
+
+// slotCount là một dòng kết quả GROUP BY theo slot
+type slotCount struct {
+	Slot   int64
+	Clicks int64
+}
+
+// ListAnalytics lấy một trang analytics của URL theo keyset (id giảm dần) và bộ lọc
+func (r *urlRepositoryImpl) ListAnalytics(urlID uint, filter entities.ClickFilter) ([]entities.Analytics, error) {
+	r, span := r.startSpan("ListAnalytics")
+	defer span.End()
+
+	order := "id DESC"
+	if filter.Ascending {
+		order = "id"
+	}
+
+	var analytics []entities.Analytics
+	err := r.filterAnalytics(urlID, filter).
+		Order(order).
+		Limit(filter.Limit).
+		Find(&analytics).Error
+	return analytics, err
+}
+
+// StreamAnalytics duyệt analytics của URL theo thứ tự id tăng dần, mỗi lần đọc một trang
+// streamPageSize dòng theo keyset id rồi gọi fn cho từng dòng. Query của trang đã đóng trước khi
+// gọi fn nên client tải chậm không giữ read transaction của SQLite; click mới ghi trong lúc
+// stream có id lớn hơn nên cũng được trả về. Dừng lại khi fn trả về lỗi.
+func (r *urlRepositoryImpl) StreamAnalytics(urlID uint, filter entities.ClickFilter, fn func(*entities.Analytics) error) error {
+	r, span := r.startSpan("StreamAnalytics")
+	defer span.End()
+
+	for {
+		var page []entities.Analytics
+		err := r.filterAnalytics(urlID, filter).
+			Order("id").
+			Limit(streamPageSize).
+			Find(&page).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range page {
+			if err := fn(&page[i]); err != nil {
+				return err
+			}
+		}
+		if len(page) < streamPageSize {
+			return nil
+		}
+		filter.AfterID = page[len(page)-1].ID
+	}
+}
+
+// filterAnalytics áp dụng điều kiện của ClickFilter (trừ Limit) lên query analytics
+func (r *urlRepositoryImpl) filterAnalytics(urlID uint, filter entities.ClickFilter) *gorm.DB {
+	query := r.db.Where("url_id = ?", urlID)
+	if filter.BeforeID > 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+	if filter.AfterID > 0 {
+		query = query.Where("id > ?", filter.AfterID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("clicked_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("clicked_at < ?", filter.To.UTC())
+	}
+	if filter.Country != "" {
+		query = query.Where("country = ?", filter.Country)
+	}
+	if filter.ReferrerHost != "" {
+		query = query.Where("referrer_host = ?", filter.ReferrerHost)
+	}
+	return query
+}
+
+// GetLastClickedAt lấy thời điểm click gần nhất, nil nếu chưa có click
+func (r *urlRepositoryImpl) GetLastClickedAt(urlID uint) (*time.Time, error) {
+	r, span := r.startSpan("GetLastClickedAt")
+	defer span.End()
+
+	var analytics entities.Analytics
+	err := r.db.Select("clicked_at").
+		Where("url_id = ?", urlID).
+		Order("clicked_at DESC").
+		Limit(1).
+		Find(&analytics).Error
+	if err != nil || analytics.ClickedAt.IsZero() {
+		return nil, err
+	}
+	return &analytics.ClickedAt, nil
+}
+
+// GetClickTimeSeries đếm click theo bucket thời gian trong khoảng [from, to), có zero-fill.
+// Phần trước rollupBefore được đọc từ rollup theo giờ (time zero nghĩa là chỉ đọc click thô),
+// nên loc phải có offset tròn giờ trên phần đó.
+func (r *urlRepositoryImpl) GetClickTimeSeries(urlID uint, from, to time.Time, interval string, loc *time.Location, rollupBefore time.Time) ([]entities.TimeSeriesBucket, error) {
+	r, span := r.startSpan("GetClickTimeSeries")
+	defer span.End()
+
+	rawFrom := from
+	if rawFrom.Before(rollupBefore) {
+		rawFrom = rollupBefore
+	}
+
+	var slots []slotCount
+	if rawFrom.Before(to) {
+		err := r.db.Model(&entities.Analytics{}).
+			Select("CAST(strftime('%s', clicked_at) AS INTEGER) / ? AS slot, COUNT(*) AS clicks", timeSeriesSlotSeconds).
+			Where("url_id = ? AND clicked_at >= ? AND clicked_at < ?", urlID, rawFrom.UTC(), to.UTC()).
+			Group("slot").
+			Scan(&slots).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if from.Before(rollupBefore) {
+		rollupTo := to
+		if rollupBefore.Before(rollupTo) {
+			rollupTo = rollupBefore
+		}
+
+		// Bucket rollup theo giờ được dồn vào slot chứa bucket_start, chỉ đúng khi mốc bucket trùng đầu giờ UTC.
+		// Với timezone lệch :30/:45 usecase chỉ đọc click thô (rollupBefore zero hoặc trước from).
+		var rolled []slotCount
+		err := r.db.Table(entities.HourlyRollup{}.TableName()).
+			Select("CAST(strftime('%s', bucket_start) AS INTEGER) / ? AS slot, SUM(clicks) AS clicks", timeSeriesSlotSeconds).
+			Where("url_id = ? AND bucket_start >= ? AND bucket_start < ?", urlID, from.UTC(), rollupTo.UTC()).
+			Group("slot").
+			Scan(&rolled).Error
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, rolled...)
+	}
+
+	counts := make(map[int64]int64, len(slots))
+	for _, s := range slots {
+		start := truncateToBucket(time.Unix(s.Slot*timeSeriesSlotSeconds, 0), interval, loc)
+		counts[start.Unix()] += s.Clicks
+	}
+
+	var buckets []entities.TimeSeriesBucket
+	for start := truncateToBucket(from, interval, loc); start.Before(to); start = nextBucket(start, interval, loc) {
+		buckets = append(buckets, entities.TimeSeriesBucket{
+			Start:  start,
+			Clicks: counts[start.Unix()],
+		})
+	}
+	return buckets, nil
+}
+
+// breakdownColumns whitelist các cột được phép GROUP BY
+var breakdownColumns = map[string]bool{
+	entities.DimensionBrowser: true,
+	entities.DimensionOS:      true,
+	entities.DimensionDevice:  true,
+	entities.DimensionCountry: true,
+
+	entities.DimensionReferrerHost:    true,
+	entities.DimensionReferrerChannel: true,
+}
+
+// GetClickBreakdown đếm click theo giá trị của một dimension, sắp xếp giảm dần.
+// Click trước rollupBefore được đọc từ rollup theo ngày.
+func (r *urlRepositoryImpl) GetClickBreakdown(urlID uint, dimension string, rollupBefore time.Time, limit int) ([]entities.DimensionCount, error) {
+	r, span := r.startSpan("GetClickBreakdown")
+	defer span.End()
+
+	if !breakdownColumns[dimension] {
+		return nil, fmt.Errorf("unsupported dimension: %s", dimension)
+	}
+
+	value := fmt.Sprintf("COALESCE(NULLIF(%s, ''), 'unknown') AS value", dimension)
+	raw, rolled := r.splitAtRollup(urlID, rollupBefore)
+
+	var counts []entities.DimensionCount
+	err := r.db.Raw("SELECT value, SUM(clicks) AS clicks FROM (SELECT * FROM (?) UNION ALL SELECT * FROM (?)) GROUP BY value ORDER BY clicks DESC, value LIMIT ?",
+		raw.Select(value+", COUNT(*) AS clicks").Group("value"),
+		rolled.Select(value+", SUM(clicks) AS clicks").Group("value"),
+		limit,
+	).Scan(&counts).Error
+	return counts, err
+}
+
+// GetTopReferrers lấy các referrer host có nhiều click nhất, bỏ qua traffic direct.
+// Click trước rollupBefore được đọc từ rollup theo ngày.
+func (r *urlRepositoryImpl) GetTopReferrers(urlID uint, rollupBefore time.Time, limit int) ([]entities.ReferrerCount, error) {
+	r, span := r.startSpan("GetTopReferrers")
+	defer span.End()
+
+	const columns = "referrer_host AS host, referrer_channel AS channel"
+	raw, rolled := r.splitAtRollup(urlID, rollupBefore)
+
+	var counts []entities.ReferrerCount
+	err := r.db.Raw("SELECT host, channel, SUM(clicks) AS clicks FROM (SELECT * FROM (?) UNION ALL SELECT * FROM (?)) GROUP BY host, channel ORDER BY clicks DESC, host LIMIT ?",
+		raw.Select(columns+", COUNT(*) AS clicks").Where("referrer_host <> ''").Group("referrer_host, referrer_channel"),
+		rolled.Select(columns+", SUM(clicks) AS clicks").Where("referrer_host <> ''").Group("referrer_host, referrer_channel"),
+		limit,
+	).Scan(&counts).Error
+	return counts, err
+}
+
+// splitAtRollup tạo hai query cho một link: click thô từ rollupBefore trở đi
+// và rollup theo ngày trước rollupBefore
+func (r *urlRepositoryImpl) splitAtRollup(urlID uint, rollupBefore time.Time) (raw, rolled *gorm.DB) {
+	raw = r.db.Model(&entities.Analytics{}).
+		Where("url_id = ? AND clicked_at >= ?", urlID, rollupBefore.UTC())
+	rolled = r.db.Table(entities.DailyRollup{}.TableName()).
+		Where("url_id = ? AND bucket_start < ?", urlID, rollupBefore.UTC())
+	return raw, rolled
+}
+
+// CountUniqueVisitors đếm số visitor hash khác nhau trong khoảng [from, to)
+func (r *urlRepositoryImpl) CountUniqueVisitors(urlID uint, from, to time.Time) (int64, error) {
+	r, span := r.startSpan("CountUniqueVisitors")
+	defer span.End()
+
+	var count int64
+	err := r.db.Model(&entities.Analytics{}).
+		Where("url_id = ? AND visitor_hash <> '' AND clicked_at >= ? AND clicked_at < ?", urlID, from.UTC(), to.UTC()).
+		Distinct("visitor_hash").
+		Count(&count).Error
+	return count, err
+}
+
+// eraseBatchSize giới hạn số tham số trong mỗi câu DELETE ... IN
+const eraseBatchSize = 500
+
+// DeleteAnalytics xóa click thô có ip_address thuộc ipAddresses hoặc visitor_hash thuộc fingerprints
+func (r *urlRepositoryImpl) DeleteAnalytics(ipAddresses, fingerprints []string) (int64, error) {
+	r, span := r.startSpan("DeleteAnalytics")
+	defer span.End()
+
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for column, values := range map[string][]string{"ip_address": ipAddresses, "visitor_hash": fingerprints} {
+			for start := 0; start < len(values); start += eraseBatchSize {
+				end := start + eraseBatchSize
+				if end > len(values) {
+					end = len(values)
+				}
+				result := tx.Where(column+" IN ?", values[start:end]).Delete(&entities.Analytics{})
+				if result.Error != nil {
+					return result.Error
+				}
+				deleted += result.RowsAffected
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// truncateToBucket trả về thời điểm bắt đầu bucket chứa t theo múi giờ loc.
+// Tuần bắt đầu từ thứ Hai (ISO 8601).
+func truncateToBucket(t time.Time, interval string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch interval {
+	case entities.IntervalHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+	case entities.IntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// nextBucket trả về thời điểm bắt đầu bucket kế tiếp
+func nextBucket(start time.Time, interval string, loc *time.Location) time.Time {
+	switch interval {
+	case entities.IntervalHour:
+		return start.Add(time.Hour)
+	case entities.IntervalWeek:
+		return truncateToBucket(start.AddDate(0, 0, 7), interval, loc)
+	default:
+		return truncateToBucket(start.AddDate(0, 0, 1), interval, loc)
+	}
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// apiKeyRepositoryImpl implement IAPIKeyRepository
+type apiKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepositoryImpl tạo instance mới của APIKeyRepository
+func NewAPIKeyRepositoryImpl(db *gorm.DB) repositories.IAPIKeyRepository {
+	return &apiKeyRepositoryImpl{
+		db: db,
+	}
+}
+
+// WithContext trả về repository chạy query với ctx
+func (r *apiKeyRepositoryImpl) WithContext(ctx context.Context) repositories.IAPIKeyRepository {
+	return &apiKeyRepositoryImpl{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// startSpan mở span cho một method của repository
+func (r *apiKeyRepositoryImpl) startSpan(name string) (*apiKeyRepositoryImpl, trace.Span) {
+	ctx, span := utils.StartSpan(r.db.Statement.Context, "apiKeyRepository."+name)
+	return &apiKeyRepositoryImpl{db: r.db.WithContext(ctx)}, span
+}
+
+// CreateAPIKey tạo API key mới
+func (r *apiKeyRepositoryImpl) CreateAPIKey(key *entities.APIKey) error {
+	r, span := r.startSpan("CreateAPIKey")
+	defer span.End()
+
+	return r.db.Create(key).Error
+}
+
+// GetAPIKey lấy API key theo ID
+func (r *apiKeyRepositoryImpl) GetAPIKey(id uint) (*entities.APIKey, error) {
+	r, span := r.startSpan("GetAPIKey")
+	defer span.End()
+
+	var key entities.APIKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetAPIKeyByHash lấy API key theo SHA-256 của key
+func (r *apiKeyRepositoryImpl) GetAPIKeyByHash(keyHash string) (*entities.APIKey, error) {
+	r, span := r.startSpan("GetAPIKeyByHash")
+	defer span.End()
+
+	var key entities.APIKey
+	if err := r.db.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListAPIKeys lấy tất cả API key, kể cả key đã revoke
+func (r *apiKeyRepositoryImpl) ListAPIKeys() ([]entities.APIKey, error) {
+	r, span := r.startSpan("ListAPIKeys")
+	defer span.End()
+
+	var keys []entities.APIKey
+	err := r.db.Order("id").Find(&keys).Error
+	return keys, err
+}
+
+// UpdateAPIKey cập nhật API key (rotate, revoke)
+func (r *apiKeyRepositoryImpl) UpdateAPIKey(key *entities.APIKey) error {
+	r, span := r.startSpan("UpdateAPIKey")
+	defer span.End()
+
+	return r.db.Save(key).Error
+}
+
+// TouchAPIKey ghi thời điểm key được dùng gần nhất
+func (r *apiKeyRepositoryImpl) TouchAPIKey(id uint, usedAt time.Time) error {
+	r, span := r.startSpan("TouchAPIKey")
+	defer span.End()
+
+	return r.db.Model(&entities.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// folderRepositoryImpl implement IFolderRepository
+type folderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFolderRepositoryImpl tạo instance mới của FolderRepository
+func NewFolderRepositoryImpl(db *gorm.DB) repositories.IFolderRepository {
+	return &folderRepositoryImpl{
+		db: db,
+	}
+}
+
+// WithContext trả về repository chạy query với ctx
+func (r *folderRepositoryImpl) WithContext(ctx context.Context) repositories.IFolderRepository {
+	return &folderRepositoryImpl{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// startSpan mở span cho một method của repository
+func (r *folderRepositoryImpl) startSpan(name string) (*folderRepositoryImpl, trace.Span) {
+	ctx, span := utils.StartSpan(r.db.Statement.Context, "folderRepository."+name)
+	return &folderRepositoryImpl{db: r.db.WithContext(ctx)}, span
+}
+
+// CreateFolder tạo folder mới
+func (r *folderRepositoryImpl) CreateFolder(folder *entities.Folder) error {
+	r, span := r.startSpan("CreateFolder")
+	defer span.End()
+
+	return r.db.Create(folder).Error
+}
+
+// GetFolder lấy folder theo ID
+func (r *folderRepositoryImpl) GetFolder(id uint) (*entities.Folder, error) {
+	r, span := r.startSpan("GetFolder")
+	defer span.End()
+
+	var folder entities.Folder
+	if err := r.db.First(&folder, id).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// ListFolders lấy tất cả folder
+func (r *folderRepositoryImpl) ListFolders() ([]entities.Folder, error) {
+	r, span := r.startSpan("ListFolders")
+	defer span.End()
+
+	var folders []entities.Folder
+	err := r.db.Order("id").Find(&folders).Error
+	return folders, err
+}
+
+// CountURLsByFolder đếm số URL trong scope nằm trực tiếp trong từng folder
+func (r *folderRepositoryImpl) CountURLsByFolder(scope entities.URLScope) (map[uint]int64, error) {
+	r, span := r.startSpan("CountURLsByFolder")
+	defer span.End()
+
+	var rows []struct {
+		FolderID uint
+		Count    int64
+	}
+	query := r.db.Model(&entities.URL{}).
+		Select("urls.folder_id, COUNT(*) AS count").
+		Where("urls.folder_id IS NOT NULL").
+		Group("urls.folder_id")
+	err := applyURLScope(query, scope).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.FolderID] = row.Count
+	}
+	return counts, nil
+}
+
+// UpdateFolder cập nhật tên và folder cha
+func (r *folderRepositoryImpl) UpdateFolder(folder *entities.Folder) error {
+	r, span := r.startSpan("UpdateFolder")
+	defer span.End()
+
+	return r.db.Save(folder).Error
+}
+
+// DeleteFolder xóa folder
+func (r *folderRepositoryImpl) DeleteFolder(id uint) error {
+	r, span := r.startSpan("DeleteFolder")
+	defer span.End()
+
+	result := r.db.Delete(&entities.Folder{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetURLFolder chuyển URL vào folder, folderID nil là bỏ khỏi folder
+func (r *folderRepositoryImpl) SetURLFolder(urlID uint, folderID *uint) error {
+	r, span := r.startSpan("SetURLFolder")
+	defer span.End()
+
+	return r.db.Model(&entities.URL{}).Where("id = ?", urlID).Update("folder_id", folderID).Error
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+// rollupBatchSize là số dòng rollup insert mỗi lần
+const rollupBatchSize = 500
+
+// rollupDimensions là các cột dimension được giữ lại trong bảng rollup
+var rollupDimensions = []string{"country", "referrer_host", "referrer_channel", "device_type", "browser", "os"}
+
+// rollupBucketSeconds là độ dài bucket (UTC) của từng loại rollup
+var rollupBucketSeconds = map[string]int64{
+	entities.IntervalHour: 3600,
+	entities.IntervalDay:  86400,
+}
+
+// rollupRow là một dòng kết quả GROUP BY khi gom click thô
+type rollupRow struct {
+	URLID           uint
+	Slot            int64
+	Country         string
+	ReferrerHost    string
+	ReferrerChannel string
+	DeviceType      string
+	Browser         string
+	OS              string
+	Clicks          int64
+}
+
+// rollupTable trả về tên bảng rollup theo granularity (hour hoặc day)
+func rollupTable(granularity string) (string, error) {
+	switch granularity {
+	case entities.IntervalHour:
+		return entities.HourlyRollup{}.TableName(), nil
+	case entities.IntervalDay:
+		return entities.DailyRollup{}.TableName(), nil
+	default:
+		return "", fmt.Errorf("unsupported rollup granularity: %s", granularity)
+	}
+}
+
+// RollupAnalytics gom click thô trong [from, to) vào bảng rollup theo granularity
+// và lưu to làm watermark. Các bucket trong khoảng được tính lại từ đầu nên có thể chạy lại nhiều lần.
+func (r *urlRepositoryImpl) RollupAnalytics(granularity string, from, to time.Time) (int64, error) {
+	r, span := r.startSpan("RollupAnalytics")
+	defer span.End()
+
+	table, err := rollupTable(granularity)
+	if err != nil {
+		return 0, err
+	}
+	size := rollupBucketSeconds[granularity]
+
+	selects := []string{"url_id", "CAST(strftime('%s', clicked_at) AS INTEGER) / ? AS slot", "COUNT(*) AS clicks"}
+	groups := []string{"url_id", "slot"}
+	for _, column := range rollupDimensions {
+		selects = append(selects, fmt.Sprintf("COALESCE(%s, '') AS %s", column, column))
+		groups = append(groups, fmt.Sprintf("COALESCE(%s, '')", column))
+	}
+
+	var written int64
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(table).
+			Where("bucket_start >= ? AND bucket_start < ?", from.UTC(), to.UTC()).
+			Delete(&entities.ClickRollup{}).Error; err != nil {
+			return err
+		}
+
+		var rows []rollupRow
+		if err := tx.Model(&entities.Analytics{}).
+			Select(strings.Join(selects, ", "), size).
+			Where("clicked_at >= ? AND clicked_at < ?", from.UTC(), to.UTC()).
+			Group(strings.Join(groups, ", ")).
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		state := &entities.RollupState{Granularity: granularity, RolledUntil: to.UTC()}
+		if err := tx.Save(state).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		rollups := make([]entities.ClickRollup, 0, len(rows))
+		for _, row := range rows {
+			rollups = append(rollups, entities.ClickRollup{
+				URLID:           row.URLID,
+				BucketStart:     time.Unix(row.Slot*size, 0).UTC(),
+				Country:         row.Country,
+				ReferrerHost:    row.ReferrerHost,
+				ReferrerChannel: row.ReferrerChannel,
+				DeviceType:      row.DeviceType,
+				Browser:         row.Browser,
+				OS:              row.OS,
+				Clicks:          row.Clicks,
+			})
+		}
+		written = int64(len(rollups))
+		return tx.Table(table).CreateInBatches(&rollups, rollupBatchSize).Error
+	})
+	return written, err
+}
+
+// GetRollupWatermark lấy mốc mà click thô trước đó đã được rollup, nil nếu chưa rollup lần nào
+func (r *urlRepositoryImpl) GetRollupWatermark(granularity string) (*time.Time, error) {
+	r, span := r.startSpan("GetRollupWatermark")
+	defer span.End()
+
+	var state entities.RollupState
+	err := r.db.Where("granularity = ?", granularity).Limit(1).Find(&state).Error
+	if err != nil || state.RolledUntil.IsZero() {
+		return nil, err
+	}
+	return &state.RolledUntil, nil
+}
+
+// GetOldestClickedAt lấy thời điểm của click thô cũ nhất, nil nếu chưa có click
+func (r *urlRepositoryImpl) GetOldestClickedAt() (*time.Time, error) {
+	r, span := r.startSpan("GetOldestClickedAt")
+	defer span.End()
+
+	var analytics entities.Analytics
+	err := r.db.Select("clicked_at").
+		Order("clicked_at").
+		Limit(1).
+		Find(&analytics).Error
+	if err != nil || analytics.ClickedAt.IsZero() {
+		return nil, err
+	}
+	return &analytics.ClickedAt, nil
+}
+
+// PruneAnalytics xóa click thô cũ hơn before
+func (r *urlRepositoryImpl) PruneAnalytics(before time.Time) (int64, error) {
+	r, span := r.startSpan("PruneAnalytics")
+	defer span.End()
+
+	result := r.db.Where("clicked_at < ?", before.UTC()).Delete(&entities.Analytics{})
+	return result.RowsAffected, result.Error
+}
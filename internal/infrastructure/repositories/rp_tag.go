@@ -0,0 +1,253 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// tagRepositoryImpl implement ITagRepository
+type tagRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTagRepositoryImpl tạo instance mới của TagRepository
+func NewTagRepositoryImpl(db *gorm.DB) repositories.ITagRepository {
+	return &tagRepositoryImpl{
+		db: db,
+	}
+}
+
+// WithContext trả về repository chạy query với ctx
+func (r *tagRepositoryImpl) WithContext(ctx context.Context) repositories.ITagRepository {
+	return &tagRepositoryImpl{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// startSpan mở span cho một method của repository
+func (r *tagRepositoryImpl) startSpan(name string) (*tagRepositoryImpl, trace.Span) {
+	ctx, span := utils.StartSpan(r.db.Statement.Context, "tagRepository."+name)
+	return &tagRepositoryImpl{db: r.db.WithContext(ctx)}, span
+}
+
+// CreateTag tạo tag mới
+func (r *tagRepositoryImpl) CreateTag(tag *entities.Tag) error {
+	r, span := r.startSpan("CreateTag")
+	defer span.End()
+
+	return r.db.Create(tag).Error
+}
+
+// GetTagByName lấy tag theo tên đã chuẩn hóa
+func (r *tagRepositoryImpl) GetTagByName(name string) (*entities.Tag, error) {
+	r, span := r.startSpan("GetTagByName")
+	defer span.End()
+
+	var tag entities.Tag
+	if err := r.db.Where("name = ?", name).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// ListTags lấy tất cả tag theo thứ tự tên
+func (r *tagRepositoryImpl) ListTags() ([]entities.Tag, error) {
+	r, span := r.startSpan("ListTags")
+	defer span.End()
+
+	var tags []entities.Tag
+	err := r.db.Order("name").Find(&tags).Error
+	return tags, err
+}
+
+// CountURLsByTag đếm số URL trong scope của từng tag
+func (r *tagRepositoryImpl) CountURLsByTag(scope entities.URLScope) (map[uint]int64, error) {
+	r, span := r.startSpan("CountURLsByTag")
+	defer span.End()
+
+	var rows []struct {
+		TagID uint
+		Count int64
+	}
+	query := r.db.Table("url_tags").Select("url_tags.tag_id, COUNT(*) AS count").Group("url_tags.tag_id")
+	if !scope.IsAll() {
+		query = applyURLScope(query.Joins("JOIN urls ON urls.id = url_tags.url_id"), scope)
+	}
+	err := query.Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+// UpdateTag cập nhật tag (đổi tên)
+func (r *tagRepositoryImpl) UpdateTag(tag *entities.Tag) error {
+	r, span := r.startSpan("UpdateTag")
+	defer span.End()
+
+	return r.db.Save(tag).Error
+}
+
+// DeleteTag xóa tag và gỡ tag khỏi mọi URL
+func (r *tagRepositoryImpl) DeleteTag(id uint) error {
+	r, span := r.startSpan("DeleteTag")
+	defer span.End()
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM url_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// FindOrCreateTags lấy các tag theo tên, tạo tag chưa tồn tại. Kết quả theo thứ tự names.
+func (r *tagRepositoryImpl) FindOrCreateTags(names []string) ([]entities.Tag, error) {
+	r, span := r.startSpan("FindOrCreateTags")
+	defer span.End()
+
+	tags := make([]entities.Tag, 0, len(names))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, name := range names {
+			tag := entities.Tag{Name: name}
+			if err := tx.Where(entities.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+				return err
+			}
+			tags = append(tags, tag)
+		}
+		return nil
+	})
+	return tags, err
+}
+
+// ReplaceURLTags thay toàn bộ tag của URL bằng tags
+func (r *tagRepositoryImpl) ReplaceURLTags(urlID uint, tags []entities.Tag) error {
+	r, span := r.startSpan("ReplaceURLTags")
+	defer span.End()
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM url_tags WHERE url_id = ?", urlID).Error; err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if err := tx.Exec("INSERT INTO url_tags (url_id, tag_id) VALUES (?, ?)", urlID, tag.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetURLTags lấy tag của URL theo thứ tự tên
+func (r *tagRepositoryImpl) GetURLTags(urlID uint) ([]entities.Tag, error) {
+	r, span := r.startSpan("GetURLTags")
+	defer span.End()
+
+	var tags []entities.Tag
+	err := r.db.
+		Joins("JOIN url_tags ON url_tags.tag_id = tags.id").
+		Where("url_tags.url_id = ?", urlID).
+		Order("tags.name").
+		Find(&tags).Error
+	return tags, err
+}
+
+// GetTagStats tổng hợp số URL, URL đang active, tổng click và lần click cuối của các URL gắn tag
+func (r *tagRepositoryImpl) GetTagStats(tagID uint, scope entities.URLScope) (*entities.TagStats, error) {
+	r, span := r.startSpan("GetTagStats")
+	defer span.End()
+
+	var stats entities.TagStats
+	err := r.db.Model(&entities.URL{}).
+		Select("COUNT(*) AS url_count, "+
+			"COALESCE(SUM(CASE WHEN urls.is_active THEN 1 ELSE 0 END), 0) AS active_url_count, "+
+			"COALESCE(SUM(urls.click_count), 0) AS total_clicks").
+		Where("urls.id IN (?)", r.taggedURLIDs(tagID, scope)).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var last entities.Analytics
+	err = r.db.Select("clicked_at").
+		Where("url_id IN (?)", r.taggedURLIDs(tagID, scope)).
+		Order("clicked_at DESC").
+		Limit(1).
+		Find(&last).Error
+	if err != nil {
+		return nil, err
+	}
+	if !last.ClickedAt.IsZero() {
+		stats.LastClicked = &last.ClickedAt
+	}
+	return &stats, nil
+}
+
+// ListTopURLsByTag lấy các URL gắn tag có nhiều click nhất, kèm tag của từng URL
+func (r *tagRepositoryImpl) ListTopURLsByTag(tagID uint, scope entities.URLScope, limit int) ([]entities.URL, error) {
+	r, span := r.startSpan("ListTopURLsByTag")
+	defer span.End()
+
+	var urls []entities.URL
+	err := r.db.
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name") }).
+		Where("id IN (?)", r.taggedURLIDs(tagID, scope)).
+		Order("click_count DESC, id").
+		Limit(limit).
+		Find(&urls).Error
+	return urls, err
+}
+
+// CountTagUniqueVisitors đếm fingerprint khác nhau trong click thô của mọi URL gắn tag
+func (r *tagRepositoryImpl) CountTagUniqueVisitors(tagID uint, scope entities.URLScope) (int64, error) {
+	r, span := r.startSpan("CountTagUniqueVisitors")
+	defer span.End()
+
+	var count int64
+	err := r.db.Model(&entities.Analytics{}).
+		Where("url_id IN (?) AND visitor_hash <> ''", r.taggedURLIDs(tagID, scope)).
+		Distinct("visitor_hash").
+		Count(&count).Error
+	return count, err
+}
+
+// taggedURLIDs là subquery id của các URL gắn tag, giới hạn theo owner và workspace của scope
+func (r *tagRepositoryImpl) taggedURLIDs(tagID uint, scope entities.URLScope) *gorm.DB {
+	query := r.db.Session(&gorm.Session{NewDB: true}).Table("url_tags").Select("url_tags.url_id").Where("url_tags.tag_id = ?", tagID)
+	if scope.IsAll() {
+		return query
+	}
+	return applyURLScope(query.Joins("JOIN urls ON urls.id = url_tags.url_id"), scope)
+}
+
+// applyURLScope lọc query đã join bảng urls theo owner và workspace của scope
+func applyURLScope(query *gorm.DB, scope entities.URLScope) *gorm.DB {
+	if scope.OwnerID != nil {
+		query = query.Where("urls.owner_id = ?", *scope.OwnerID)
+	}
+	if scope.WorkspaceID != nil {
+		if *scope.WorkspaceID == 0 {
+			query = query.Where("urls.workspace_id IS NULL")
+		} else {
+			query = query.Where("urls.workspace_id = ?", *scope.WorkspaceID)
+		}
+	}
+	return query
+}
@@ -1,10 +1,15 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/url-shorted2/internal/domain/entities"
 	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
 
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // urlRepositoryImpl implement URLRepository interface
@@ -19,13 +24,46 @@ func NewURLRepositoryImpl(db *gorm.DB) repositories.IURLRepository {
 	}
 }
 
+// WithContext trả về repository chạy query với ctx
+func (r *urlRepositoryImpl) WithContext(ctx context.Context) repositories.IURLRepository {
+	return &urlRepositoryImpl{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// startSpan mở span cho một method của repository. Repository trả về gắn span vào
+// context của gorm nên span của từng query (GORM plugin) nằm dưới span này.
+func (r *urlRepositoryImpl) startSpan(name string) (*urlRepositoryImpl, trace.Span) {
+	ctx, span := utils.StartSpan(r.db.Statement.Context, "urlRepository."+name)
+	return &urlRepositoryImpl{db: r.db.WithContext(ctx)}, span
+}
+
 // Create tạo URL mới
 func (r *urlRepositoryImpl) Create(url *entities.URL) error {
+	r, span := r.startSpan("Create")
+	defer span.End()
+
 	return r.db.Create(url).Error
 }
 
+// CreateBatch tạo nhiều URL trong một transaction, lỗi ở bất kỳ URL nào thì không URL nào được tạo
+func (r *urlRepositoryImpl) CreateBatch(urls []*entities.URL) error {
+	r, span := r.startSpan("CreateBatch")
+	defer span.End()
+
+	if len(urls) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(urls, 100).Error
+	})
+}
+
 // GetByShortCode lấy URL theo short code
 func (r *urlRepositoryImpl) GetByShortCode(shortCode string) (*entities.URL, error) {
+	r, span := r.startSpan("GetByShortCode")
+	defer span.End()
+
 	var url entities.URL
 	err := r.db.Where("short_code = ?", shortCode).First(&url).Error
 	if err != nil {
@@ -34,8 +72,33 @@ func (r *urlRepositoryImpl) GetByShortCode(shortCode string) (*entities.URL, err
 	return &url, nil
 }
 
+// FindExistingShortCodes trả về các short code trong codes đã tồn tại
+func (r *urlRepositoryImpl) FindExistingShortCodes(codes []string) ([]string, error) {
+	r, span := r.startSpan("FindExistingShortCodes")
+	defer span.End()
+
+	// Chia nhỏ để không vượt giới hạn số tham số của một câu lệnh SQLite
+	const chunkSize = 500
+	var existing []string
+	for start := 0; start < len(codes); start += chunkSize {
+		end := min(start+chunkSize, len(codes))
+		var found []string
+		err := r.db.Model(&entities.URL{}).
+			Where("short_code IN ?", codes[start:end]).
+			Pluck("short_code", &found).Error
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, found...)
+	}
+	return existing, nil
+}
+
 // GetByID lấy URL theo ID
 func (r *urlRepositoryImpl) GetByID(id uint) (*entities.URL, error) {
+	r, span := r.startSpan("GetByID")
+	defer span.End()
+
 	var url entities.URL
 	err := r.db.First(&url, id).Error
 	if err != nil {
@@ -46,37 +109,57 @@ func (r *urlRepositoryImpl) GetByID(id uint) (*entities.URL, error) {
 
 // Update cập nhật URL
 func (r *urlRepositoryImpl) Update(url *entities.URL) error {
+	r, span := r.startSpan("Update")
+	defer span.End()
+
 	return r.db.Save(url).Error
 }
 
-// Delete xóa URL
+// Delete xóa URL và gỡ các tag của URL
 func (r *urlRepositoryImpl) Delete(id uint) error {
-	return r.db.Delete(&entities.URL{}, id).Error
-}
+	r, span := r.startSpan("Delete")
+	defer span.End()
 
-// IncrementClickCount tăng số lần click
-func (r *urlRepositoryImpl) IncrementClickCount(shortCode string) error {
-	return r.db.Model(&entities.URL{}).
-		Where("short_code = ?", shortCode).
-		Update("click_count", gorm.Expr("click_count + 1")).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM url_tags WHERE url_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.URL{}, id).Error
+	})
 }
 
-// GetAnalytics lấy analytics cho URL
-func (r *urlRepositoryImpl) GetAnalytics(urlID uint) ([]entities.Analytics, error) {
-	var analytics []entities.Analytics
-	err := r.db.Where("url_id = ?", urlID).
-		Order("clicked_at DESC").
-		Find(&analytics).Error
-	return analytics, err
+// IncrementClickCount tăng số lần click và trả về giá trị mới (UPDATE ... RETURNING nên không bị race)
+func (r *urlRepositoryImpl) IncrementClickCount(shortCode string) (int64, error) {
+	r, span := r.startSpan("IncrementClickCount")
+	defer span.End()
+
+	var url entities.URL
+	result := r.db.Model(&url).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "click_count"}}}).
+		Where("short_code = ?", shortCode).
+		Update("click_count", gorm.Expr("click_count + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return url.ClickCount, nil
 }
 
 // AddAnalytics thêm analytics record
 func (r *urlRepositoryImpl) AddAnalytics(analytics *entities.Analytics) error {
+	r, span := r.startSpan("AddAnalytics")
+	defer span.End()
+
 	return r.db.Create(analytics).Error
 }
 
 // GetLastID lấy ID cuối cùng (cao nhất) trong table
 func (r *urlRepositoryImpl) GetLastID() (uint, error) {
+	r, span := r.startSpan("GetLastID")
+	defer span.End()
+
 	var url entities.URL
 	err := r.db.Order("id DESC").First(&url).Error
 	if err != nil {
@@ -84,3 +167,52 @@ func (r *urlRepositoryImpl) GetLastID() (uint, error) {
 	}
 	return url.ID, nil
 }
+
+// ListPendingMetadata lấy các URL đang chờ fetch metadata, cũ nhất trước
+func (r *urlRepositoryImpl) ListPendingMetadata(limit int) ([]entities.URL, error) {
+	r, span := r.startSpan("ListPendingMetadata")
+	defer span.End()
+
+	var urls []entities.URL
+	err := r.db.Where("meta_status = ?", entities.MetadataStatusPending).
+		Order("id").
+		Limit(limit).
+		Find(&urls).Error
+	return urls, err
+}
+
+// UpdateMetadata ghi metadata của URL; không đổi updated_at vì link không bị người dùng sửa
+func (r *urlRepositoryImpl) UpdateMetadata(id uint, metadata entities.URLMetadata) error {
+	r, span := r.startSpan("UpdateMetadata")
+	defer span.End()
+
+	return r.db.Model(&entities.URL{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"meta_status":      metadata.Status,
+		"meta_title":       metadata.Title,
+		"meta_description": metadata.Description,
+		"meta_image_url":   metadata.ImageURL,
+		"meta_site_name":   metadata.SiteName,
+		"meta_favicon_url": metadata.FaviconURL,
+		"meta_error":       metadata.Error,
+		"meta_fetched_at":  metadata.FetchedAt,
+	}).Error
+}
+
+// UpdatePreview ghi social preview của URL mà không ghi đè metadata do worker cập nhật
+func (r *urlRepositoryImpl) UpdatePreview(id uint, preview entities.URLPreview) error {
+	r, span := r.startSpan("UpdatePreview")
+	defer span.End()
+
+	result := r.db.Model(&entities.URL{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"preview_title":       preview.Title,
+		"preview_description": preview.Description,
+		"preview_image_url":   preview.ImageURL,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/url-shorted2/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+// likeEscaper escape ký tự đặc biệt của LIKE để tìm kiếm theo chuỗi con
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListURLs lấy một trang URL (kèm tag) theo bộ lọc, sắp xếp theo created_at hoặc click_count
+// với id làm tie-breaker để keyset cursor luôn ổn định
+func (r *urlRepositoryImpl) ListURLs(filter entities.URLFilter) ([]entities.URL, error) {
+	r, span := r.startSpan("ListURLs")
+	defer span.End()
+
+	query := r.db.Model(&entities.URL{})
+	if filter.Active != nil {
+		query = query.Where("is_active = ?", *filter.Active)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedFrom.UTC())
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedTo.UTC())
+	}
+	if filter.Host != "" {
+		query = query.Where(hostCondition(r.db, filter.Host))
+	}
+	if len(filter.Tags) > 0 {
+		// URL phải có đủ mọi tag trong filter
+		tagged := r.db.Session(&gorm.Session{NewDB: true}).
+			Table("url_tags").
+			Select("url_tags.url_id").
+			Joins("JOIN tags ON tags.id = url_tags.tag_id").
+			Where("tags.name IN ?", filter.Tags).
+			Group("url_tags.url_id").
+			Having("COUNT(DISTINCT tags.id) = ?", len(filter.Tags))
+		query = query.Where("id IN (?)", tagged)
+	}
+	if filter.FolderID != nil {
+		if *filter.FolderID == 0 {
+			query = query.Where("folder_id IS NULL")
+		} else {
+			query = query.Where("folder_id = ?", *filter.FolderID)
+		}
+	}
+	if filter.OwnerID != nil {
+		query = query.Where("owner_id = ?", *filter.OwnerID)
+	}
+	if filter.WorkspaceID != nil {
+		if *filter.WorkspaceID == 0 {
+			query = query.Where("workspace_id IS NULL")
+		} else {
+			query = query.Where("workspace_id = ?", *filter.WorkspaceID)
+		}
+	}
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		query = query.Where(`short_code LIKE ? ESCAPE '\' OR original_url LIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+
+	column := "created_at"
+	if filter.SortBy == entities.URLSortClicks {
+		column = "click_count"
+	}
+	direction, cmp := "DESC", "<"
+	if filter.Ascending {
+		direction, cmp = "ASC", ">"
+	}
+
+	if filter.After != nil {
+		var value interface{} = filter.After.CreatedAt.UTC()
+		if filter.SortBy == entities.URLSortClicks {
+			value = filter.After.ClickCount
+		}
+		query = query.Where(fmt.Sprintf("%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?)", column, cmp), value, value, filter.After.ID)
+	}
+
+	var urls []entities.URL
+	err := query.
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name") }).
+		Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).
+		Limit(filter.Limit).
+		Find(&urls).Error
+	return urls, err
+}
+
+// hostCondition khớp original_url có đúng host, có hoặc không có scheme, port, path, query
+func hostCondition(db *gorm.DB, host string) *gorm.DB {
+	host = likeEscaper.Replace(strings.ToLower(host))
+	condition := db.Session(&gorm.Session{NewDB: true})
+	for _, prefix := range []string{"%://", ""} {
+		for _, suffix := range []string{"", "/%", ":%", "?%", "#%"} {
+			condition = condition.Or(`original_url LIKE ? ESCAPE '\'`, prefix+host+suffix)
+		}
+	}
+	return condition
+}
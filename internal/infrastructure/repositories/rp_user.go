@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// userRepositoryImpl implement IUserRepository
+type userRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUserRepositoryImpl tạo instance mới của UserRepository
+func NewUserRepositoryImpl(db *gorm.DB) repositories.IUserRepository {
+	return &userRepositoryImpl{
+		db: db,
+	}
+}
+
+// WithContext trả về repository chạy query với ctx
+func (r *userRepositoryImpl) WithContext(ctx context.Context) repositories.IUserRepository {
+	return &userRepositoryImpl{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// startSpan mở span cho một method của repository
+func (r *userRepositoryImpl) startSpan(name string) (*userRepositoryImpl, trace.Span) {
+	ctx, span := utils.StartSpan(r.db.Statement.Context, "userRepository."+name)
+	return &userRepositoryImpl{db: r.db.WithContext(ctx)}, span
+}
+
+// CreateUser tạo user mới
+func (r *userRepositoryImpl) CreateUser(user *entities.User) error {
+	r, span := r.startSpan("CreateUser")
+	defer span.End()
+
+	return r.db.Create(user).Error
+}
+
+// GetUser lấy user theo ID
+func (r *userRepositoryImpl) GetUser(id uint) (*entities.User, error) {
+	r, span := r.startSpan("GetUser")
+	defer span.End()
+
+	var user entities.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByEmail lấy user theo email đã chuẩn hóa
+func (r *userRepositoryImpl) GetUserByEmail(email string) (*entities.User, error) {
+	r, span := r.startSpan("GetUserByEmail")
+	defer span.End()
+
+	var user entities.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListUsers lấy tất cả user theo thứ tự tạo
+func (r *userRepositoryImpl) ListUsers() ([]entities.User, error) {
+	r, span := r.startSpan("ListUsers")
+	defer span.End()
+
+	var users []entities.User
+	err := r.db.Order("id").Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+type webhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepositoryImpl tạo instance mới của WebhookRepository
+func NewWebhookRepositoryImpl(db *gorm.DB) repositories.IWebhookRepository {
+	return &webhookRepositoryImpl{
+		db: db,
+	}
+}
+
+// WithContext trả về repository chạy query với ctx
+func (r *webhookRepositoryImpl) WithContext(ctx context.Context) repositories.IWebhookRepository {
+	return &webhookRepositoryImpl{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// startSpan mở span cho một method của repository
+func (r *webhookRepositoryImpl) startSpan(name string) (*webhookRepositoryImpl, trace.Span) {
+	ctx, span := utils.StartSpan(r.db.Statement.Context, "webhookRepository."+name)
+	return &webhookRepositoryImpl{db: r.db.WithContext(ctx)}, span
+}
+
+// CreateWebhook tạo webhook mới
+func (r *webhookRepositoryImpl) CreateWebhook(webhook *entities.Webhook) error {
+	r, span := r.startSpan("CreateWebhook")
+	defer span.End()
+
+	return r.db.Create(webhook).Error
+}
+
+// GetWebhook lấy webhook theo ID
+func (r *webhookRepositoryImpl) GetWebhook(id uint) (*entities.Webhook, error) {
+	r, span := r.startSpan("GetWebhook")
+	defer span.End()
+
+	var webhook entities.Webhook
+	err := r.db.First(&webhook, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks lấy tất cả webhook
+func (r *webhookRepositoryImpl) ListWebhooks() ([]entities.Webhook, error) {
+	r, span := r.startSpan("ListWebhooks")
+	defer span.End()
+
+	var webhooks []entities.Webhook
+	err := r.db.Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// ListActiveWebhooks lấy các webhook đang hoạt động
+func (r *webhookRepositoryImpl) ListActiveWebhooks() ([]entities.Webhook, error) {
+	r, span := r.startSpan("ListActiveWebhooks")
+	defer span.End()
+
+	var webhooks []entities.Webhook
+	err := r.db.Where("is_active = ?", true).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// DeleteWebhook xóa webhook cùng các delivery của nó
+func (r *webhookRepositoryImpl) DeleteWebhook(id uint) error {
+	r, span := r.startSpan("DeleteWebhook")
+	defer span.End()
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&entities.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.Webhook{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// EnqueueDeliveries thêm các delivery vào hàng đợi
+func (r *webhookRepositoryImpl) EnqueueDeliveries(deliveries []entities.WebhookDelivery) error {
+	r, span := r.startSpan("EnqueueDeliveries")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Create(&deliveries).Error
+}
+
+// ClaimDueDeliveries lấy các delivery pending đã tới hạn và giữ chúng trong khoảng lease
+// bằng cách dời next_attempt_at. Cập nhật có điều kiện nên mỗi delivery chỉ được một instance nhận.
+func (r *webhookRepositoryImpl) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]entities.WebhookDelivery, error) {
+	r, span := r.startSpan("ClaimDueDeliveries")
+	defer span.End()
+
+	var due []entities.WebhookDelivery
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", entities.DeliveryStatusPending, now.UTC()).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+
+	leaseUntil := now.Add(lease).UTC()
+	claimed := make([]entities.WebhookDelivery, 0, len(due))
+	for _, delivery := range due {
+		result := r.db.Model(&entities.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, entities.DeliveryStatusPending, delivery.NextAttemptAt).
+			Update("next_attempt_at", leaseUntil)
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 1 {
+			delivery.NextAttemptAt = leaseUntil
+			claimed = append(claimed, delivery)
+		}
+	}
+	return claimed, nil
+}
+
+// UpdateDelivery lưu kết quả gửi của delivery
+func (r *webhookRepositoryImpl) UpdateDelivery(delivery *entities.WebhookDelivery) error {
+	r, span := r.startSpan("UpdateDelivery")
+	defer span.End()
+
+	return r.db.Save(delivery).Error
+}
+
+// GetDelivery lấy delivery của một webhook theo ID
+func (r *webhookRepositoryImpl) GetDelivery(webhookID, deliveryID uint) (*entities.WebhookDelivery, error) {
+	r, span := r.startSpan("GetDelivery")
+	defer span.End()
+
+	var delivery entities.WebhookDelivery
+	err := r.db.Where("webhook_id = ?", webhookID).First(&delivery, deliveryID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries lấy log delivery mới nhất của webhook, lọc theo status nếu có
+func (r *webhookRepositoryImpl) ListDeliveries(webhookID uint, status string, limit int) ([]entities.WebhookDelivery, error) {
+	r, span := r.startSpan("ListDeliveries")
+	defer span.End()
+
+	query := r.db.Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []entities.WebhookDelivery
+	err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
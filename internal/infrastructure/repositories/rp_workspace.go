@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// workspaceRepositoryImpl implement IWorkspaceRepository
+type workspaceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceRepositoryImpl tạo instance mới của WorkspaceRepository
+func NewWorkspaceRepositoryImpl(db *gorm.DB) repositories.IWorkspaceRepository {
+	return &workspaceRepositoryImpl{
+		db: db,
+	}
+}
+
+// WithContext trả về repository chạy query với ctx
+func (r *workspaceRepositoryImpl) WithContext(ctx context.Context) repositories.IWorkspaceRepository {
+	return &workspaceRepositoryImpl{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// startSpan mở span cho một method của repository
+func (r *workspaceRepositoryImpl) startSpan(name string) (*workspaceRepositoryImpl, trace.Span) {
+	ctx, span := utils.StartSpan(r.db.Statement.Context, "workspaceRepository."+name)
+	return &workspaceRepositoryImpl{db: r.db.WithContext(ctx)}, span
+}
+
+// CreateWorkspace tạo workspace và member owner đầu tiên
+func (r *workspaceRepositoryImpl) CreateWorkspace(workspace *entities.Workspace, owner *entities.WorkspaceMember) error {
+	r, span := r.startSpan("CreateWorkspace")
+	defer span.End()
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		owner.WorkspaceID = workspace.ID
+		return tx.Omit("User").Create(owner).Error
+	})
+}
+
+// GetWorkspace lấy workspace theo ID
+func (r *workspaceRepositoryImpl) GetWorkspace(id uint) (*entities.Workspace, error) {
+	r, span := r.startSpan("GetWorkspace")
+	defer span.End()
+
+	var workspace entities.Workspace
+	if err := r.db.First(&workspace, id).Error; err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// ListWorkspaces lấy workspace theo thứ tự tạo, chỉ lấy workspace userID là member nếu khác nil
+func (r *workspaceRepositoryImpl) ListWorkspaces(userID *uint) ([]entities.Workspace, error) {
+	r, span := r.startSpan("ListWorkspaces")
+	defer span.End()
+
+	query := r.db.Order("id")
+	if userID != nil {
+		members := r.db.Session(&gorm.Session{NewDB: true}).Model(&entities.WorkspaceMember{}).
+			Select("workspace_id").
+			Where("user_id = ?", *userID)
+		query = query.Where("id IN (?)", members)
+	}
+	var workspaces []entities.Workspace
+	err := query.Find(&workspaces).Error
+	return workspaces, err
+}
+
+// UpdateWorkspace lưu tên và settings của workspace
+func (r *workspaceRepositoryImpl) UpdateWorkspace(workspace *entities.Workspace) error {
+	r, span := r.startSpan("UpdateWorkspace")
+	defer span.End()
+
+	return r.db.Save(workspace).Error
+}
+
+// GetMember lấy member của workspace theo user
+func (r *workspaceRepositoryImpl) GetMember(workspaceID, userID uint) (*entities.WorkspaceMember, error) {
+	r, span := r.startSpan("GetMember")
+	defer span.End()
+
+	var member entities.WorkspaceMember
+	err := r.db.Preload("User").
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// ListMembers lấy member của workspace theo thứ tự tham gia
+func (r *workspaceRepositoryImpl) ListMembers(workspaceID uint) ([]entities.WorkspaceMember, error) {
+	r, span := r.startSpan("ListMembers")
+	defer span.End()
+
+	var members []entities.WorkspaceMember
+	err := r.db.Preload("User").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at, user_id").
+		Find(&members).Error
+	return members, err
+}
+
+// CreateMember thêm member vào workspace
+func (r *workspaceRepositoryImpl) CreateMember(member *entities.WorkspaceMember) error {
+	r, span := r.startSpan("CreateMember")
+	defer span.End()
+
+	return r.db.Omit("User").Create(member).Error
+}
+
+// UpdateMember lưu role của member. Không hạ role owner cuối cùng: điều kiện đếm owner nằm trong
+// cùng câu UPDATE nên hai request hạ hai owner cùng lúc không làm workspace mất owner.
+// Không có dòng nào được cập nhật trả về gorm.ErrRecordNotFound.
+func (r *workspaceRepositoryImpl) UpdateMember(member *entities.WorkspaceMember) error {
+	r, span := r.startSpan("UpdateMember")
+	defer span.End()
+
+	result := r.db.Model(&entities.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", member.WorkspaceID, member.UserID).
+		Where(keepsAnotherOwner(member.WorkspaceID)).
+		Update("role", member.Role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteMember xóa member khỏi workspace. Như UpdateMember, không xóa owner cuối cùng và
+// trả về gorm.ErrRecordNotFound khi không có dòng nào bị xóa.
+func (r *workspaceRepositoryImpl) DeleteMember(workspaceID, userID uint) error {
+	r, span := r.startSpan("DeleteMember")
+	defer span.End()
+
+	result := r.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Where(keepsAnotherOwner(workspaceID)).
+		Delete(&entities.WorkspaceMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// keepsAnotherOwner là điều kiện WHERE: dòng không phải owner, hoặc workspace còn owner khác
+func keepsAnotherOwner(workspaceID uint) clause.Expr {
+	return gorm.Expr("(role <> ? OR (SELECT COUNT(*) FROM workspace_members AS owners WHERE owners.workspace_id = ? AND owners.role = ?) > 1)",
+		entities.WorkspaceRoleOwner, workspaceID, entities.WorkspaceRoleOwner)
+}
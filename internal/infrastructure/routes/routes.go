@@ -1,26 +1,98 @@
 package routes
 
 import (
+	"github.com/url-shorted2/docs"
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
 	"github.com/url-shorted2/internal/infrastructure/handlers"
+	"github.com/url-shorted2/internal/infrastructure/middleware"
+	"github.com/url-shorted2/internal/usecases"
+	"github.com/url-shorted2/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 // SetupRoutes thiết lập tất cả routes cho ứng dụng
-func SetupRoutes(router *gin.Engine, urlHandler *handlers.URLHandler) {
-	// API v1 group
-	v1 := router.Group("/api/v1")
+func SetupRoutes(router *gin.Engine, urlHandler *handlers.URLHandler, webhookHandler *handlers.WebhookHandler, tagHandler *handlers.TagHandler, folderHandler *handlers.FolderHandler, apiKeyHandler *handlers.APIKeyHandler, userHandler *handlers.UserHandler, workspaceHandler *handlers.WorkspaceHandler, apiKeyUsecase usecases.IAPIKeyUsecase, cfg *config.Config) {
+	// Scope cần cho từng nhóm thao tác
+	linksRead := middleware.RequireScope(entities.ScopeLinksRead)
+	linksWrite := middleware.RequireScope(entities.ScopeLinksWrite)
+	statsRead := middleware.RequireScope(entities.ScopeStatsRead)
+
+	// API v1 group, mọi endpoint cần API key
+	v1 := router.Group("/api/v1", middleware.APIKeyAuthMiddleware(apiKeyUsecase))
 	{
 		// URL routes
-		v1.POST("/urls", urlHandler.CreateShortURL)
-		v1.GET("/urls/:shortCode", urlHandler.GetURLInfo)
-		v1.GET("/urls/:shortCode/stats", urlHandler.GetURLStats)
-		v1.DELETE("/urls/:shortCode", urlHandler.DeleteURL)
+		v1.POST("/urls", linksWrite, urlHandler.CreateShortURL)
+		v1.POST("/urls/batch", linksWrite, urlHandler.CreateShortURLs)
+		v1.GET("/urls", linksRead, urlHandler.ListURLs)
+		v1.GET("/urls/:shortCode", linksRead, urlHandler.GetURLInfo)
+		v1.GET("/urls/:shortCode/stats", statsRead, urlHandler.GetURLStats)
+		v1.GET("/urls/:shortCode/stats/timeseries", statsRead, urlHandler.GetClickTimeSeries)
+		v1.GET("/urls/:shortCode/stats/referrers", statsRead, urlHandler.GetReferrerStats)
+		v1.GET("/urls/:shortCode/clicks", statsRead, urlHandler.ListClicks)
+		v1.GET("/urls/:shortCode/clicks/export", statsRead, urlHandler.ExportClicks)
+		v1.GET("/urls/:shortCode/events", statsRead, urlHandler.StreamClicks)
+		v1.GET("/urls/:shortCode/qr", linksRead, urlHandler.GetQRCode)
+		v1.PUT("/urls/:shortCode/preview", linksWrite, urlHandler.SetPreview)
+		v1.DELETE("/urls/:shortCode", linksWrite, urlHandler.DeleteURL)
+		v1.PUT("/urls/:shortCode/tags", linksWrite, tagHandler.SetURLTags)
+		v1.PUT("/urls/:shortCode/folder", linksWrite, folderHandler.SetURLFolder)
+
+		// Tag routes
+		v1.POST("/tags", linksWrite, tagHandler.CreateTag)
+		v1.GET("/tags", linksRead, tagHandler.ListTags)
+		v1.PUT("/tags/:tag", linksWrite, tagHandler.RenameTag)
+		v1.DELETE("/tags/:tag", linksWrite, tagHandler.DeleteTag)
+		v1.GET("/tags/:tag/stats", statsRead, tagHandler.GetTagStats)
+
+		// Folder routes
+		v1.POST("/folders", linksWrite, folderHandler.CreateFolder)
+		v1.GET("/folders", linksRead, folderHandler.ListFolders)
+		v1.PUT("/folders/:id", linksWrite, folderHandler.UpdateFolder)
+		v1.DELETE("/folders/:id", linksWrite, folderHandler.DeleteFolder)
+
+		// Workspace routes, role trong workspace được kiểm tra ở usecase
+		v1.POST("/workspaces", linksWrite, workspaceHandler.CreateWorkspace)
+		v1.GET("/workspaces", linksRead, workspaceHandler.ListWorkspaces)
+		v1.GET("/workspaces/:id", linksRead, workspaceHandler.GetWorkspace)
+		v1.PUT("/workspaces/:id/settings", linksWrite, workspaceHandler.UpdateSettings)
+		v1.GET("/workspaces/:id/members", linksRead, workspaceHandler.ListMembers)
+		v1.POST("/workspaces/:id/members", linksWrite, workspaceHandler.InviteMember)
+		v1.PUT("/workspaces/:id/members/:userId", linksWrite, workspaceHandler.UpdateMember)
+		v1.DELETE("/workspaces/:id/members/:userId", linksWrite, workspaceHandler.RemoveMember)
+
+		// Admin routes
+		admin := v1.Group("/admin", middleware.RequireScope(entities.ScopeAdmin))
+		admin.POST("/analytics/erase", urlHandler.EraseAnalytics)
+		admin.POST("/urls/import", urlHandler.ImportURLs)
+		admin.POST("/webhooks", webhookHandler.CreateWebhook)
+		admin.GET("/webhooks", webhookHandler.ListWebhooks)
+		admin.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+		admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+		admin.POST("/webhooks/:id/deliveries/:deliveryId/retry", webhookHandler.RetryDelivery)
+		admin.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+		admin.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+		admin.POST("/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+		admin.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+		admin.POST("/users", userHandler.CreateUser)
+		admin.GET("/users", userHandler.ListUsers)
 	}
 
 	// Redirect route (short code without prefix)
 	router.GET("/:shortCode", urlHandler.Redirect)
 
+	// OpenAPI document và trang tài liệu
+	router.GET("/api/openapi.json", func(c *gin.Context) {
+		c.Data(200, "application/json; charset=utf-8", docs.OpenAPI)
+	})
+	router.GET("/api/docs", func(c *gin.Context) {
+		c.Data(200, "text/html; charset=utf-8", docs.UI)
+	})
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(utils.MetricsHandler()))
+
 	// Health check route
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
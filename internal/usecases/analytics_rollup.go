@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+)
+
+// defaultRollupInterval là chu kỳ rollup khi config không hợp lệ
+const defaultRollupInterval = time.Hour
+
+// rollupChunk là độ dài mỗi lần gom để giới hạn kích thước transaction khi backfill
+var rollupChunk = map[string]time.Duration{
+	entities.IntervalHour: 24 * time.Hour,
+	entities.IntervalDay:  31 * 24 * time.Hour,
+}
+
+// IAnalyticsRollupUsecase gom click thô vào bảng rollup và xóa click thô quá hạn
+type IAnalyticsRollupUsecase interface {
+	RunRollup(now time.Time) (*entities.RollupResult, error)
+	Start(ctx context.Context)
+}
+
+type analyticsRollupUsecase struct {
+	urlRepo repositories.IURLRepository
+	config  *config.Config
+}
+
+// NewAnalyticsRollupUsecase tạo rollup job. Mỗi lần chạy đều idempotent nên
+// nhiều instance chạy cùng lúc không làm sai số liệu.
+func NewAnalyticsRollupUsecase(urlRepo repositories.IURLRepository, cfg *config.Config) IAnalyticsRollupUsecase {
+	return &analyticsRollupUsecase{
+		urlRepo: urlRepo,
+		config:  cfg,
+	}
+}
+
+// Start chạy rollup ngay rồi lặp lại theo RollupInterval cho tới khi ctx kết thúc
+func (u *analyticsRollupUsecase) Start(ctx context.Context) {
+	ticker := time.NewTicker(u.rollupInterval())
+	defer ticker.Stop()
+
+	for {
+		result, err := u.RunRollup(time.Now())
+		if err != nil {
+			fmt.Printf("Analytics rollup failed: %v\n", err)
+		} else {
+			fmt.Printf("Analytics rollup done: hourly=%d daily=%d pruned=%d\n", result.HourlyRows, result.DailyRows, result.PrunedRows)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// rollupInterval trả về chu kỳ rollup theo config, giá trị <= 0 dùng mặc định
+func (u *analyticsRollupUsecase) rollupInterval() time.Duration {
+	if u.config == nil || u.config.Analytics.RollupInterval <= 0 {
+		return defaultRollupInterval
+	}
+	return u.config.Analytics.RollupInterval
+}
+
+// RunRollup gom các bucket giờ/ngày đã kết thúc trước now, sau đó xóa click thô
+// cũ hơn retention nếu chúng đã nằm trong rollup
+func (u *analyticsRollupUsecase) RunRollup(now time.Time) (*entities.RollupResult, error) {
+	result := &entities.RollupResult{}
+
+	hourly, err := u.rollup(entities.IntervalHour, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll up hourly analytics: %w", err)
+	}
+	result.HourlyRows = hourly
+
+	daily, err := u.rollup(entities.IntervalDay, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll up daily analytics: %w", err)
+	}
+	result.DailyRows = daily
+
+	cutoff := rawDataCutoff(u.config, now)
+	if cutoff.IsZero() {
+		return result, nil
+	}
+
+	// Chỉ xóa click thô đã nằm trong cả rollup giờ và ngày
+	for _, granularity := range []string{entities.IntervalHour, entities.IntervalDay} {
+		watermark, err := u.urlRepo.GetRollupWatermark(granularity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s rollup watermark: %w", granularity, err)
+		}
+		if watermark == nil {
+			return result, nil
+		}
+		if watermark.Before(cutoff) {
+			cutoff = *watermark
+		}
+	}
+
+	pruned, err := u.urlRepo.PruneAnalytics(cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune analytics: %w", err)
+	}
+	result.PrunedRows = pruned
+	return result, nil
+}
+
+// rollup gom từ watermark (hoặc click thô cũ nhất) tới đầu bucket hiện tại
+func (u *analyticsRollupUsecase) rollup(granularity string, now time.Time) (int64, error) {
+	end := truncateUTC(now, granularity)
+
+	start, err := u.urlRepo.GetRollupWatermark(granularity)
+	if err != nil {
+		return 0, err
+	}
+	if start == nil {
+		if start, err = u.urlRepo.GetOldestClickedAt(); err != nil || start == nil {
+			return 0, err
+		}
+	}
+
+	var written int64
+	for from := truncateUTC(*start, granularity); from.Before(end); {
+		to := from.Add(rollupChunk[granularity])
+		if to.After(end) {
+			to = end
+		}
+		n, err := u.urlRepo.RollupAnalytics(granularity, from, to)
+		if err != nil {
+			return written, err
+		}
+		written += n
+		from = to
+	}
+	return written, nil
+}
+
+// rawDataCutoff trả về mốc (đầu ngày UTC) mà click thô trước đó có thể đã bị xóa,
+// time zero nếu retention bị tắt
+func rawDataCutoff(cfg *config.Config, now time.Time) time.Time {
+	if cfg == nil || cfg.Analytics.RawRetention <= 0 {
+		return time.Time{}
+	}
+	return truncateUTC(now.Add(-cfg.Analytics.RawRetention), entities.IntervalDay)
+}
+
+// truncateUTC làm tròn xuống đầu giờ hoặc đầu ngày theo UTC
+func truncateUTC(t time.Time, granularity string) time.Time {
+	if granularity == entities.IntervalHour {
+		return t.UTC().Truncate(time.Hour)
+	}
+	return t.UTC().Truncate(24 * time.Hour)
+}
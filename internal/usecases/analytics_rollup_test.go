@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAnalyticsRollupUsecase_RunRollup(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		setup     func(*MockURLRepository)
+		want      *entities.RollupResult
+		wantErr   bool
+	}{
+		{
+			name: "Backfill từ click cũ nhất, không xóa khi tắt retention",
+			setup: func(mockRepo *MockURLRepository) {
+				oldest := time.Date(2024, 3, 9, 22, 10, 0, 0, time.UTC)
+				mockRepo.On("GetRollupWatermark", entities.IntervalHour).Return(nil, nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalDay).Return(nil, nil)
+				mockRepo.On("GetOldestClickedAt").Return(&oldest, nil)
+				mockRepo.On("RollupAnalytics", entities.IntervalHour, *at(9, 22), *at(10, 12)).Return(int64(5), nil)
+				mockRepo.On("RollupAnalytics", entities.IntervalDay, *at(9, 0), *at(10, 0)).Return(int64(2), nil)
+			},
+			want: &entities.RollupResult{HourlyRows: 5, DailyRows: 2},
+		},
+		{
+			name:      "Tiếp tục từ watermark và xóa click quá retention",
+			retention: 7 * 24 * time.Hour,
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetRollupWatermark", entities.IntervalHour).Return(at(10, 10), nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalDay).Return(at(9, 0), nil)
+				mockRepo.On("RollupAnalytics", entities.IntervalHour, *at(10, 10), *at(10, 12)).Return(int64(3), nil)
+				mockRepo.On("RollupAnalytics", entities.IntervalDay, *at(9, 0), *at(10, 0)).Return(int64(1), nil)
+				mockRepo.On("PruneAnalytics", *at(3, 0)).Return(int64(42), nil)
+			},
+			want: &entities.RollupResult{HourlyRows: 3, DailyRows: 1, PrunedRows: 42},
+		},
+		{
+			name:      "Không xóa click thô chưa được rollup",
+			retention: 7 * 24 * time.Hour,
+			setup: func(mockRepo *MockURLRepository) {
+				behind := time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC)
+				mockRepo.On("GetRollupWatermark", entities.IntervalHour).Return(at(10, 10), nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalDay).Return(&behind, nil)
+				mockRepo.On("RollupAnalytics", entities.IntervalHour, *at(10, 10), *at(10, 12)).Return(int64(3), nil)
+				mockRepo.On("RollupAnalytics", entities.IntervalDay, behind, *at(10, 0)).Return(int64(19), nil)
+				mockRepo.On("PruneAnalytics", behind).Return(int64(7), nil)
+			},
+			want: &entities.RollupResult{HourlyRows: 3, DailyRows: 19, PrunedRows: 7},
+		},
+		{
+			name:      "Rollup lỗi thì không xóa click thô",
+			retention: 7 * 24 * time.Hour,
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetRollupWatermark", entities.IntervalHour).Return(at(10, 10), nil)
+				mockRepo.On("RollupAnalytics", entities.IntervalHour, *at(10, 10), *at(10, 12)).Return(int64(0), errors.New("disk full"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockURLRepository)
+			tt.setup(mockRepo)
+
+			usecase := NewAnalyticsRollupUsecase(mockRepo, &config.Config{
+				Analytics: config.AnalyticsConfig{RawRetention: tt.retention},
+			})
+
+			got, err := usecase.RunRollup(now)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "PruneAnalytics", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRawDataCutoff(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+
+	assert.True(t, rawDataCutoff(nil, now).IsZero())
+	assert.True(t, rawDataCutoff(&config.Config{}, now).IsZero())
+	assert.Equal(t,
+		time.Date(2024, 2, 9, 0, 0, 0, 0, time.UTC),
+		rawDataCutoff(&config.Config{Analytics: config.AnalyticsConfig{RawRetention: 30 * 24 * time.Hour}}, now),
+	)
+}
@@ -0,0 +1,312 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// Lỗi của API key
+var (
+	// ErrUnauthorized trả về khi request không có hoặc có API key không hợp lệ
+	ErrUnauthorized = newDomainError(KindUnauthorized, entities.ProblemCodeUnauthorized, "missing or invalid API key")
+	// ErrInsufficientScope trả về khi API key không có scope cần cho thao tác
+	ErrInsufficientScope = newDomainError(KindForbidden, entities.ProblemCodeInsufficientScope, "API key does not have the required scope")
+	// ErrInvalidAPIKey trả về khi tên hoặc danh sách scope của key không hợp lệ
+	ErrInvalidAPIKey = newDomainError(KindValidation, "invalid_api_key", "invalid API key request")
+	// ErrAPIKeyNotFound trả về khi API key không tồn tại
+	ErrAPIKeyNotFound = newDomainError(KindNotFound, "api_key_not_found", "API key not found")
+	// ErrAPIKeyRevoked trả về khi rotate hoặc revoke một key đã bị revoke
+	ErrAPIKeyRevoked = newDomainError(KindPrecondition, "api_key_revoked", "API key has been revoked")
+)
+
+const (
+	// apiKeyPrefix giúp nhận ra key trong log hoặc công cụ quét secret
+	apiKeyPrefix = "usk_"
+	// apiKeyDisplayLength là số ký tự đầu của key được lưu để hiển thị
+	apiKeyDisplayLength = 12
+	// apiKeyTouchInterval giới hạn tần suất ghi last_used_at để không ghi DB mỗi request
+	apiKeyTouchInterval = time.Minute
+	// maxAPIKeyNameLength giới hạn độ dài tên key
+	maxAPIKeyNameLength = 100
+)
+
+// adminTokenKey là principal của ADMIN_TOKEN, không lưu trong DB
+var adminTokenKey = &entities.APIKey{Name: "admin-token", Prefix: "admin-token", Scopes: entities.ScopeAdmin}
+
+// apiKeyScopes là các scope hợp lệ
+var apiKeyScopes = []string{entities.ScopeLinksRead, entities.ScopeLinksWrite, entities.ScopeStatsRead, entities.ScopeAdmin}
+
+type IAPIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, req entities.CreateAPIKeyRequest) (*entities.APIKeyResponse, error)
+	ListAPIKeys(ctx context.Context) ([]entities.APIKeyResponse, error)
+	RotateAPIKey(ctx context.Context, id uint) (*entities.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, id uint) (*entities.APIKeyResponse, error)
+	Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, error)
+}
+
+type apiKeyUsecase struct {
+	apiKeyRepo repositories.IAPIKeyRepository
+	userRepo   repositories.IUserRepository
+	adminToken string
+}
+
+// NewAPIKeyUsecase tạo API key usecase. adminToken (ADMIN_TOKEN) khác rỗng được chấp nhận
+// như một key có scope admin, dùng để tạo key đầu tiên.
+func NewAPIKeyUsecase(apiKeyRepo repositories.IAPIKeyRepository, userRepo repositories.IUserRepository, adminToken string) IAPIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		adminToken: adminToken,
+	}
+}
+
+// CreateAPIKey tạo key mới; key gốc chỉ trả về một lần trong response
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, req entities.CreateAPIKeyRequest) (*entities.APIKeyResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "apiKeyUsecase.CreateAPIKey")
+	defer span.End()
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxAPIKeyNameLength {
+		return nil, ErrInvalidAPIKey.WithMessage("name must be 1-%d characters", maxAPIKeyNameLength)
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	// Key không có scope admin chỉ thao tác được trên link của user nên phải gắn với một user
+	if req.UserID == nil && !slices.Contains(scopes, entities.ScopeAdmin) {
+		return nil, ErrInvalidAPIKey.WithMessage("user_id is required for keys without admin scope")
+	}
+	if req.UserID != nil {
+		if _, err := u.userRepo.WithContext(ctx).GetUser(*req.UserID); err != nil {
+			return nil, notFoundError(err, ErrUserNotFound, "failed to get user")
+		}
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key := &entities.APIKey{
+		Name:    name,
+		UserID:  req.UserID,
+		Prefix:  rawKey[:apiKeyDisplayLength],
+		KeyHash: hashAPIKey(rawKey),
+		Scopes:  strings.Join(scopes, ","),
+	}
+	if err := u.apiKeyRepo.WithContext(ctx).CreateAPIKey(key); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	response := apiKeyResponse(key)
+	response.Key = rawKey
+	return &response, nil
+}
+
+// ListAPIKeys lấy tất cả key, không kèm key gốc
+func (u *apiKeyUsecase) ListAPIKeys(ctx context.Context) ([]entities.APIKeyResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "apiKeyUsecase.ListAPIKeys")
+	defer span.End()
+
+	keys, err := u.apiKeyRepo.WithContext(ctx).ListAPIKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	responses := make([]entities.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		responses = append(responses, apiKeyResponse(&keys[i]))
+	}
+	return responses, nil
+}
+
+// RotateAPIKey thay key gốc, giữ nguyên tên và scope; key cũ hết hiệu lực ngay
+func (u *apiKeyUsecase) RotateAPIKey(ctx context.Context, id uint) (*entities.APIKeyResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "apiKeyUsecase.RotateAPIKey")
+	defer span.End()
+
+	key, err := u.findActiveAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key.Prefix = rawKey[:apiKeyDisplayLength]
+	key.KeyHash = hashAPIKey(rawKey)
+	key.LastUsedAt = nil
+	if err := u.apiKeyRepo.WithContext(ctx).UpdateAPIKey(key); err != nil {
+		return nil, fmt.Errorf("failed to rotate API key: %w", err)
+	}
+
+	response := apiKeyResponse(key)
+	response.Key = rawKey
+	return &response, nil
+}
+
+// RevokeAPIKey vô hiệu hóa key; key vẫn nằm trong danh sách để tra cứu
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, id uint) (*entities.APIKeyResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "apiKeyUsecase.RevokeAPIKey")
+	defer span.End()
+
+	key, err := u.findActiveAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	if err := u.apiKeyRepo.WithContext(ctx).UpdateAPIKey(key); err != nil {
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	response := apiKeyResponse(key)
+	return &response, nil
+}
+
+// Authenticate tìm key theo key gốc; key không tồn tại hoặc đã revoke trả về ErrUnauthorized
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, error) {
+	ctx, span := utils.StartSpan(ctx, "apiKeyUsecase.Authenticate")
+	defer span.End()
+
+	if u.adminToken != "" && subtle.ConstantTimeCompare([]byte(rawKey), []byte(u.adminToken)) == 1 {
+		return adminTokenKey, nil
+	}
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrUnauthorized
+	}
+	key, err := u.apiKeyRepo.WithContext(ctx).GetAPIKeyByHash(hashAPIKey(rawKey))
+	if err != nil {
+		return nil, notFoundError(err, ErrUnauthorized, "failed to get API key")
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrUnauthorized
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		// Lỗi ghi last_used_at không chặn request
+		if err := u.apiKeyRepo.WithContext(ctx).TouchAPIKey(key.ID, now); err != nil {
+			fmt.Printf("Failed to update API key last used: %v\n", err)
+		}
+		key.LastUsedAt = &now
+	}
+	return key, nil
+}
+
+// findActiveAPIKey lấy key theo ID, key đã revoke trả về ErrAPIKeyRevoked
+func (u *apiKeyUsecase) findActiveAPIKey(ctx context.Context, id uint) (*entities.APIKey, error) {
+	key, err := u.apiKeyRepo.WithContext(ctx).GetAPIKey(id)
+	if err != nil {
+		return nil, notFoundError(err, ErrAPIKeyNotFound, "failed to get API key")
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	return key, nil
+}
+
+// apiKeyContextKey là key lưu API key của request trong context
+type apiKeyContextKey struct{}
+
+// WithAPIKey gắn API key đã xác thực vào ctx để usecase biết ai đang gọi
+func WithAPIKey(ctx context.Context, key *entities.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// systemCallerContextKey đánh dấu ctx của tiến trình tin cậy không đi qua xác thực API key
+type systemCallerContextKey struct{}
+
+// WithSystemCaller đánh dấu ctx là của job nền hoặc CLI chạy trên server. Người gọi này có quyền
+// như scope admin; ctx không có API key và không được đánh dấu bị coi là không có quyền.
+func WithSystemCaller(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemCallerContextKey{}, true)
+}
+
+// isSystemCaller cho biết ctx đã được đánh dấu bởi WithSystemCaller
+func isSystemCaller(ctx context.Context) bool {
+	system, _ := ctx.Value(systemCallerContextKey{}).(bool)
+	return system
+}
+
+// APIKeyFromContext lấy API key đã gắn bởi WithAPIKey, nil nếu request chưa xác thực
+func APIKeyFromContext(ctx context.Context) *entities.APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*entities.APIKey)
+	return key
+}
+
+// RequireScope kiểm tra API key trong ctx có scope; thiếu key trả về ErrUnauthorized
+func RequireScope(ctx context.Context, scope string) error {
+	key := APIKeyFromContext(ctx)
+	if key == nil {
+		return ErrUnauthorized
+	}
+	if !key.HasScope(scope) {
+		return ErrInsufficientScope.WithMessage("API key requires scope %s", scope)
+	}
+	return nil
+}
+
+// normalizeScopes kiểm tra và bỏ trùng danh sách scope, giữ thứ tự của apiKeyScopes
+func normalizeScopes(scopes []string) ([]string, error) {
+	requested := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !slices.Contains(apiKeyScopes, scope) {
+			return nil, ErrInvalidAPIKey.WithMessage("unknown scope %q, valid scopes: %s", scope, strings.Join(apiKeyScopes, ", "))
+		}
+		requested[scope] = true
+	}
+
+	normalized := make([]string, 0, len(requested))
+	for _, scope := range apiKeyScopes {
+		if requested[scope] {
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, ErrInvalidAPIKey.WithMessage("at least one scope is required")
+	}
+	return normalized, nil
+}
+
+// generateAPIKey tạo key ngẫu nhiên 32 byte dạng hex với prefix usk_
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return apiKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashAPIKey trả về SHA-256 của key gốc; key có 256 bit ngẫu nhiên nên không cần salt
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func apiKeyResponse(key *entities.APIKey) entities.APIKeyResponse {
+	return entities.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		UserID:     key.UserID,
+		Prefix:     key.Prefix,
+		Scopes:     key.ScopeList(),
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrorKind phân loại lỗi domain; lớp giao tiếp (HTTP, gRPC) map kind sang status code
+type ErrorKind int
+
+// Các loại lỗi domain. Lỗi không phải DomainError được coi là KindInternal.
+// KindConflict là tài nguyên đã tồn tại; KindPrecondition là trạng thái hiện tại không cho phép thao tác.
+const (
+	KindInternal ErrorKind = iota
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindPrecondition
+	KindExpired
+	KindUnavailable
+	KindUnauthorized
+	KindForbidden
+)
+
+// CodeInternal là code của mọi lỗi không phân loại được (DB, Redis, bug...)
+const CodeInternal = "internal_error"
+
+// DomainError là lỗi domain có Code ổn định để client xử lý theo máy.
+// Hai DomainError được coi là cùng lỗi (errors.Is) khi có cùng Code.
+type DomainError struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+// Is cho phép so sánh với sentinel theo Code, kể cả khi message khác nhau
+func (e *DomainError) Is(target error) bool {
+	t, ok := target.(*DomainError)
+	return ok && t.Code == e.Code
+}
+
+// WithMessage trả về bản sao của lỗi với message cụ thể hơn
+func (e *DomainError) WithMessage(format string, args ...interface{}) *DomainError {
+	return &DomainError{Kind: e.Kind, Code: e.Code, Message: fmt.Sprintf(format, args...)}
+}
+
+func newDomainError(kind ErrorKind, code, message string) *DomainError {
+	return &DomainError{Kind: kind, Code: code, Message: message}
+}
+
+// AsDomainError lấy DomainError trong chuỗi wrap của err.
+// Lỗi không phân loại trả về DomainError KindInternal với message chung để không lộ chi tiết hạ tầng.
+func AsDomainError(err error) *DomainError {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return newDomainError(KindInternal, CodeInternal, "internal server error")
+}
+
+// Lỗi domain dùng chung giữa các usecase
+var (
+	// ErrInvalidRequest trả về khi body, query hoặc tham số của request không parse được
+	ErrInvalidRequest = newDomainError(KindValidation, "invalid_request", "invalid request")
+	// ErrURLNotFound trả về khi short code không tồn tại
+	ErrURLNotFound = newDomainError(KindNotFound, "url_not_found", "URL not found")
+	// ErrURLInactive trả về khi short code tồn tại nhưng đã bị vô hiệu hóa
+	ErrURLInactive = newDomainError(KindExpired, "url_inactive", "URL is inactive")
+	// ErrInvalidURL trả về khi URL đích không hợp lệ
+	ErrInvalidURL = newDomainError(KindValidation, "invalid_url", "invalid URL")
+	// ErrLockUnavailable trả về khi không lấy được lock cấp short code (Redis lỗi hoặc quá tải)
+	ErrLockUnavailable = newDomainError(KindUnavailable, "lock_unavailable", "short code allocation is temporarily unavailable")
+)
+
+// findURL lấy URL theo short code; record không tồn tại trả về ErrURLNotFound,
+// lỗi khác (DB) được giữ nguyên để map thành lỗi internal
+func (u *urlUsecase) findURL(ctx context.Context, shortCode string) (*entities.URL, error) {
+	return getURLByShortCode(u.repo(ctx), shortCode)
+}
+
+// getURLByShortCode giống findURL, dùng cho usecase khác có IURLRepository
+func getURLByShortCode(urlRepo repositories.IURLRepository, shortCode string) (*entities.URL, error) {
+	urlEntity, err := urlRepo.GetByShortCode(shortCode)
+	if err != nil {
+		return nil, notFoundError(err, ErrURLNotFound, "failed to get URL")
+	}
+	return urlEntity, nil
+}
+
+// notFoundError đổi gorm.ErrRecordNotFound thành lỗi domain notFound, lỗi khác được wrap với action
+func notFoundError(err error, notFound *DomainError, action string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// lockError ghi log lỗi lấy lock và trả về ErrLockUnavailable.
+// Lỗi Redis/dlock không nằm trong message để không lộ chi tiết hạ tầng ra client.
+func lockError(err error) error {
+	fmt.Printf("Failed to acquire short code lock: %v\n", err)
+	return ErrLockUnavailable
+}
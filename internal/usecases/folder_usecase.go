@@ -0,0 +1,311 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// Lỗi của folder
+var (
+	// ErrInvalidFolder trả về khi tên folder, folder cha hoặc folder đích không hợp lệ
+	ErrInvalidFolder = newDomainError(KindValidation, "invalid_folder", "invalid folder")
+	// ErrFolderNotFound trả về khi folder trong path không tồn tại
+	ErrFolderNotFound = newDomainError(KindNotFound, "folder_not_found", "folder not found")
+	// ErrFolderExists trả về khi folder cha đã có folder con cùng tên
+	ErrFolderExists = newDomainError(KindConflict, "folder_exists", "folder already exists")
+	// ErrFolderNotEmpty trả về khi xóa folder còn folder con hoặc URL
+	ErrFolderNotEmpty = newDomainError(KindPrecondition, "folder_not_empty", "folder is not empty")
+)
+
+// maxFolderNameLength giới hạn độ dài tên folder, khớp với cột name
+const maxFolderNameLength = 100
+
+type IFolderUsecase interface {
+	CreateFolder(ctx context.Context, req entities.FolderRequest) (*entities.FolderResponse, error)
+	ListFolders(ctx context.Context) (*entities.FolderListResponse, error)
+	UpdateFolder(ctx context.Context, id uint, req entities.FolderRequest) (*entities.FolderResponse, error)
+	DeleteFolder(ctx context.Context, id uint) error
+	SetURLFolder(ctx context.Context, shortCode string, req entities.SetURLFolderRequest) (*entities.URLOrganizationResponse, error)
+}
+
+type folderUsecase struct {
+	folderRepo    repositories.IFolderRepository
+	tagRepo       repositories.ITagRepository
+	urlRepo       repositories.IURLRepository
+	workspaceRepo repositories.IWorkspaceRepository
+}
+
+// NewFolderUsecase tạo folder usecase
+func NewFolderUsecase(folderRepo repositories.IFolderRepository, tagRepo repositories.ITagRepository, urlRepo repositories.IURLRepository, workspaceRepo repositories.IWorkspaceRepository) IFolderUsecase {
+	return &folderUsecase{
+		folderRepo:    folderRepo,
+		tagRepo:       tagRepo,
+		urlRepo:       urlRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// folderTree là toàn bộ folder theo ID, dùng để dựng path và kiểm tra vòng lặp
+type folderTree map[uint]*entities.Folder
+
+// CreateFolder tạo folder mới, parent_id null là folder gốc
+func (u *folderUsecase) CreateFolder(ctx context.Context, req entities.FolderRequest) (*entities.FolderResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "folderUsecase.CreateFolder")
+	defer span.End()
+
+	tree, err := u.loadTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	folder := &entities.Folder{}
+	if err := tree.apply(folder, req); err != nil {
+		return nil, err
+	}
+	if err := u.folderRepo.WithContext(ctx).CreateFolder(folder); err != nil {
+		return nil, fmt.Errorf("failed to create folder: %w", err)
+	}
+	tree[folder.ID] = folder
+
+	return tree.response(folder, 0), nil
+}
+
+// ListFolders lấy tất cả folder kèm path và số URL, sắp xếp theo path.
+// Người gọi không phải admin chỉ đếm link cá nhân của mình.
+func (u *folderUsecase) ListFolders(ctx context.Context) (*entities.FolderListResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "folderUsecase.ListFolders")
+	defer span.End()
+
+	tree, err := u.loadTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := u.folderRepo.WithContext(ctx).CountURLsByFolder(personalScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count folder URLs: %w", err)
+	}
+
+	response := &entities.FolderListResponse{Folders: make([]entities.FolderResponse, 0, len(tree))}
+	for _, folder := range tree {
+		response.Folders = append(response.Folders, *tree.response(folder, counts[folder.ID]))
+	}
+	sort.Slice(response.Folders, func(i, j int) bool {
+		return response.Folders[i].Path < response.Folders[j].Path
+	})
+	return response, nil
+}
+
+// UpdateFolder đổi tên hoặc chuyển folder sang folder cha khác (thay toàn bộ name và parent_id).
+// Folder dùng chung giữa mọi user nên cần scope admin.
+func (u *folderUsecase) UpdateFolder(ctx context.Context, id uint, req entities.FolderRequest) (*entities.FolderResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "folderUsecase.UpdateFolder")
+	defer span.End()
+
+	if err := requireAdminCaller(ctx, "updating a folder"); err != nil {
+		return nil, err
+	}
+	tree, err := u.loadTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	folder, ok := tree[id]
+	if !ok {
+		return nil, ErrFolderNotFound
+	}
+	if err := tree.apply(folder, req); err != nil {
+		return nil, err
+	}
+	if err := u.folderRepo.WithContext(ctx).UpdateFolder(folder); err != nil {
+		return nil, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	counts, err := u.folderRepo.WithContext(ctx).CountURLsByFolder(entities.URLScope{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count folder URLs: %w", err)
+	}
+	return tree.response(folder, counts[folder.ID]), nil
+}
+
+// DeleteFolder xóa folder rỗng, cần scope admin; folder còn folder con hoặc URL trả về ErrFolderNotEmpty
+func (u *folderUsecase) DeleteFolder(ctx context.Context, id uint) error {
+	ctx, span := utils.StartSpan(ctx, "folderUsecase.DeleteFolder")
+	defer span.End()
+
+	if err := requireAdminCaller(ctx, "deleting a folder"); err != nil {
+		return err
+	}
+	tree, err := u.loadTree(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := tree[id]; !ok {
+		return ErrFolderNotFound
+	}
+	for _, folder := range tree {
+		if folder.ParentID != nil && *folder.ParentID == id {
+			return ErrFolderNotEmpty.WithMessage("folder %d has subfolders", id)
+		}
+	}
+	// Đếm mọi URL, kể cả của user khác, để không xóa folder còn link
+	counts, err := u.folderRepo.WithContext(ctx).CountURLsByFolder(entities.URLScope{})
+	if err != nil {
+		return fmt.Errorf("failed to count folder URLs: %w", err)
+	}
+	if counts[id] > 0 {
+		return ErrFolderNotEmpty.WithMessage("folder %d contains %d URLs", id, counts[id])
+	}
+
+	if err := u.folderRepo.WithContext(ctx).DeleteFolder(id); err != nil {
+		return notFoundError(err, ErrFolderNotFound, "failed to delete folder")
+	}
+	return nil
+}
+
+// SetURLFolder chuyển URL vào folder; folder_id null hoặc 0 đưa URL ra khỏi folder
+func (u *folderUsecase) SetURLFolder(ctx context.Context, shortCode string, req entities.SetURLFolderRequest) (*entities.URLOrganizationResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "folderUsecase.SetURLFolder")
+	defer span.End()
+
+	folderID := req.FolderID
+	if folderID != nil && *folderID == 0 {
+		folderID = nil
+	}
+
+	urlEntity, err := getURLByShortCode(u.urlRepo.WithContext(ctx), shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeURL(ctx, u.workspaceRepo, urlEntity, entities.WorkspaceRoleEditor); err != nil {
+		return nil, err
+	}
+	if folderID != nil {
+		if _, err := u.folderRepo.WithContext(ctx).GetFolder(*folderID); err != nil {
+			return nil, notFoundError(err, ErrInvalidFolder.WithMessage("folder %d does not exist", *folderID), "failed to get folder")
+		}
+	}
+
+	if err := u.folderRepo.WithContext(ctx).SetURLFolder(urlEntity.ID, folderID); err != nil {
+		return nil, fmt.Errorf("failed to set URL folder: %w", err)
+	}
+	urlEntity.FolderID = folderID
+
+	return urlOrganization(ctx, u.tagRepo, urlEntity)
+}
+
+// loadTree đọc toàn bộ folder; số folder nhỏ nên dựng cây trong bộ nhớ đơn giản hơn CTE đệ quy
+func (u *folderUsecase) loadTree(ctx context.Context) (folderTree, error) {
+	folders, err := u.folderRepo.WithContext(ctx).ListFolders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+	tree := make(folderTree, len(folders))
+	for i := range folders {
+		tree[folders[i].ID] = &folders[i]
+	}
+	return tree, nil
+}
+
+// apply kiểm tra req và gán name, parent_id cho folder (folder mới có ID 0).
+// Folder cha phải tồn tại, không phải chính folder hay folder con của nó, và không có folder cùng tên.
+func (t folderTree) apply(folder *entities.Folder, req entities.FolderRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxFolderNameLength {
+		return ErrInvalidFolder.WithMessage("folder name must be 1-%d characters", maxFolderNameLength)
+	}
+	if strings.Contains(name, "/") {
+		return ErrInvalidFolder.WithMessage("folder name must not contain '/'")
+	}
+
+	parentID := req.ParentID
+	if parentID != nil && *parentID == 0 {
+		parentID = nil
+	}
+	if parentID != nil {
+		if _, ok := t[*parentID]; !ok {
+			return ErrInvalidFolder.WithMessage("parent folder %d does not exist", *parentID)
+		}
+		if folder.ID != 0 && t.isDescendant(*parentID, folder.ID) {
+			return ErrInvalidFolder.WithMessage("folder %d cannot be moved into itself or its subfolder", folder.ID)
+		}
+	}
+
+	for _, other := range t {
+		if other.ID != folder.ID && sameParent(other.ParentID, parentID) && strings.EqualFold(other.Name, name) {
+			return ErrFolderExists.WithMessage("folder %q already exists in %s", name, t.parentPath(parentID))
+		}
+	}
+
+	folder.Name = name
+	folder.ParentID = parentID
+	return nil
+}
+
+// isDescendant cho biết id có phải ancestorID hoặc nằm trong cây con của ancestorID
+func (t folderTree) isDescendant(id, ancestorID uint) bool {
+	// giới hạn số bước để dữ liệu lỗi (vòng lặp sẵn có) không làm treo request
+	for steps := 0; steps <= len(t); steps++ {
+		if id == ancestorID {
+			return true
+		}
+		folder, ok := t[id]
+		if !ok || folder.ParentID == nil {
+			return false
+		}
+		id = *folder.ParentID
+	}
+	return true
+}
+
+// path dựng đường dẫn dạng /cha/con của folder
+func (t folderTree) path(folder *entities.Folder) string {
+	names := []string{folder.Name}
+	parentID := folder.ParentID
+	for steps := 0; parentID != nil && steps < len(t); steps++ {
+		parent, ok := t[*parentID]
+		if !ok {
+			break
+		}
+		names = append(names, parent.Name)
+		parentID = parent.ParentID
+	}
+
+	var b strings.Builder
+	for i := len(names) - 1; i >= 0; i-- {
+		b.WriteString("/")
+		b.WriteString(names[i])
+	}
+	return b.String()
+}
+
+// parentPath là path của folder cha, "/" cho gốc; dùng trong message lỗi
+func (t folderTree) parentPath(parentID *uint) string {
+	if parentID == nil {
+		return "/"
+	}
+	return t.path(t[*parentID])
+}
+
+func (t folderTree) response(folder *entities.Folder, urlCount int64) *entities.FolderResponse {
+	return &entities.FolderResponse{
+		ID:        folder.ID,
+		Name:      folder.Name,
+		ParentID:  folder.ParentID,
+		Path:      t.path(folder),
+		URLCount:  urlCount,
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+	}
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrURLForbidden trả về khi người gọi không phải owner của URL, không đủ role trong workspace của URL và không có scope admin
+var ErrURLForbidden = newDomainError(KindForbidden, "url_forbidden", "URL belongs to another user")
+
+// isAdminCaller cho biết người gọi có quyền trên mọi URL: API key có scope admin,
+// hoặc tiến trình tin cậy đánh dấu bằng WithSystemCaller. Thiếu API key không được coi là admin.
+func isAdminCaller(ctx context.Context) bool {
+	if isSystemCaller(ctx) {
+		return true
+	}
+	key := APIKeyFromContext(ctx)
+	return key != nil && key.HasScope(entities.ScopeAdmin)
+}
+
+// callerUserID trả về user sở hữu API key của request, nil nếu key không gắn user
+func callerUserID(ctx context.Context) *uint {
+	if key := APIKeyFromContext(ctx); key != nil {
+		return key.UserID
+	}
+	return nil
+}
+
+// visibleOwner trả về owner mà người gọi được xem số liệu tổng hợp: nil (mọi owner) với admin,
+// user của key với người gọi khác; key không gắn user không thấy link nào
+func visibleOwner(ctx context.Context) *uint {
+	if isAdminCaller(ctx) {
+		return nil
+	}
+	if userID := callerUserID(ctx); userID != nil {
+		return userID
+	}
+	none := uint(0)
+	return &none
+}
+
+// personalScope là scope số liệu tổng hợp người gọi được xem khi không chọn workspace:
+// mọi URL với admin, link cá nhân của người gọi với key khác
+func personalScope(ctx context.Context) entities.URLScope {
+	if isAdminCaller(ctx) {
+		return entities.URLScope{}
+	}
+	personal := uint(0)
+	return entities.URLScope{OwnerID: visibleOwner(ctx), WorkspaceID: &personal}
+}
+
+// requireAdminCaller trả về ErrInsufficientScope khi người gọi không có scope admin,
+// dùng cho thao tác trên tài nguyên dùng chung giữa mọi user (tag, folder)
+func requireAdminCaller(ctx context.Context, action string) error {
+	if !isAdminCaller(ctx) {
+		return ErrInsufficientScope.WithMessage("%s requires scope %s", action, entities.ScopeAdmin)
+	}
+	return nil
+}
+
+// authorizeURL chỉ cho người có quyền trên URL thao tác: admin, owner của link cá nhân,
+// hoặc member có role tối thiểu role trong workspace chứa link
+func authorizeURL(ctx context.Context, workspaceRepo repositories.IWorkspaceRepository, urlEntity *entities.URL, role string) error {
+	if isAdminCaller(ctx) {
+		return nil
+	}
+	if urlEntity.WorkspaceID != nil {
+		return requireWorkspaceRole(ctx, workspaceRepo, *urlEntity.WorkspaceID, role, ErrURLForbidden)
+	}
+	userID := callerUserID(ctx)
+	if userID == nil || urlEntity.OwnerID == nil || *userID != *urlEntity.OwnerID {
+		return ErrURLForbidden
+	}
+	return nil
+}
+
+// requireWorkspaceRole trả về forbidden khi người gọi không phải admin và không có role tối thiểu role trong workspace
+func requireWorkspaceRole(ctx context.Context, workspaceRepo repositories.IWorkspaceRepository, workspaceID uint, role string, forbidden *DomainError) error {
+	if isAdminCaller(ctx) {
+		return nil
+	}
+	member, err := callerMember(ctx, workspaceRepo, workspaceID)
+	if err != nil {
+		return err
+	}
+	if member == nil || !member.HasRole(role) {
+		return forbidden.WithMessage("requires %s role in workspace %d", role, workspaceID)
+	}
+	return nil
+}
+
+// callerMember trả về member của người gọi trong workspace, nil nếu người gọi không phải member
+func callerMember(ctx context.Context, workspaceRepo repositories.IWorkspaceRepository, workspaceID uint) (*entities.WorkspaceMember, error) {
+	userID := callerUserID(ctx)
+	if userID == nil {
+		return nil, nil
+	}
+	member, err := workspaceRepo.WithContext(ctx).GetMember(workspaceID, *userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace member: %w", err)
+	}
+	return member, nil
+}
+
+// findAuthorizedURL giống findURL nhưng trả về ErrURLForbidden khi người gọi không có role tối thiểu role trên URL
+func (u *urlUsecase) findAuthorizedURL(ctx context.Context, shortCode, role string) (*entities.URL, error) {
+	urlEntity, err := u.findURL(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeURL(ctx, u.workspaceRepo, urlEntity, role); err != nil {
+		return nil, err
+	}
+	return urlEntity, nil
+}
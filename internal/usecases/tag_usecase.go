@@ -0,0 +1,310 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// Lỗi của tag
+var (
+	// ErrInvalidTag trả về khi tên tag hoặc danh sách tag không hợp lệ
+	ErrInvalidTag = newDomainError(KindValidation, "invalid_tag", "invalid tag")
+	// ErrTagNotFound trả về khi tag không tồn tại
+	ErrTagNotFound = newDomainError(KindNotFound, "tag_not_found", "tag not found")
+	// ErrTagExists trả về khi tạo hoặc đổi tên trùng một tag đã có
+	ErrTagExists = newDomainError(KindConflict, "tag_exists", "tag already exists")
+)
+
+const (
+	// maxTagsPerURL giới hạn số tag gắn cho một URL
+	maxTagsPerURL = 50
+	// tagStatsTopURLs là số URL nhiều click nhất trả về trong thống kê tag
+	tagStatsTopURLs = 10
+)
+
+// tagNamePattern cho phép tag dạng nhóm:giá-trị như campaign:black-friday; không có "/" để dùng được trong path
+var tagNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9:._-]{0,63}$`)
+
+type ITagUsecase interface {
+	CreateTag(ctx context.Context, req entities.TagRequest) (*entities.TagResponse, error)
+	ListTags(ctx context.Context) (*entities.TagListResponse, error)
+	RenameTag(ctx context.Context, name string, req entities.TagRequest) (*entities.TagResponse, error)
+	DeleteTag(ctx context.Context, name string) error
+	GetTagStats(ctx context.Context, name string, req entities.TagStatsRequest) (*entities.TagStatsResponse, error)
+	SetURLTags(ctx context.Context, shortCode string, req entities.SetURLTagsRequest) (*entities.URLOrganizationResponse, error)
+}
+
+type tagUsecase struct {
+	tagRepo       repositories.ITagRepository
+	urlRepo       repositories.IURLRepository
+	workspaceRepo repositories.IWorkspaceRepository
+	baseURL       string
+}
+
+// NewTagUsecase tạo tag usecase
+func NewTagUsecase(tagRepo repositories.ITagRepository, urlRepo repositories.IURLRepository, workspaceRepo repositories.IWorkspaceRepository, baseURL string) ITagUsecase {
+	return &tagUsecase{
+		tagRepo:       tagRepo,
+		urlRepo:       urlRepo,
+		workspaceRepo: workspaceRepo,
+		baseURL:       baseURL,
+	}
+}
+
+// CreateTag tạo tag chưa gắn cho URL nào
+func (u *tagUsecase) CreateTag(ctx context.Context, req entities.TagRequest) (*entities.TagResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "tagUsecase.CreateTag")
+	defer span.End()
+
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.ensureTagAvailable(ctx, name); err != nil {
+		return nil, err
+	}
+
+	tag := &entities.Tag{Name: name}
+	if err := u.tagRepo.WithContext(ctx).CreateTag(tag); err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return &entities.TagResponse{ID: tag.ID, Name: tag.Name, CreatedAt: tag.CreatedAt}, nil
+}
+
+// ListTags lấy tất cả tag kèm số URL của từng tag; người gọi không phải admin chỉ đếm link cá nhân của mình
+func (u *tagUsecase) ListTags(ctx context.Context) (*entities.TagListResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "tagUsecase.ListTags")
+	defer span.End()
+
+	tags, err := u.tagRepo.WithContext(ctx).ListTags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	counts, err := u.tagRepo.WithContext(ctx).CountURLsByTag(personalScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tagged URLs: %w", err)
+	}
+
+	response := &entities.TagListResponse{Tags: make([]entities.TagResponse, 0, len(tags))}
+	for _, tag := range tags {
+		response.Tags = append(response.Tags, entities.TagResponse{
+			ID:        tag.ID,
+			Name:      tag.Name,
+			URLCount:  counts[tag.ID],
+			CreatedAt: tag.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// RenameTag đổi tên tag, các URL đang gắn tag giữ nguyên. Tag dùng chung giữa mọi user nên cần scope admin.
+func (u *tagUsecase) RenameTag(ctx context.Context, name string, req entities.TagRequest) (*entities.TagResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "tagUsecase.RenameTag")
+	defer span.End()
+
+	if err := requireAdminCaller(ctx, "renaming a tag"); err != nil {
+		return nil, err
+	}
+	tag, err := u.findTag(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	newName, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if newName != tag.Name {
+		if err := u.ensureTagAvailable(ctx, newName); err != nil {
+			return nil, err
+		}
+		tag.Name = newName
+		if err := u.tagRepo.WithContext(ctx).UpdateTag(tag); err != nil {
+			return nil, fmt.Errorf("failed to rename tag: %w", err)
+		}
+	}
+
+	counts, err := u.tagRepo.WithContext(ctx).CountURLsByTag(entities.URLScope{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tagged URLs: %w", err)
+	}
+	return &entities.TagResponse{ID: tag.ID, Name: tag.Name, URLCount: counts[tag.ID], CreatedAt: tag.CreatedAt}, nil
+}
+
+// DeleteTag xóa tag và gỡ tag khỏi mọi URL (URL không bị xóa), cần scope admin
+func (u *tagUsecase) DeleteTag(ctx context.Context, name string) error {
+	ctx, span := utils.StartSpan(ctx, "tagUsecase.DeleteTag")
+	defer span.End()
+
+	if err := requireAdminCaller(ctx, "deleting a tag"); err != nil {
+		return err
+	}
+	tag, err := u.findTag(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := u.tagRepo.WithContext(ctx).DeleteTag(tag.ID); err != nil {
+		return notFoundError(err, ErrTagNotFound, "failed to delete tag")
+	}
+	return nil
+}
+
+// GetTagStats tổng hợp thống kê trên mọi URL gắn tag. Có workspace_id thì chỉ tính link của workspace
+// (cần role analyst), không có thì người gọi không phải admin chỉ thấy link cá nhân của mình.
+func (u *tagUsecase) GetTagStats(ctx context.Context, name string, req entities.TagStatsRequest) (*entities.TagStatsResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "tagUsecase.GetTagStats")
+	defer span.End()
+
+	tag, err := u.findTag(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := entities.URLScope{WorkspaceID: req.WorkspaceID}
+	if req.WorkspaceID != nil && *req.WorkspaceID != 0 {
+		if err := requireWorkspaceRole(ctx, u.workspaceRepo, *req.WorkspaceID, entities.WorkspaceRoleAnalyst, ErrWorkspaceForbidden); err != nil {
+			return nil, err
+		}
+	} else if !isAdminCaller(ctx) {
+		scope = personalScope(ctx)
+	}
+	stats, err := u.tagRepo.WithContext(ctx).GetTagStats(tag.ID, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag stats: %w", err)
+	}
+	uniqueVisitors, err := u.tagRepo.WithContext(ctx).CountTagUniqueVisitors(tag.ID, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unique visitors: %w", err)
+	}
+	topURLs, err := u.tagRepo.WithContext(ctx).ListTopURLsByTag(tag.ID, scope, tagStatsTopURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top URLs: %w", err)
+	}
+
+	response := &entities.TagStatsResponse{
+		Tag:            tag.Name,
+		URLCount:       stats.URLCount,
+		ActiveURLCount: stats.ActiveURLCount,
+		TotalClicks:    stats.TotalClicks,
+		UniqueVisitors: uniqueVisitors,
+		LastClicked:    stats.LastClicked,
+		TopURLs:        make([]entities.URLListItem, 0, len(topURLs)),
+	}
+	for i := range topURLs {
+		response.TopURLs = append(response.TopURLs, newURLListItem(u.baseURL, &topURLs[i]))
+	}
+	return response, nil
+}
+
+// SetURLTags thay toàn bộ tag của URL; tag chưa có được tạo mới, danh sách rỗng gỡ hết tag
+func (u *tagUsecase) SetURLTags(ctx context.Context, shortCode string, req entities.SetURLTagsRequest) (*entities.URLOrganizationResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "tagUsecase.SetURLTags")
+	defer span.End()
+
+	names, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) > maxTagsPerURL {
+		return nil, ErrInvalidTag.WithMessage("a URL can have at most %d tags", maxTagsPerURL)
+	}
+
+	urlEntity, err := getURLByShortCode(u.urlRepo.WithContext(ctx), shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeURL(ctx, u.workspaceRepo, urlEntity, entities.WorkspaceRoleEditor); err != nil {
+		return nil, err
+	}
+
+	tags, err := u.tagRepo.WithContext(ctx).FindOrCreateTags(names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tags: %w", err)
+	}
+	if err := u.tagRepo.WithContext(ctx).ReplaceURLTags(urlEntity.ID, tags); err != nil {
+		return nil, fmt.Errorf("failed to set URL tags: %w", err)
+	}
+
+	return urlOrganization(ctx, u.tagRepo, urlEntity)
+}
+
+// findTag lấy tag theo tên trong path (chuẩn hóa trước khi tìm)
+func (u *tagUsecase) findTag(ctx context.Context, name string) (*entities.Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := u.tagRepo.WithContext(ctx).GetTagByName(name)
+	if err != nil {
+		return nil, notFoundError(err, ErrTagNotFound, "failed to get tag")
+	}
+	return tag, nil
+}
+
+// ensureTagAvailable trả về ErrTagExists nếu đã có tag tên name
+func (u *tagUsecase) ensureTagAvailable(ctx context.Context, name string) error {
+	_, err := u.tagRepo.WithContext(ctx).GetTagByName(name)
+	if err == nil {
+		return ErrTagExists.WithMessage("tag %q already exists", name)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get tag: %w", err)
+	}
+	return nil
+}
+
+// urlOrganization đọc folder và tag hiện tại của URL
+func urlOrganization(ctx context.Context, tagRepo repositories.ITagRepository, urlEntity *entities.URL) (*entities.URLOrganizationResponse, error) {
+	tags, err := tagRepo.WithContext(ctx).GetURLTags(urlEntity.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get URL tags: %w", err)
+	}
+	return &entities.URLOrganizationResponse{
+		ShortCode: urlEntity.ShortCode,
+		FolderID:  urlEntity.FolderID,
+		Tags:      tagNames(tags),
+	}, nil
+}
+
+// normalizeTagName chuẩn hóa tên tag về chữ thường và kiểm tra định dạng
+func normalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !tagNamePattern.MatchString(name) {
+		return "", ErrInvalidTag.WithMessage("invalid tag %q: use 1-64 characters a-z 0-9 : . _ - starting with a letter or digit", name)
+	}
+	return name, nil
+}
+
+// normalizeTagNames chuẩn hóa danh sách tag, bỏ tag trùng và giữ thứ tự xuất hiện
+func normalizeTagNames(names []string) ([]string, error) {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, raw := range names {
+		name, err := normalizeTagName(raw)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// tagNames lấy tên của các tag, luôn trả slice khác nil để JSON là []
+func tagNames(tags []entities.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// defaultBatchMaxItems là số item tối đa khi usecase không có config
+const defaultBatchMaxItems = 500
+
+// ErrInvalidBatch trả về khi request tạo hàng loạt không hợp lệ (rỗng hoặc quá nhiều item)
+var ErrInvalidBatch = newDomainError(KindValidation, "invalid_batch", "invalid batch request")
+
+// CreateShortURLs tạo nhiều short URL với một lần lấy lock và một transaction.
+// Item không hợp lệ được trả lỗi riêng, các item còn lại vẫn được tạo.
+func (u *urlUsecase) CreateShortURLs(ctx context.Context, req entities.BatchCreateURLRequest) (*entities.BatchCreateURLResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.CreateShortURLs")
+	defer span.End()
+
+	maxItems := u.batchMaxItems()
+	if len(req.Items) == 0 || len(req.Items) > maxItems {
+		return nil, ErrInvalidBatch.WithMessage("items must contain between 1 and %d URLs", maxItems)
+	}
+
+	response := &entities.BatchCreateURLResponse{
+		Results: make([]entities.BatchCreateURLResult, len(req.Items)),
+	}
+	var valid []int
+	var urls []*entities.URL
+	for i, item := range req.Items {
+		response.Results[i].Index = i
+		if err := u.validateURL(item.OriginalURL); err != nil {
+			response.Results[i].Error = err.Error()
+			continue
+		}
+		// Lỗi domain (redirect type, workspace, role) chỉ làm hỏng item, lỗi DB làm hỏng cả batch
+		urlEntity, err := u.newURL(ctx, item)
+		var domainErr *DomainError
+		if errors.As(err, &domainErr) {
+			response.Results[i].Error = err.Error()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		valid = append(valid, i)
+		urls = append(urls, urlEntity)
+	}
+
+	if len(valid) > 0 {
+		if err := u.createSequential(ctx, urls); err != nil {
+			return nil, err
+		}
+		for j, i := range valid {
+			response.Results[i].URL = &entities.CreateURLResponse{
+				ShortCode:   urls[j].ShortCode,
+				ShortURL:    fmt.Sprintf("%s/%s", u.baseURL, urls[j].ShortCode),
+				OriginalURL: urls[j].OriginalURL,
+				CreatedAt:   urls[j].CreatedAt,
+			}
+			u.publish(entities.WebhookEventURLCreated, u.urlEventData(urls[j], urls[j].CreatedAt))
+		}
+	}
+
+	response.Created = len(valid)
+	response.Failed = len(req.Items) - len(valid)
+	return response, nil
+}
+
+// createSequential cấp short code liên tiếp cho các URL đã dựng bằng newURL và tạo chúng trong một transaction
+func (u *urlUsecase) createSequential(ctx context.Context, urls []*entities.URL) error {
+	lrs, err := u.locker.Lock(ctx, createURLLockKey)
+	if err != nil {
+		return lockError(err)
+	}
+	defer func() {
+		if err := u.locker.Unlock(context.WithoutCancel(ctx), lrs); err != nil {
+			fmt.Printf("Failed to unlock: %v\n", err)
+		}
+	}()
+
+	lastID, err := u.repo(ctx).GetLastID()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get last ID: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for j, urlEntity := range urls {
+		urlEntity.ShortCode = fmt.Sprintf("%v", lastID+uint(j)+1)
+		urlEntity.CreatedAt = now
+		urlEntity.UpdatedAt = now
+	}
+
+	if err := u.repo(ctx).CreateBatch(urls); err != nil {
+		return fmt.Errorf("failed to create URLs: %w", err)
+	}
+	return nil
+}
+
+// batchMaxItems trả về số item tối đa của một batch theo config
+func (u *urlUsecase) batchMaxItems() int {
+	if u.config == nil || u.config.Links.BatchMaxItems <= 0 {
+		return defaultBatchMaxItems
+	}
+	return u.config.Links.BatchMaxItems
+}
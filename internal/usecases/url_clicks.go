@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// Giới hạn số click trả về mỗi trang
+const (
+	defaultClickPageSize = 50
+	maxClickPageSize     = 500
+)
+
+// ListClicks lấy lịch sử click theo trang (mới nhất trước) với keyset cursor
+func (u *urlUsecase) ListClicks(ctx context.Context, shortCode string, req entities.ClickListRequest) (*entities.ClickListResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.ListClicks")
+	defer span.End()
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultClickPageSize
+	}
+	if limit < 0 || limit > maxClickPageSize {
+		return nil, ErrInvalidStatsQuery.WithMessage("limit must be between 1 and %d", maxClickPageSize)
+	}
+
+	filter, err := parseClickFilter(req.From, req.To, req.Country, req.Referrer)
+	if err != nil {
+		return nil, err
+	}
+	if req.Cursor != "" {
+		if filter.BeforeID, err = decodeClickCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	// Lấy dư một record để biết còn trang sau không
+	filter.Limit = limit + 1
+
+	urlEntity, err := u.findAuthorizedURL(ctx, shortCode, entities.WorkspaceRoleAnalyst)
+	if err != nil {
+		return nil, err
+	}
+
+	clicks, err := u.repo(ctx).ListAnalytics(urlEntity.ID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clicks: %w", err)
+	}
+
+	response := &entities.ClickListResponse{
+		ShortCode: urlEntity.ShortCode,
+		Clicks:    clicks,
+	}
+	if len(clicks) > limit {
+		response.Clicks = clicks[:limit]
+		response.HasMore = true
+		response.NextCursor = encodeClickCursor(response.Clicks[limit-1].ID)
+	}
+	if response.Clicks == nil {
+		response.Clicks = []entities.Analytics{}
+	}
+
+	return response, nil
+}
+
+// parseClickFilter parse các bộ lọc chung của lịch sử click (khoảng thời gian, country, referrer)
+func parseClickFilter(from, to, country, referrer string) (entities.ClickFilter, error) {
+	var filter entities.ClickFilter
+	var err error
+	if from != "" {
+		if filter.From, err = parseStatsTime(from, time.UTC); err != nil {
+			return filter, err
+		}
+	}
+	if to != "" {
+		if filter.To, err = parseStatsTime(to, time.UTC); err != nil {
+			return filter, err
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, ErrInvalidStatsQuery.WithMessage("from must be before to")
+	}
+
+	filter.Country = strings.ToUpper(strings.TrimSpace(country))
+	if referrer != "" {
+		filter.ReferrerHost = utils.NormalizeHost(referrer)
+	}
+	return filter, nil
+}
+
+// encodeClickCursor mã hóa id của click cuối trang thành cursor opaque
+func encodeClickCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+// decodeClickCursor giải mã cursor về id
+func decodeClickCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidStatsQuery.WithMessage("invalid cursor")
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidStatsQuery.WithMessage("invalid cursor")
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,169 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// ErrClickStreamUnavailable được trả về khi service không có click broker
+var ErrClickStreamUnavailable = newDomainError(KindUnavailable, "click_stream_unavailable", "live click stream is not available")
+
+// clickReplayPage là số click đọc từ database mỗi lần khi replay cho client reconnect với Last-Event-ID
+const clickReplayPage = 500
+
+// maxPendingClicks là số live event tối đa chờ gửi cho một client đọc chậm
+const maxPendingClicks = 10000
+
+// StreamClicks subscribe live click của một link.
+// Nếu lastEventID > 0, mọi click có id lớn hơn được replay từ database theo thứ tự id tăng dần,
+// từng trang clickReplayPage click, trước khi chuyển sang live.
+// Channel trả về bị đóng khi ctx kết thúc.
+func (u *urlUsecase) StreamClicks(ctx context.Context, shortCode string, lastEventID uint) (<-chan entities.ClickEvent, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.StreamClicks")
+	defer span.End()
+
+	urlEntity, err := u.findAuthorizedURL(ctx, shortCode, entities.WorkspaceRoleAnalyst)
+	if err != nil {
+		return nil, err
+	}
+	if u.clicks == nil {
+		return nil, ErrClickStreamUnavailable
+	}
+
+	// Subscribe trước khi đọc replay để không bỏ sót click xen giữa hai bước
+	live, err := u.clicks.Subscribe(ctx, urlEntity.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe click stream: %w", err)
+	}
+
+	// Đọc trang replay đầu trước khi trả về để lỗi database được báo cho client
+	var replay []entities.Analytics
+	if lastEventID > 0 {
+		replay, err = u.replayClicks(ctx, urlEntity.ID, lastEventID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make(chan entities.ClickEvent)
+	go func() {
+		defer close(out)
+
+		// Live event nhận được trong lúc replay hoặc khi client đọc chậm được giữ trong pending,
+		// để buffer của broker không đầy và làm rơi event. Quá maxPendingClicks thì đóng stream,
+		// client reconnect với Last-Event-ID và lấy lại phần còn thiếu từ database.
+		var pending []entities.ClickEvent
+		receive := func(event entities.ClickEvent, ok bool) bool {
+			if !ok || len(pending) >= maxPendingClicks {
+				return false
+			}
+			pending = append(pending, event)
+			return true
+		}
+		send := func(event entities.ClickEvent) bool {
+			for {
+				select {
+				case out <- event:
+					return true
+				case received, ok := <-live:
+					if !receive(received, ok) {
+						return false
+					}
+				case <-ctx.Done():
+					return false
+				}
+			}
+		}
+
+		// Replay từng trang tới khi trang đọc được chưa đầy, tức đã bắt kịp database
+		replayed := lastEventID
+		for len(replay) > 0 {
+			for i := range replay {
+				event := newClickEvent(urlEntity.ShortCode, &replay[i])
+				if !send(event) {
+					return
+				}
+				replayed = event.ID
+			}
+			if len(replay) < clickReplayPage {
+				break
+			}
+
+			var err error
+			if replay, err = u.replayClicks(ctx, urlEntity.ID, replayed); err != nil {
+				// Đóng stream, client reconnect với Last-Event-ID và replay tiếp từ event cuối đã nhận
+				fmt.Printf("Failed to replay click stream: %v\n", err)
+				return
+			}
+		}
+
+		for {
+			for len(pending) > 0 {
+				event := pending[0]
+				pending = pending[1:]
+				// Chỉ bỏ event đã gửi trong phần replay. Click được publish sau khi ghi nên có thể
+				// tới không theo thứ tự id; mốc replayed không tăng theo live event.
+				if event.ID <= replayed {
+					continue
+				}
+				if !send(event) {
+					return
+				}
+			}
+
+			select {
+			case event, ok := <-live:
+				if !receive(event, ok) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// replayClicks đọc tối đa clickReplayPage click có id lớn hơn afterID, theo id tăng dần
+func (u *urlUsecase) replayClicks(ctx context.Context, urlID, afterID uint) ([]entities.Analytics, error) {
+	clicks, err := u.repo(ctx).ListAnalytics(urlID, entities.ClickFilter{
+		AfterID:   afterID,
+		Ascending: true,
+		Limit:     clickReplayPage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay clicks: %w", err)
+	}
+	return clicks, nil
+}
+
+// publishClick publish click vừa ghi cho các subscriber, lỗi chỉ được log
+func (u *urlUsecase) publishClick(ctx context.Context, shortCode string, analytics *entities.Analytics) {
+	if u.clicks == nil {
+		return
+	}
+	event := newClickEvent(shortCode, analytics)
+	if err := u.clicks.Publish(ctx, &event); err != nil {
+		fmt.Printf("Failed to publish click event: %v\n", err)
+	}
+}
+
+// newClickEvent chuyển bản ghi analytics thành event, bỏ IP và User-Agent
+func newClickEvent(shortCode string, analytics *entities.Analytics) entities.ClickEvent {
+	return entities.ClickEvent{
+		ID:              analytics.ID,
+		URLID:           analytics.URLID,
+		ShortCode:       shortCode,
+		ClickedAt:       analytics.ClickedAt,
+		Country:         analytics.Country,
+		ReferrerHost:    analytics.ReferrerHost,
+		ReferrerChannel: analytics.ReferrerChannel,
+		Browser:         analytics.Browser,
+		OS:              analytics.OS,
+		DeviceType:      analytics.DeviceType,
+	}
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// exportFlushEvery là số dòng giữa hai lần flush xuống client
+const exportFlushEvery = 500
+
+// clickCSVHeader là header của file CSV export
+var clickCSVHeader = []string{
+	"id", "clicked_at", "ip_address", "user_agent", "referer", "referrer_host", "referrer_channel",
+	"country", "city", "browser", "browser_version", "os", "device_type",
+}
+
+// ClickExport là một export đã được validate, sẵn sàng stream xuống writer
+type ClickExport struct {
+	Filename    string
+	ContentType string
+	ctx         context.Context
+	write       func(ctx context.Context, w io.Writer) error
+}
+
+// Stream ghi dữ liệu export xuống w. Span riêng vì ExportClicks đã kết thúc trước khi stream.
+func (e *ClickExport) Stream(w io.Writer) error {
+	ctx, span := utils.StartSpan(e.ctx, "ClickExport.Stream")
+	defer span.End()
+
+	return e.write(ctx, w)
+}
+
+// ExportClicks validate request và chuẩn bị export click dạng CSV hoặc NDJSON.
+// Dữ liệu chỉ được đọc từ database khi gọi Stream, từng dòng một qua cursor.
+func (u *urlUsecase) ExportClicks(ctx context.Context, shortCode string, req entities.ClickExportRequest) (*ClickExport, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.ExportClicks")
+	defer span.End()
+
+	format := req.Format
+	if format == "" {
+		format = entities.ExportFormatCSV
+	}
+	if format != entities.ExportFormatCSV && format != entities.ExportFormatNDJSON {
+		return nil, ErrInvalidStatsQuery.WithMessage("format must be csv or ndjson")
+	}
+
+	filter, err := parseClickFilter(req.From, req.To, req.Country, req.Referrer)
+	if err != nil {
+		return nil, err
+	}
+
+	urlEntity, err := u.findAuthorizedURL(ctx, shortCode, entities.WorkspaceRoleAnalyst)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &ClickExport{
+		Filename: fmt.Sprintf("clicks-%s-%s.%s", urlEntity.ShortCode, time.Now().UTC().Format("20060102"), format),
+		ctx:      ctx,
+	}
+	if format == entities.ExportFormatCSV {
+		export.ContentType = "text/csv; charset=utf-8"
+		export.write = func(ctx context.Context, w io.Writer) error {
+			return u.writeClicksCSV(ctx, w, urlEntity.ID, filter)
+		}
+	} else {
+		export.ContentType = "application/x-ndjson"
+		export.write = func(ctx context.Context, w io.Writer) error {
+			return u.writeClicksNDJSON(ctx, w, urlEntity.ID, filter)
+		}
+	}
+	return export, nil
+}
+
+// writeClicksCSV stream click dạng CSV
+func (u *urlUsecase) writeClicksCSV(ctx context.Context, w io.Writer, urlID uint, filter entities.ClickFilter) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(clickCSVHeader); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := u.repo(ctx).StreamAnalytics(urlID, filter, func(a *entities.Analytics) error {
+		if err := cw.Write([]string{
+			strconv.FormatUint(uint64(a.ID), 10),
+			a.ClickedAt.UTC().Format(time.RFC3339Nano),
+			csvCell(a.IPAddress),
+			csvCell(a.UserAgent),
+			csvCell(a.Referer),
+			csvCell(a.ReferrerHost),
+			csvCell(a.ReferrerChannel),
+			csvCell(a.Country),
+			csvCell(a.City),
+			csvCell(a.Browser),
+			csvCell(a.BrowserVersion),
+			csvCell(a.OS),
+			csvCell(a.DeviceType),
+		}); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushEvery == 0 {
+			cw.Flush()
+			flushWriter(w)
+		}
+		return cw.Error()
+	})
+	cw.Flush()
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// csvCell thêm ' trước giá trị bắt đầu bằng = + - @ tab hoặc CR để spreadsheet không chạy
+// User-Agent, Referer... do visitor gửi lên như công thức (CSV injection)
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// writeClicksNDJSON stream click dạng newline-delimited JSON
+func (u *urlUsecase) writeClicksNDJSON(ctx context.Context, w io.Writer, urlID uint, filter entities.ClickFilter) error {
+	encoder := json.NewEncoder(w)
+
+	rows := 0
+	return u.repo(ctx).StreamAnalytics(urlID, filter, func(a *entities.Analytics) error {
+		if err := encoder.Encode(a); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushEvery == 0 {
+			flushWriter(w)
+		}
+		return nil
+	})
+}
+
+// flushWriter flush writer nếu hỗ trợ (ví dụ http.ResponseWriter)
+func flushWriter(w io.Writer) {
+	if f, ok := w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,422 @@
+package usecases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// defaultImportMaxRows là số dòng tối đa khi usecase không có config
+const defaultImportMaxRows = 100000
+
+// ErrInvalidImport trả về khi cả file import không đọc được (sai định dạng, thiếu cột, quá nhiều dòng)
+var ErrInvalidImport = newDomainError(KindValidation, "invalid_import", "invalid import file")
+
+// importCodePattern giới hạn ký tự của short code được import
+var importCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// reservedShortCodes là các path gốc trùng với route của service
+var reservedShortCodes = map[string]bool{"api": true, "health": true, "metrics": true}
+
+// Tên cột (đã chuẩn hóa: chữ thường, bỏ ký tự không phải chữ/số) theo thứ tự ưu tiên.
+// Bao gồm tên cột trong file export của các shortener phổ biến (Bitly, YOURLS, Shlink, Kutt).
+var (
+	importCodeColumns        = []string{"code", "shortcode", "keyword", "slug", "alias", "address", "backhalf", "bitlink", "shorturl", "shortlink"}
+	importDestinationColumns = []string{"destination", "originalurl", "longurl", "target", "url", "destinationurl", "longlink"}
+	importCreatedColumns     = []string{"created", "createdat", "datecreated", "creationdate", "timestamp", "date"}
+	importClicksColumns      = []string{"clicks", "clickcount", "visits", "visitscount", "visitcount", "totalclicks", "hits"}
+)
+
+// importDateLayouts là các định dạng ngày tạo được chấp nhận (không có timezone thì hiểu là UTC)
+var importDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// importRow là một dòng dữ liệu thô đọc từ file
+type importRow struct {
+	row         int
+	code        string
+	destination string
+	created     string
+	clicks      string
+}
+
+// ImportURLs import link từ hệ thống khác, giữ nguyên short code.
+// Dòng lỗi hoặc trùng code được ghi vào báo cáo và bỏ qua; ở chế độ apply,
+// các dòng hợp lệ được tạo trong một transaction.
+func (u *urlUsecase) ImportURLs(ctx context.Context, r io.Reader, req entities.ImportURLsRequest) (*entities.ImportReport, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.ImportURLs")
+	defer span.End()
+
+	mode := req.Mode
+	if mode == "" {
+		mode = entities.ImportModeDryRun
+	}
+	if mode != entities.ImportModeDryRun && mode != entities.ImportModeApply {
+		return nil, ErrInvalidImport.WithMessage("mode must be dry-run or apply")
+	}
+
+	br := bufio.NewReader(r)
+	format := req.Format
+	if format == "" {
+		format = sniffImportFormat(br)
+	}
+
+	var rows []importRow
+	var err error
+	switch format {
+	case entities.ImportFormatCSV:
+		rows, err = u.readImportCSV(br)
+	case entities.ImportFormatJSON:
+		rows, err = u.readImportJSON(br)
+	default:
+		return nil, ErrInvalidImport.WithMessage("format must be csv or json")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entities.ImportReport{
+		Mode:   mode,
+		Format: format,
+		Total:  len(rows),
+		Issues: []entities.ImportRowIssue{},
+	}
+	reject := func(row importRow, status, reason string) {
+		report.Issues = append(report.Issues, entities.ImportRowIssue{
+			Row:         row.row,
+			Code:        row.code,
+			OriginalURL: row.destination,
+			Status:      status,
+			Error:       reason,
+		})
+		if status == entities.ImportRowInvalid {
+			report.Invalid++
+		} else {
+			report.Conflicts++
+		}
+	}
+
+	now := time.Now().UTC()
+	ownerID := callerUserID(ctx)
+	seen := make(map[string]bool, len(rows))
+	var candidates []importRow
+	var urls []*entities.URL
+	for _, row := range rows {
+		urlEntity, status, reason := u.parseImportRow(row, now)
+		if urlEntity == nil {
+			reject(row, status, reason)
+			continue
+		}
+		urlEntity.OwnerID = ownerID
+		if seen[urlEntity.ShortCode] {
+			reject(row, entities.ImportRowConflict, "duplicate code in file")
+			continue
+		}
+		seen[urlEntity.ShortCode] = true
+		candidates = append(candidates, row)
+		urls = append(urls, urlEntity)
+	}
+
+	if mode == entities.ImportModeApply && len(urls) > 0 {
+		// Giữ lock tạo link để hai lần import đồng thời không cùng qua bước kiểm tra trùng
+		lrs, err := u.locker.Lock(ctx, createURLLockKey)
+		if err != nil {
+			return nil, lockError(err)
+		}
+		defer func() {
+			if err := u.locker.Unlock(context.WithoutCancel(ctx), lrs); err != nil {
+				fmt.Printf("Failed to unlock: %v\n", err)
+			}
+		}()
+	}
+
+	if len(urls) > 0 {
+		codes := make([]string, len(urls))
+		for i, urlEntity := range urls {
+			codes[i] = urlEntity.ShortCode
+		}
+		existing, err := u.repo(ctx).FindExistingShortCodes(codes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing codes: %w", err)
+		}
+		taken := make(map[string]bool, len(existing))
+		for _, code := range existing {
+			taken[code] = true
+		}
+
+		kept := urls[:0]
+		for i, urlEntity := range urls {
+			if taken[urlEntity.ShortCode] {
+				reject(candidates[i], entities.ImportRowConflict, "code already exists")
+				continue
+			}
+			kept = append(kept, urlEntity)
+		}
+		urls = kept
+	}
+	report.Valid = len(urls)
+	sort.Slice(report.Issues, func(i, j int) bool { return report.Issues[i].Row < report.Issues[j].Row })
+
+	if mode == entities.ImportModeApply {
+		// Không phát event url.created: đây là link đã tồn tại ở hệ thống cũ
+		if err := u.repo(ctx).CreateBatch(urls); err != nil {
+			return nil, fmt.Errorf("failed to import URLs: %w", err)
+		}
+		report.Created = len(urls)
+	}
+
+	return report, nil
+}
+
+// parseImportRow validate một dòng và chuyển thành URL entity.
+// Trả về nil kèm trạng thái và lý do khi dòng bị bỏ qua.
+func (u *urlUsecase) parseImportRow(row importRow, now time.Time) (*entities.URL, string, string) {
+	code := normalizeImportCode(row.code)
+	if code == "" {
+		return nil, entities.ImportRowInvalid, "code is required"
+	}
+	if !importCodePattern.MatchString(code) {
+		return nil, entities.ImportRowInvalid, "code may only contain letters, digits, '-' and '_' (max 64)"
+	}
+	// Code tự sinh là ID tăng dần nên code toàn số sẽ đụng với link tạo sau này
+	if strings.IndexFunc(code, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+		return nil, entities.ImportRowConflict, "numeric codes are reserved for generated links"
+	}
+	if reservedShortCodes[strings.ToLower(code)] {
+		return nil, entities.ImportRowConflict, "code is reserved"
+	}
+
+	destination := strings.TrimSpace(row.destination)
+	if err := u.validateURL(destination); err != nil {
+		return nil, entities.ImportRowInvalid, err.Error()
+	}
+
+	createdAt := now
+	if created := strings.TrimSpace(row.created); created != "" {
+		parsed, err := parseImportTime(created)
+		if err != nil {
+			return nil, entities.ImportRowInvalid, "invalid created date"
+		}
+		createdAt = parsed
+	}
+
+	var clicks int64
+	if value := strings.ReplaceAll(strings.TrimSpace(row.clicks), ",", ""); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, entities.ImportRowInvalid, "invalid click count"
+		}
+		clicks = parsed
+	}
+
+	return &entities.URL{
+		ShortCode:   code,
+		OriginalURL: destination,
+		IsActive:    true,
+		ClickCount:  clicks,
+		CreatedAt:   createdAt,
+		UpdatedAt:   now,
+	}, "", ""
+}
+
+// readImportCSV đọc file CSV có header; cột được nhận diện theo tên
+func (u *urlUsecase) readImportCSV(r io.Reader) ([]importRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, ErrInvalidImport.WithMessage("cannot read CSV header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		key := normalizeImportColumn(name)
+		if _, ok := columns[key]; !ok {
+			columns[key] = i
+		}
+	}
+	index := func(aliases []string) int {
+		for _, alias := range aliases {
+			if i, ok := columns[alias]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	codeCol, destCol := index(importCodeColumns), index(importDestinationColumns)
+	createdCol, clicksCol := index(importCreatedColumns), index(importClicksColumns)
+	if codeCol < 0 || destCol < 0 {
+		return nil, ErrInvalidImport.WithMessage("CSV header must have code and destination columns")
+	}
+	field := func(record []string, i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	maxRows := u.importMaxRows()
+	var rows []importRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidImport.WithMessage("%v", err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, ErrInvalidImport.WithMessage("file has more than %d rows", maxRows)
+		}
+		rows = append(rows, importRow{
+			row:         len(rows) + 1,
+			code:        field(record, codeCol),
+			destination: field(record, destCol),
+			created:     field(record, createdCol),
+			clicks:      field(record, clicksCol),
+		})
+	}
+	return rows, nil
+}
+
+// readImportJSON đọc mảng object JSON, hoặc object bọc mảng trong links/urls/data/items
+func (u *urlUsecase) readImportJSON(r io.Reader) ([]importRow, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, ErrInvalidImport.WithMessage("%v", err)
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		var wrapper map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &wrapper); err != nil {
+			return nil, ErrInvalidImport.WithMessage("JSON must be an array of objects")
+		}
+		found := false
+		for _, key := range []string{"links", "urls", "data", "items"} {
+			if inner, ok := wrapper[key]; ok && json.Unmarshal(inner, &items) == nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrInvalidImport.WithMessage("JSON must be an array of objects")
+		}
+	}
+
+	maxRows := u.importMaxRows()
+	if len(items) > maxRows {
+		return nil, ErrInvalidImport.WithMessage("file has more than %d rows", maxRows)
+	}
+	rows := make([]importRow, len(items))
+	for i, item := range items {
+		fields := make(map[string]string, len(item))
+		for key, value := range item {
+			key = normalizeImportColumn(key)
+			if _, ok := fields[key]; !ok && value != nil {
+				fields[key] = fmt.Sprint(value)
+			}
+		}
+		value := func(aliases []string) string {
+			for _, alias := range aliases {
+				if v, ok := fields[alias]; ok {
+					return v
+				}
+			}
+			return ""
+		}
+		rows[i] = importRow{
+			row:         i + 1,
+			code:        value(importCodeColumns),
+			destination: value(importDestinationColumns),
+			created:     value(importCreatedColumns),
+			clicks:      value(importClicksColumns),
+		}
+	}
+	return rows, nil
+}
+
+// importMaxRows trả về số dòng tối đa của một file import theo config
+func (u *urlUsecase) importMaxRows() int {
+	if u.config == nil || u.config.Links.ImportMaxRows <= 0 {
+		return defaultImportMaxRows
+	}
+	return u.config.Links.ImportMaxRows
+}
+
+// sniffImportFormat đoán định dạng theo ký tự đầu tiên của file
+func sniffImportFormat(br *bufio.Reader) string {
+	head, _ := br.Peek(512)
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(head) > 0 && (head[0] == '[' || head[0] == '{') {
+		return entities.ImportFormatJSON
+	}
+	return entities.ImportFormatCSV
+}
+
+// normalizeImportColumn chuẩn hóa tên cột: bỏ BOM, chữ thường, chỉ giữ chữ và số
+func normalizeImportColumn(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimPrefix(name, "\ufeff")) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// normalizeImportCode lấy short code từ giá trị cột code; file export thường ghi
+// cả short URL (ví dụ bit.ly/abc hoặc https://sho.rt/abc) nên chỉ giữ phần path cuối
+func normalizeImportCode(value string) string {
+	value = strings.TrimSpace(value)
+	if i := strings.IndexAny(value, "?#"); i >= 0 {
+		value = value[:i]
+	}
+	value = strings.TrimRight(value, "/")
+	if i := strings.LastIndex(value, "/"); i >= 0 {
+		value = value[i+1:]
+	}
+	return value
+}
+
+// parseImportTime đọc ngày tạo dạng RFC3339, ngày giờ không timezone (UTC) hoặc unix timestamp
+func parseImportTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Timestamp tính bằng mili giây
+		if seconds > 1e11 {
+			return time.UnixMilli(seconds).UTC(), nil
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	for _, layout := range importDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("unsupported date format")
+}
@@ -0,0 +1,281 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// ErrInvalidURLQuery trả về khi query params của danh sách URL không hợp lệ
+var ErrInvalidURLQuery = newDomainError(KindValidation, "invalid_query", "invalid url list query")
+
+// Giới hạn số URL trả về mỗi trang
+const (
+	defaultURLPageSize = 50
+	maxURLPageSize     = 200
+)
+
+// urlCursor là nội dung của cursor opaque; sort và order được lưu kèm để
+// phát hiện cursor dùng với kiểu sắp xếp khác
+type urlCursor struct {
+	Sort       string    `json:"s"`
+	Order      string    `json:"o"`
+	CreatedAt  time.Time `json:"c,omitempty"`
+	ClickCount int64     `json:"n,omitempty"`
+	ID         uint      `json:"id"`
+}
+
+// ListURLs lấy danh sách URL theo trang với bộ lọc, tìm kiếm và sắp xếp
+func (u *urlUsecase) ListURLs(ctx context.Context, req entities.URLListRequest) (*entities.URLListResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.ListURLs")
+	defer span.End()
+
+	filter, err := parseURLFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := u.listScope(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	filter.OwnerID, filter.WorkspaceID = scope.OwnerID, scope.WorkspaceID
+	limit := filter.Limit
+	// Lấy dư một record để biết còn trang sau không
+	filter.Limit = limit + 1
+
+	urls, err := u.repo(ctx).ListURLs(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list URLs: %w", err)
+	}
+
+	response := &entities.URLListResponse{
+		URLs: make([]entities.URLListItem, 0, len(urls)),
+	}
+	if len(urls) > limit {
+		urls = urls[:limit]
+		response.HasMore = true
+		response.NextCursor = encodeURLCursor(filter, &urls[limit-1])
+	}
+	for i := range urls {
+		response.URLs = append(response.URLs, newURLListItem(u.baseURL, &urls[i]))
+	}
+
+	return response, nil
+}
+
+// newURLListItem chuyển URL (đã preload Tags) thành item của danh sách
+func newURLListItem(baseURL string, urlEntity *entities.URL) entities.URLListItem {
+	return entities.URLListItem{
+		ID:           urlEntity.ID,
+		ShortCode:    urlEntity.ShortCode,
+		ShortURL:     fmt.Sprintf("%s/%s", baseURL, urlEntity.ShortCode),
+		OriginalURL:  urlEntity.OriginalURL,
+		IsActive:     urlEntity.IsActive,
+		ClickCount:   urlEntity.ClickCount,
+		FolderID:     urlEntity.FolderID,
+		OwnerID:      urlEntity.OwnerID,
+		WorkspaceID:  urlEntity.WorkspaceID,
+		RedirectType: urlEntity.RedirectStatus(),
+		Tags:         tagNames(urlEntity.Tags),
+		Metadata:     urlEntity.Metadata,
+		Preview:      urlEntity.Preview,
+		CreatedAt:    urlEntity.CreatedAt,
+		UpdatedAt:    urlEntity.UpdatedAt,
+	}
+}
+
+// parseURLFilter validate query params và chuyển thành URLFilter
+func parseURLFilter(req entities.URLListRequest) (entities.URLFilter, error) {
+	filter := entities.URLFilter{
+		Active: req.Active,
+		Search: strings.TrimSpace(req.Q),
+		SortBy: req.Sort,
+		Limit:  req.Limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultURLPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > maxURLPageSize {
+		return filter, ErrInvalidURLQuery.WithMessage("limit must be between 1 and %d", maxURLPageSize)
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = entities.URLSortCreated
+	}
+	if filter.SortBy != entities.URLSortCreated && filter.SortBy != entities.URLSortClicks {
+		return filter, ErrInvalidURLQuery.WithMessage("sort must be created or clicks")
+	}
+	switch req.Order {
+	case "", "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return filter, ErrInvalidURLQuery.WithMessage("order must be asc or desc")
+	}
+
+	var err error
+	if req.CreatedFrom != "" {
+		if filter.CreatedFrom, err = parseStatsTime(req.CreatedFrom, time.UTC); err != nil {
+			return filter, ErrInvalidURLQuery.WithMessage("invalid created_from")
+		}
+	}
+	if req.CreatedTo != "" {
+		if filter.CreatedTo, err = parseStatsTime(req.CreatedTo, time.UTC); err != nil {
+			return filter, ErrInvalidURLQuery.WithMessage("invalid created_to")
+		}
+	}
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return filter, ErrInvalidURLQuery.WithMessage("created_from must be before created_to")
+	}
+
+	if req.Host != "" {
+		if filter.Host = destinationHost(req.Host); filter.Host == "" {
+			return filter, ErrInvalidURLQuery.WithMessage("invalid host")
+		}
+	}
+
+	if len(req.Tags) > 0 {
+		if filter.Tags, err = normalizeTagNames(req.Tags); err != nil {
+			return filter, ErrInvalidURLQuery.WithMessage("%v", err)
+		}
+	}
+	filter.FolderID = req.FolderID
+
+	if req.Cursor != "" {
+		if filter.After, err = decodeURLCursor(req.Cursor, filter); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// listScope chuyển query owner và workspace_id thành tập URL được liệt kê. Không có workspace_id
+// (hoặc 0) chỉ lấy link cá nhân theo listOwner, trừ owner=all của admin lấy mọi link. Có workspace_id
+// cần role viewer và mặc định lấy link của mọi member; owner=me hoặc ID user lọc theo người tạo.
+func (u *urlUsecase) listScope(ctx context.Context, req entities.URLListRequest) (entities.URLScope, error) {
+	if req.WorkspaceID == nil || *req.WorkspaceID == 0 {
+		ownerID, err := listOwner(ctx, req.Owner)
+		if err != nil {
+			return entities.URLScope{}, err
+		}
+		scope := entities.URLScope{OwnerID: ownerID}
+		if ownerID != nil || req.WorkspaceID != nil {
+			personal := uint(0)
+			scope.WorkspaceID = &personal
+		}
+		return scope, nil
+	}
+
+	workspaceID := *req.WorkspaceID
+	if err := requireWorkspaceRole(ctx, u.workspaceRepo, workspaceID, entities.WorkspaceRoleViewer, ErrWorkspaceForbidden); err != nil {
+		return entities.URLScope{}, err
+	}
+	scope := entities.URLScope{WorkspaceID: &workspaceID}
+	switch req.Owner {
+	case "", "all":
+	case "me":
+		scope.OwnerID = callerUserID(ctx)
+	default:
+		id, err := strconv.ParseUint(req.Owner, 10, 64)
+		if err != nil || id == 0 {
+			return scope, ErrInvalidURLQuery.WithMessage("owner must be me, all or a user id")
+		}
+		ownerID := uint(id)
+		scope.OwnerID = &ownerID
+	}
+	return scope, nil
+}
+
+// listOwner chuyển query owner thành owner cần lọc. Mặc định (rỗng hoặc "me") chỉ lấy link
+// của người gọi; "all" hoặc ID của user khác cần scope admin. Admin không gắn user (ADMIN_TOKEN)
+// mặc định thấy mọi link.
+func listOwner(ctx context.Context, owner string) (*uint, error) {
+	userID := callerUserID(ctx)
+	admin := isAdminCaller(ctx)
+
+	switch owner {
+	case "", "me":
+		if userID == nil {
+			return visibleOwner(ctx), nil
+		}
+		return userID, nil
+	case "all":
+		if !admin {
+			return nil, ErrInsufficientScope.WithMessage("listing links of all users requires admin scope")
+		}
+		return nil, nil
+	}
+
+	id, err := strconv.ParseUint(owner, 10, 64)
+	if err != nil || id == 0 {
+		return nil, ErrInvalidURLQuery.WithMessage("owner must be me, all or a user id")
+	}
+	ownerID := uint(id)
+	if !admin && (userID == nil || *userID != ownerID) {
+		return nil, ErrInsufficientScope.WithMessage("listing links of another user requires admin scope")
+	}
+	return &ownerID, nil
+}
+
+// destinationHost lấy host (viết thường, không port) từ host hoặc URL đích
+func destinationHost(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// encodeURLCursor mã hóa vị trí của URL cuối trang thành cursor opaque
+func encodeURLCursor(filter entities.URLFilter, last *entities.URL) string {
+	cursor := urlCursor{Sort: filter.SortBy, Order: "desc", ID: last.ID}
+	if filter.Ascending {
+		cursor.Order = "asc"
+	}
+	if filter.SortBy == entities.URLSortClicks {
+		cursor.ClickCount = last.ClickCount
+	} else {
+		cursor.CreatedAt = last.CreatedAt.UTC()
+	}
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeURLCursor giải mã cursor và kiểm tra cursor khớp với kiểu sắp xếp hiện tại
+func decodeURLCursor(value string, filter entities.URLFilter) (*entities.URLCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidURLQuery.WithMessage("invalid cursor")
+	}
+	var cursor urlCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidURLQuery.WithMessage("invalid cursor")
+	}
+
+	order := "desc"
+	if filter.Ascending {
+		order = "asc"
+	}
+	if cursor.Sort != filter.SortBy || cursor.Order != order {
+		return nil, ErrInvalidURLQuery.WithMessage("cursor does not match sort and order")
+	}
+
+	return &entities.URLCursor{
+		CreatedAt:  cursor.CreatedAt,
+		ClickCount: cursor.ClickCount,
+		ID:         cursor.ID,
+	}, nil
+}
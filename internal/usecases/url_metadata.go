@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+)
+
+const (
+	// metadataBatchSize là số URL tối đa xử lý trong một lần poll
+	metadataBatchSize = 20
+	// metadataWorkers là số trang đích được fetch song song
+	metadataWorkers = 4
+)
+
+// Độ dài tối đa của các field metadata, khớp với size của cột
+const (
+	maxMetaTitleLength       = 300
+	maxMetaDescriptionLength = 1000
+	maxMetaURLLength         = 2048
+	maxMetaSiteNameLength    = 200
+	maxMetaErrorLength       = 500
+)
+
+// IMetadataUsecase lấy title, favicon, Open Graph của trang đích cho các link đang chờ
+type IMetadataUsecase interface {
+	FetchPending(ctx context.Context) (int, error)
+	Start(ctx context.Context)
+}
+
+type metadataUsecase struct {
+	urlRepo repositories.IURLRepository
+	fetcher *utils.MetadataFetcher
+	config  *config.Config
+}
+
+// NewMetadataUsecase tạo metadata usecase. Link được CreateShortURL đánh dấu pending,
+// worker nền lấy metadata rồi ghi lại vào link nên việc tạo link không phải chờ trang đích.
+func NewMetadataUsecase(urlRepo repositories.IURLRepository, cfg *config.Config) IMetadataUsecase {
+	return &metadataUsecase{
+		urlRepo: urlRepo,
+		fetcher: utils.NewMetadataFetcher(cfg.Metadata.Timeout, cfg.Metadata.MaxBytes, cfg.Metadata.AllowPrivateHosts),
+		config:  cfg,
+	}
+}
+
+// Start chạy worker fetch metadata theo chu kỳ cho tới khi ctx bị hủy
+func (u *metadataUsecase) Start(ctx context.Context) {
+	ticker := time.NewTicker(u.config.Metadata.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := u.FetchPending(ctx); err != nil {
+			fmt.Printf("Metadata fetch failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FetchPending fetch metadata của các link pending, trả về số link đã xử lý.
+// Trang đích lỗi được ghi status failed, không retry. Nhiều instance có thể cùng fetch
+// một link nhưng kết quả như nhau nên không cần lease như webhook delivery.
+func (u *metadataUsecase) FetchPending(ctx context.Context) (int, error) {
+	ctx, span := utils.StartSpan(ctx, "metadataUsecase.FetchPending")
+	defer span.End()
+
+	urls, err := u.urlRepo.WithContext(ctx).ListPendingMetadata(metadataBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending metadata: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	slots := make(chan struct{}, metadataWorkers)
+	for i := range urls {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(urlEntity *entities.URL) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			metadata := u.fetch(ctx, urlEntity.OriginalURL)
+			if err := u.urlRepo.WithContext(ctx).UpdateMetadata(urlEntity.ID, metadata); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to update metadata of URL %d: %w", urlEntity.ID, err)
+				}
+				mu.Unlock()
+			}
+		}(&urls[i])
+	}
+	wg.Wait()
+
+	return len(urls), firstErr
+}
+
+// fetch lấy metadata của một trang đích, lỗi được ghi vào metadata thay vì trả về
+func (u *metadataUsecase) fetch(ctx context.Context, originalURL string) entities.URLMetadata {
+	page, err := u.fetcher.Fetch(ctx, originalURL)
+	fetchedAt := time.Now().UTC()
+	if err != nil {
+		return entities.URLMetadata{
+			Status:    entities.MetadataStatusFailed,
+			Error:     truncateRunes(err.Error(), maxMetaErrorLength),
+			FetchedAt: &fetchedAt,
+		}
+	}
+
+	return entities.URLMetadata{
+		Status:      entities.MetadataStatusFetched,
+		Title:       truncateRunes(page.Title, maxMetaTitleLength),
+		Description: truncateRunes(page.Description, maxMetaDescriptionLength),
+		ImageURL:    limitURL(page.ImageURL),
+		SiteName:    truncateRunes(page.SiteName, maxMetaSiteNameLength),
+		FaviconURL:  limitURL(page.FaviconURL),
+		FetchedAt:   &fetchedAt,
+	}
+}
+
+// initialMetadata là metadata của link mới tạo: pending để worker fetch nếu tính năng được bật
+func (u *urlUsecase) initialMetadata() entities.URLMetadata {
+	if !u.config.Metadata.Enabled {
+		return entities.URLMetadata{}
+	}
+	return entities.URLMetadata{Status: entities.MetadataStatusPending}
+}
+
+// limitURL bỏ URL dài hơn cột thay vì cắt, vì URL bị cắt không dùng được
+func limitURL(rawURL string) string {
+	if len(rawURL) > maxMetaURLLength {
+		return ""
+	}
+	return rawURL
+}
+
+// truncateRunes cắt s còn tối đa n ký tự mà không làm vỡ ký tự UTF-8
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
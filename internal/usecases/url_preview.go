@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// ErrInvalidPreview trả về khi social preview tùy chỉnh không hợp lệ
+var ErrInvalidPreview = newDomainError(KindValidation, "invalid_preview", "invalid preview")
+
+// SocialPreview là trang HTML chứa thẻ Open Graph trả cho bot unfurl
+type SocialPreview struct {
+	HTML []byte
+}
+
+// SetPreview đặt title, description, ảnh của social preview; mọi field rỗng là xóa preview
+func (u *urlUsecase) SetPreview(ctx context.Context, shortCode string, req entities.URLPreviewRequest) (*entities.URLPreview, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.SetPreview")
+	defer span.End()
+
+	preview, err := parsePreview(req)
+	if err != nil {
+		return nil, err
+	}
+
+	urlEntity, err := u.findAuthorizedURL(ctx, shortCode, entities.WorkspaceRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo(ctx).UpdatePreview(urlEntity.ID, preview); err != nil {
+		return nil, notFoundError(err, ErrURLNotFound, "failed to update preview")
+	}
+	return &preview, nil
+}
+
+// GetSocialPreview render trang preview cho bot unfurl. Trả về nil khi link không có preview
+// tùy chỉnh để handler redirect như bình thường. Request của bot không được tính là click.
+func (u *urlUsecase) GetSocialPreview(ctx context.Context, shortCode string) (*SocialPreview, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.GetSocialPreview")
+	defer span.End()
+
+	urlEntity, err := u.findURL(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if !urlEntity.IsActive {
+		return nil, ErrURLInactive
+	}
+	// Trang đích không phải http(s) không được đặt vào meta refresh, handler redirect như bình thường
+	if urlEntity.Preview.IsEmpty() || !utils.IsHTTPURL(urlEntity.OriginalURL) {
+		return nil, nil
+	}
+
+	// Field không được đặt lấy từ metadata của trang đích
+	page := utils.PreviewPage{
+		Title:       firstNonEmpty(urlEntity.Preview.Title, urlEntity.Metadata.Title, urlEntity.OriginalURL),
+		Description: firstNonEmpty(urlEntity.Preview.Description, urlEntity.Metadata.Description),
+		ImageURL:    firstNonEmpty(urlEntity.Preview.ImageURL, urlEntity.Metadata.ImageURL),
+		URL:         fmt.Sprintf("%s/%s", u.baseURL, urlEntity.ShortCode),
+		RedirectURL: urlEntity.OriginalURL,
+	}
+	html, err := utils.RenderPreviewPage(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render preview: %w", err)
+	}
+	return &SocialPreview{HTML: html}, nil
+}
+
+// parsePreview chuẩn hóa và kiểm tra preview; giới hạn độ dài khớp với cột
+func parsePreview(req entities.URLPreviewRequest) (entities.URLPreview, error) {
+	preview := entities.URLPreview{
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		ImageURL:    strings.TrimSpace(req.ImageURL),
+	}
+	if utf8.RuneCountInString(preview.Title) > maxMetaTitleLength {
+		return preview, ErrInvalidPreview.WithMessage("title must be at most %d characters", maxMetaTitleLength)
+	}
+	if utf8.RuneCountInString(preview.Description) > maxMetaDescriptionLength {
+		return preview, ErrInvalidPreview.WithMessage("description must be at most %d characters", maxMetaDescriptionLength)
+	}
+	if preview.ImageURL != "" {
+		parsed, err := url.Parse(preview.ImageURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			len(preview.ImageURL) > maxMetaURLLength {
+			return preview, ErrInvalidPreview.WithMessage("image_url must be an absolute http(s) URL of at most %d characters", maxMetaURLLength)
+		}
+	}
+	return preview, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
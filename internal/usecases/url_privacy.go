@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// ErrInvalidEraseRequest trả về khi request xóa analytics không hợp lệ
+var ErrInvalidEraseRequest = newDomainError(KindValidation, "invalid_erase_request", "invalid erase request")
+
+// EraseAnalytics xóa mọi click thô gắn với một IP hoặc fingerprint.
+// IP được so khớp với IP gốc và hash theo từng chu kỳ salt; click chỉ lưu prefix đã cắt
+// dùng chung với visitor khác nên chỉ bị xóa khi khớp fingerprint (cần user_agent).
+func (u *urlUsecase) EraseAnalytics(ctx context.Context, req entities.EraseAnalyticsRequest) (*entities.EraseAnalyticsResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.EraseAnalytics")
+	defer span.End()
+
+	ipAddress := strings.TrimSpace(req.IPAddress)
+	fingerprint := strings.ToLower(strings.TrimSpace(req.Fingerprint))
+
+	if ipAddress == "" && fingerprint == "" {
+		return nil, ErrInvalidEraseRequest.WithMessage("ip_address or fingerprint is required")
+	}
+	if ipAddress != "" && net.ParseIP(ipAddress) == nil {
+		return nil, ErrInvalidEraseRequest.WithMessage("invalid ip_address %q", ipAddress)
+	}
+	if _, err := hex.DecodeString(fingerprint); err != nil || (fingerprint != "" && len(fingerprint) != 64) {
+		return nil, ErrInvalidEraseRequest.WithMessage("fingerprint must be a SHA-256 hex digest")
+	}
+
+	var ipForms, fingerprints []string
+	if fingerprint != "" {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	if ipAddress != "" {
+		now := time.Now()
+		from := now
+		oldest, err := u.repo(ctx).GetOldestClickedAt()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get oldest click: %w", err)
+		}
+		if oldest != nil {
+			from = *oldest
+		}
+		ipForms = u.anonymizer().StoredForms(ipAddress, from, now)
+		if req.UserAgent != "" {
+			fingerprints = append(fingerprints, u.anonymizer().FingerprintForms(ipAddress, req.UserAgent, from, now)...)
+		}
+	}
+
+	deleted, err := u.repo(ctx).DeleteAnalytics(ipForms, fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase analytics: %w", err)
+	}
+
+	return &entities.EraseAnalyticsResponse{DeletedClicks: deleted}, nil
+}
+
+// newIPAnonymizer tạo anonymizer theo privacy config.
+// Chế độ hash không có secret sẽ fallback về truncate để không lưu IP gốc.
+func newIPAnonymizer(cfg *config.Config) *utils.IPAnonymizer {
+	if cfg == nil {
+		return utils.NewIPAnonymizer(utils.IPModeFull, "", 0)
+	}
+
+	mode := cfg.Privacy.IPMode
+	if mode == utils.IPModeHash && cfg.Privacy.IPHashSecret == "" {
+		fmt.Printf("PRIVACY_IP_HASH_SECRET is empty, falling back to IP truncation\n")
+		mode = utils.IPModeTruncate
+	}
+	return utils.NewIPAnonymizer(mode, cfg.Privacy.IPHashSecret, cfg.Privacy.SaltRotation)
+}
+
+// anonymizer trả về IP anonymizer của usecase, tạo theo config nếu usecase không qua constructor
+func (u *urlUsecase) anonymizer() *utils.IPAnonymizer {
+	if u.ipAnon == nil {
+		return newIPAnonymizer(u.config)
+	}
+	return u.ipAnon
+}
+
+// anonymizeIP chuyển IP của click về dạng được phép lưu
+func (u *urlUsecase) anonymizeIP(ipAddress string, at time.Time) string {
+	return u.anonymizer().Anonymize(ipAddress, at)
+}
+
+// honorDoNotTrack cho biết có tôn trọng header DNT / Sec-GPC không
+func (u *urlUsecase) honorDoNotTrack() bool {
+	return u.config != nil && u.config.Privacy.HonorDNT
+}
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// ErrInvalidQRQuery trả về khi query params của QR code không hợp lệ
+var ErrInvalidQRQuery = newDomainError(KindValidation, "invalid_qr_query", "invalid QR code query")
+
+// Giới hạn kích thước ảnh QR code (pixel)
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 2048
+	// defaultQRCacheMaxAge là max-age khi usecase không có config
+	defaultQRCacheMaxAge = 86400
+)
+
+// QRCode là ảnh QR code đã render
+type QRCode struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+	// ETag là hash nội dung ảnh, dùng cho If-None-Match
+	ETag string
+	// MaxAge là thời gian (giây) CDN/browser được cache ảnh
+	MaxAge int
+}
+
+// GetQRCode render QR code chứa short URL của shortCode
+func (u *urlUsecase) GetQRCode(ctx context.Context, shortCode string, req entities.QRCodeRequest) (*QRCode, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.GetQRCode")
+	defer span.End()
+
+	format, opts, err := u.parseQROptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	urlEntity, err := u.findURL(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	content := fmt.Sprintf("%s/%s", strings.TrimRight(u.qrBaseURL(), "/"), urlEntity.ShortCode)
+	qr := &QRCode{
+		Filename: fmt.Sprintf("qr-%s.%s", urlEntity.ShortCode, format),
+		MaxAge:   defaultQRCacheMaxAge,
+	}
+	if u.config != nil && u.config.QR.CacheMaxAge > 0 {
+		qr.MaxAge = u.config.QR.CacheMaxAge
+	}
+	if format == entities.QRFormatSVG {
+		qr.ContentType = "image/svg+xml"
+		qr.Data, err = utils.RenderQRSVG(content, opts)
+	} else {
+		qr.ContentType = "image/png"
+		qr.Data, err = utils.RenderQRPNG(content, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+
+	sum := sha256.Sum256(qr.Data)
+	qr.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	return qr, nil
+}
+
+// parseQROptions validate query params và chuyển thành tùy chọn render
+func (u *urlUsecase) parseQROptions(req entities.QRCodeRequest) (string, utils.QROptions, error) {
+	opts := utils.QROptions{
+		Size:       req.Size,
+		Level:      strings.ToUpper(req.ECC),
+		Foreground: color.RGBA{A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+
+	format := req.Format
+	if format == "" {
+		format = entities.QRFormatPNG
+	}
+	if format != entities.QRFormatPNG && format != entities.QRFormatSVG {
+		return "", opts, ErrInvalidQRQuery.WithMessage("format must be png or svg")
+	}
+
+	if opts.Size == 0 {
+		opts.Size = defaultQRSize
+	}
+	if opts.Size < minQRSize || opts.Size > maxQRSize {
+		return "", opts, ErrInvalidQRQuery.WithMessage("size must be between %d and %d", minQRSize, maxQRSize)
+	}
+
+	var err error
+	if req.FG != "" {
+		if opts.Foreground, err = utils.ParseHexColor(req.FG); err != nil {
+			return "", opts, ErrInvalidQRQuery.WithMessage("%v", err)
+		}
+	}
+	if req.BG != "" {
+		if opts.Background, err = utils.ParseHexColor(req.BG); err != nil {
+			return "", opts, ErrInvalidQRQuery.WithMessage("%v", err)
+		}
+	}
+	if opts.Foreground == opts.Background {
+		return "", opts, ErrInvalidQRQuery.WithMessage("fg and bg must differ")
+	}
+
+	if req.Logo {
+		if u.qrLogo == nil {
+			return "", opts, ErrInvalidQRQuery.WithMessage("logo is not configured")
+		}
+		opts.Logo = u.qrLogo
+		// Logo che mất một phần module nên cần mức sửa lỗi cao
+		if opts.Level == "" {
+			opts.Level = utils.QRLevelHigh
+		}
+		if opts.Level != utils.QRLevelQuarter && opts.Level != utils.QRLevelHigh {
+			return "", opts, ErrInvalidQRQuery.WithMessage("logo requires ecc Q or H")
+		}
+	}
+	if opts.Level == "" {
+		opts.Level = utils.QRLevelMedium
+	}
+	if !utils.ValidQRLevel(opts.Level) {
+		return "", opts, ErrInvalidQRQuery.WithMessage("ecc must be L, M, Q or H")
+	}
+
+	return format, opts, nil
+}
+
+// qrBaseURL là base URL của short link trong QR code, lấy từ config.Server.BaseURL
+func (u *urlUsecase) qrBaseURL() string {
+	if u.config != nil && u.config.Server.BaseURL != "" {
+		return u.config.Server.BaseURL
+	}
+	return u.baseURL
+}
+
+// loadQRLogo đọc logo QR code từ config; lỗi chỉ được log và tắt logo
+func loadQRLogo(cfg *config.Config) image.Image {
+	if cfg.QR.LogoPath == "" {
+		return nil
+	}
+	logo, err := utils.LoadQRLogo(cfg.QR.LogoPath)
+	if err != nil {
+		fmt.Printf("Failed to load QR logo: %v\n", err)
+		return nil
+	}
+	return logo
+}
@@ -0,0 +1,244 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/utils"
+)
+
+// ErrInvalidStatsQuery trả về khi query params của các endpoint thống kê không hợp lệ
+var ErrInvalidStatsQuery = newDomainError(KindValidation, "invalid_stats_query", "invalid stats query")
+
+// maxTimeSeriesBuckets giới hạn số bucket trả về để tránh zero-fill quá lớn
+const maxTimeSeriesBuckets = 2000
+
+// breakdownLimit là số giá trị tối đa trả về cho mỗi dimension breakdown
+const breakdownLimit = 20
+
+// defaultVisitorWindowDays là số ngày tính unique_visitors khi không có cấu hình
+const defaultVisitorWindowDays = 90
+
+// Giới hạn số referrer trả về
+const (
+	defaultReferrerLimit = 10
+	maxReferrerLimit     = 100
+)
+
+// defaultTimeSeriesRange là khoảng thời gian mặc định khi không truyền from
+var defaultTimeSeriesRange = map[string]time.Duration{
+	entities.IntervalHour: 24 * time.Hour,
+	entities.IntervalDay:  30 * 24 * time.Hour,
+	entities.IntervalWeek: 12 * 7 * 24 * time.Hour,
+}
+
+// bucketDuration là độ dài gần đúng của một bucket, dùng để ước lượng số bucket
+var bucketDuration = map[string]time.Duration{
+	entities.IntervalHour: time.Hour,
+	entities.IntervalDay:  24 * time.Hour,
+	entities.IntervalWeek: 7 * 24 * time.Hour,
+}
+
+// GetClickTimeSeries lấy số click theo bucket thời gian
+func (u *urlUsecase) GetClickTimeSeries(ctx context.Context, shortCode string, req entities.TimeSeriesRequest) (*entities.TimeSeriesResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.GetClickTimeSeries")
+	defer span.End()
+
+	interval := req.Interval
+	if interval == "" {
+		interval = entities.IntervalDay
+	}
+	if _, ok := bucketDuration[interval]; !ok {
+		return nil, ErrInvalidStatsQuery.WithMessage("interval must be one of hour, day, week")
+	}
+
+	tz := req.TZ
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrInvalidStatsQuery.WithMessage("unknown timezone %q", tz)
+	}
+
+	to := time.Now()
+	if req.To != "" {
+		if to, err = parseStatsTime(req.To, loc); err != nil {
+			return nil, err
+		}
+	}
+	from := to.Add(-defaultTimeSeriesRange[interval])
+	if req.From != "" {
+		if from, err = parseStatsTime(req.From, loc); err != nil {
+			return nil, err
+		}
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidStatsQuery.WithMessage("from must be before to")
+	}
+	if to.Sub(from)/bucketDuration[interval] > maxTimeSeriesBuckets {
+		return nil, ErrInvalidStatsQuery.WithMessage("range too large for interval %s (max %d buckets)", interval, maxTimeSeriesBuckets)
+	}
+
+	urlEntity, err := u.findAuthorizedURL(ctx, shortCode, entities.WorkspaceRoleAnalyst)
+	if err != nil {
+		return nil, err
+	}
+
+	rollupBefore, err := u.rollupBefore(ctx, entities.IntervalHour)
+	if err != nil {
+		return nil, err
+	}
+	if !hourAligned(from, to, bucketDuration[interval], loc) {
+		// Rollup theo giờ không chia được vào bucket lệch :30/:45, chỉ đọc click thô còn trong retention
+		rollupBefore = rawDataCutoff(u.config, time.Now())
+		if from.Before(rollupBefore) {
+			return nil, ErrInvalidStatsQuery.WithMessage("timezone %s is not hour-aligned, clicks before %s are only kept as hourly rollups",
+				tz, rollupBefore.In(loc).Format(time.RFC3339))
+		}
+	}
+	buckets, err := u.repo(ctx).GetClickTimeSeries(urlEntity.ID, from, to, interval, loc, rollupBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get click time series: %w", err)
+	}
+
+	var total int64
+	for _, b := range buckets {
+		total += b.Clicks
+	}
+
+	return &entities.TimeSeriesResponse{
+		ShortCode:   urlEntity.ShortCode,
+		Interval:    interval,
+		Timezone:    loc.String(),
+		From:        from.In(loc),
+		To:          to.In(loc),
+		TotalClicks: total,
+		Buckets:     buckets,
+	}, nil
+}
+
+// GetReferrerStats lấy top referrer host và breakdown theo channel
+func (u *urlUsecase) GetReferrerStats(ctx context.Context, shortCode string, req entities.ReferrerStatsRequest) (*entities.ReferrerStatsResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.GetReferrerStats")
+	defer span.End()
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultReferrerLimit
+	}
+	if limit < 0 || limit > maxReferrerLimit {
+		return nil, ErrInvalidStatsQuery.WithMessage("limit must be between 1 and %d", maxReferrerLimit)
+	}
+
+	urlEntity, err := u.findAuthorizedURL(ctx, shortCode, entities.WorkspaceRoleAnalyst)
+	if err != nil {
+		return nil, err
+	}
+
+	rollupBefore, err := u.rollupBefore(ctx, entities.IntervalDay)
+	if err != nil {
+		return nil, err
+	}
+	referrers, err := u.repo(ctx).GetTopReferrers(urlEntity.ID, rollupBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top referrers: %w", err)
+	}
+
+	return &entities.ReferrerStatsResponse{
+		ShortCode:   urlEntity.ShortCode,
+		TotalClicks: urlEntity.ClickCount,
+		Channels:    u.getClickBreakdown(ctx, urlEntity.ID, entities.DimensionReferrerChannel),
+		Referrers:   referrers,
+	}, nil
+}
+
+// parseStatsTime parse thời gian dạng RFC3339 hoặc YYYY-MM-DD (theo múi giờ loc)
+func parseStatsTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, ErrInvalidStatsQuery.WithMessage("invalid time %q, expected RFC3339 or YYYY-MM-DD", value)
+}
+
+// getClickBreakdown lấy breakdown theo dimension, trả về rỗng nếu lỗi
+func (u *urlUsecase) getClickBreakdown(ctx context.Context, urlID uint, dimension string) []entities.DimensionCount {
+	rollupBefore, err := u.rollupBefore(ctx, entities.IntervalDay)
+	if err != nil {
+		fmt.Printf("Failed to get %s breakdown: %v\n", dimension, err)
+		return []entities.DimensionCount{}
+	}
+	counts, err := u.repo(ctx).GetClickBreakdown(urlID, dimension, rollupBefore, breakdownLimit)
+	if err != nil {
+		fmt.Printf("Failed to get %s breakdown: %v\n", dimension, err)
+		return []entities.DimensionCount{}
+	}
+	return counts
+}
+
+// hourAligned kiểm tra offset của loc là bội số của một giờ tại mọi mốc bucket trong [from, to].
+// Rollup theo giờ không tách được nên không chia đúng vào bucket lệch :30 hoặc :45 (Asia/Kolkata, Australia/Adelaide).
+func hourAligned(from, to time.Time, step time.Duration, loc *time.Location) bool {
+	for t := from; ; t = t.Add(step) {
+		if t.After(to) {
+			t = to
+		}
+		if _, offset := t.In(loc).Zone(); offset%3600 != 0 {
+			return false
+		}
+		if !t.Before(to) {
+			return true
+		}
+	}
+}
+
+// rollupBefore là mốc mà thống kê trước đó được đọc từ bảng rollup granularity thay vì click thô:
+// watermark đã lưu của rollup, time zero nếu chưa rollup lần nào. Click thô chỉ bị xóa khi đã nằm
+// trong rollup nên từ watermark trở đi click thô luôn đầy đủ, kể cả khi rollup job chạy trễ.
+func (u *urlUsecase) rollupBefore(ctx context.Context, granularity string) (time.Time, error) {
+	watermark, err := u.repo(ctx).GetRollupWatermark(granularity)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get %s rollup watermark: %w", granularity, err)
+	}
+	if watermark == nil {
+		return time.Time{}, nil
+	}
+	return *watermark, nil
+}
+
+// countUniqueVisitors cộng visitor duy nhất của từng ngày UTC trong visitorWindow ngày gần nhất,
+// đếm bằng Redis HyperLogLog theo ngày. Fallback COUNT(DISTINCT visitor_hash) trong database cho cùng
+// số liệu vì visitor_hash đổi salt mỗi ngày UTC, nên một visitor là một hash khác nhau mỗi ngày.
+func (u *urlUsecase) countUniqueVisitors(ctx context.Context, urlID uint) int64 {
+	now := time.Now()
+	from := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-visitorWindowDays(u.config))
+	if u.visitors != nil {
+		count, err := u.visitors.CountDays(ctx, urlID, from, now)
+		if err == nil {
+			return count
+		}
+		fmt.Printf("Failed to count unique visitors from Redis, falling back to database: %v\n", err)
+	}
+
+	count, err := u.repo(ctx).CountUniqueVisitors(urlID, from, now)
+	if err != nil {
+		fmt.Printf("Failed to count unique visitors: %v\n", err)
+		return 0
+	}
+	return count
+}
+
+// visitorWindowDays là số ngày (tính cả hôm nay) của unique_visitors, cũng là số ngày giữ HLL theo ngày.
+// Giá trị dưới một ngày dùng mặc định để key không bị hết hạn ngay khi PFADD.
+func visitorWindowDays(cfg *config.Config) int {
+	if cfg == nil || cfg.Analytics.VisitorDayTTL < 24*time.Hour {
+		return defaultVisitorWindowDays
+	}
+	return int(cfg.Analytics.VisitorDayTTL / (24 * time.Hour))
+}
@@ -5,8 +5,11 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"image"
+	"io"
 	"math/big"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,28 +22,53 @@ import (
 )
 
 type IURLUsecase interface {
-	CreateShortURL(req entities.CreateURLRequest) (*entities.CreateURLResponse, error)
-	GetOriginalURL(shortCode string) (string, error)
-	Redirect(shortCode string, ipAddress, userAgent, referer string) (string, error)
-	GetURLStats(shortCode string) (*entities.URLStatsResponse, error)
-	DeleteURL(shortCode string) error
+	CreateShortURL(ctx context.Context, req entities.CreateURLRequest) (*entities.CreateURLResponse, error)
+	CreateShortURLs(ctx context.Context, req entities.BatchCreateURLRequest) (*entities.BatchCreateURLResponse, error)
+	ImportURLs(ctx context.Context, r io.Reader, req entities.ImportURLsRequest) (*entities.ImportReport, error)
+	GetOriginalURL(ctx context.Context, shortCode string) (string, error)
+	Redirect(ctx context.Context, shortCode string, ipAddress, userAgent, referer string, doNotTrack bool) (*entities.RedirectTarget, error)
+	GetURLStats(ctx context.Context, shortCode string) (*entities.URLStatsResponse, error)
+	GetClickTimeSeries(ctx context.Context, shortCode string, req entities.TimeSeriesRequest) (*entities.TimeSeriesResponse, error)
+	GetReferrerStats(ctx context.Context, shortCode string, req entities.ReferrerStatsRequest) (*entities.ReferrerStatsResponse, error)
+	ListClicks(ctx context.Context, shortCode string, req entities.ClickListRequest) (*entities.ClickListResponse, error)
+	ExportClicks(ctx context.Context, shortCode string, req entities.ClickExportRequest) (*ClickExport, error)
+	StreamClicks(ctx context.Context, shortCode string, lastEventID uint) (<-chan entities.ClickEvent, error)
+	ListURLs(ctx context.Context, req entities.URLListRequest) (*entities.URLListResponse, error)
+	GetQRCode(ctx context.Context, shortCode string, req entities.QRCodeRequest) (*QRCode, error)
+	SetPreview(ctx context.Context, shortCode string, req entities.URLPreviewRequest) (*entities.URLPreview, error)
+	GetSocialPreview(ctx context.Context, shortCode string) (*SocialPreview, error)
+	DeleteURL(ctx context.Context, shortCode string) error
+	EraseAnalytics(ctx context.Context, req entities.EraseAnalyticsRequest) (*entities.EraseAnalyticsResponse, error)
 }
 
+// createURLLockKey là distributed lock bảo vệ việc cấp short code tuần tự
+const createURLLockKey = "lock-create-shorted-link"
+
 type urlUsecase struct {
-	urlRepo repositories.IURLRepository
-	baseURL string
-	locker  utils.IDLock
-	config  *config.Config
+	urlRepo       repositories.IURLRepository
+	workspaceRepo repositories.IWorkspaceRepository
+	baseURL       string
+	locker        utils.IDLock
+	visitors      utils.IVisitorCounter
+	clicks        utils.IClickBroker
+	ipAnon        *utils.IPAnonymizer
+	events        IEventPublisher
+	qrLogo        image.Image
+	config        *config.Config
 }
 
-func NewURLUsecase(urlRepo repositories.IURLRepository, baseURL string, cfg *config.Config) IURLUsecase {
+// NewURLUsecase tạo URL usecase. events có thể nil nếu không cần phát event (webhook).
+func NewURLUsecase(urlRepo repositories.IURLRepository, workspaceRepo repositories.IWorkspaceRepository, baseURL string, cfg *config.Config, events IEventPublisher) IURLUsecase {
 	var locker utils.IDLock
+	var visitors utils.IVisitorCounter
+	var clicks utils.IClickBroker
 
-	// Sử dụng mock lock trong test environment
+	// Sử dụng mock lock và broker in-memory trong test environment, không đếm visitor qua Redis
 	if cfg.Server.GinMode == "test" {
 		locker = utils.NewMockLock()
+		clicks = utils.NewMemoryClickBroker()
 	} else {
-		locker = utils.NewRedisLock(utils.GetRedisWithConfig(
+		rclient := utils.GetRedisWithConfig(
 			cfg.Redis.URL,
 			cfg.Redis.PoolSize,
 			cfg.Redis.MinIdleConns,
@@ -49,59 +77,73 @@ func NewURLUsecase(urlRepo repositories.IURLRepository, baseURL string, cfg *con
 			cfg.Redis.ReadTimeout,
 			cfg.Redis.WriteTimeout,
 			cfg.Redis.PoolTimeout,
-		), &utils.Config{
+		)
+		locker = utils.NewRedisLock(rclient, &utils.Config{
 			MaxLockTime: cfg.Lock.MaxTime,
 			MaxTryTime:  cfg.Lock.MaxTryTime,
 		})
+		visitors = utils.NewRedisVisitorCounter(rclient, time.Duration(visitorWindowDays(cfg))*24*time.Hour)
+		clicks = utils.NewRedisClickBroker(rclient)
 	}
 
 	return &urlUsecase{
-		urlRepo: urlRepo,
-		baseURL: baseURL,
-		locker:  locker,
-		config:  cfg,
+		urlRepo:       urlRepo,
+		workspaceRepo: workspaceRepo,
+		baseURL:       baseURL,
+		locker:        locker,
+		visitors:      visitors,
+		clicks:        clicks,
+		ipAnon:        newIPAnonymizer(cfg),
+		events:        events,
+		qrLogo:        loadQRLogo(cfg),
+		config:        cfg,
 	}
 }
 
 // CreateShortURL tạo short URL
-func (u *urlUsecase) CreateShortURL(req entities.CreateURLRequest) (*entities.CreateURLResponse, error) {
+func (u *urlUsecase) CreateShortURL(ctx context.Context, req entities.CreateURLRequest) (*entities.CreateURLResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.CreateShortURL")
+	defer span.End()
+
 	// Validate URL
 	if err := u.validateURL(req.OriginalURL); err != nil {
 		return nil, err
 	}
-	var key = "lock-create-shorted-link"
+	urlEntity, err := u.newURL(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var key = createURLLockKey
 	//lock key
-	lrs, err := u.locker.Lock(context.TODO(), key)
+	lrs, err := u.locker.Lock(ctx, key)
 	if nil != err {
-		return nil, err
+		return nil, lockError(err)
 	}
 	// unlock key
 	defer func() {
-		if err := u.locker.Unlock(context.TODO(), lrs); err != nil {
+		// Unlock cả khi request đã bị hủy để không giữ lock tới hết MaxLockTime
+		if err := u.locker.Unlock(context.WithoutCancel(ctx), lrs); err != nil {
 			fmt.Printf("Failed to unlock: %v\n", err)
 		}
 	}()
 	//get lastID for create short link
-	id, err := u.urlRepo.GetLastID()
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return nil, nil
+	id, err := u.repo(ctx).GetLastID()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get last ID: %w", err)
 	}
 
 	//defer unlock key
 
 	next := id + 1
+	// Lưu UTC để so sánh/sắp xếp created_at dạng text trong SQLite luôn đúng
+	now := time.Now().UTC()
 	//Create URL entity
-	urlEntity := &entities.URL{
-		ShortCode:   fmt.Sprintf("%v", next),
-		OriginalURL: req.OriginalURL,
-		IsActive:    true,
-		ClickCount:  0,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
+	urlEntity.ShortCode = fmt.Sprintf("%v", next)
+	urlEntity.CreatedAt = now
+	urlEntity.UpdatedAt = now
 
 	// // Save to database
-	if err := u.urlRepo.Create(urlEntity); err != nil {
+	if err := u.repo(ctx).Create(urlEntity); err != nil {
 		return nil, fmt.Errorf("failed to create URL: %w", err)
 	}
 
@@ -109,109 +151,242 @@ func (u *urlUsecase) CreateShortURL(req entities.CreateURLRequest) (*entities.Cr
 	response := &entities.CreateURLResponse{
 		ShortCode:   fmt.Sprintf("%v", next),
 		ShortURL:    fmt.Sprintf("%s/%v", u.baseURL, next),
-		OriginalURL: req.OriginalURL,
+		OriginalURL: urlEntity.OriginalURL,
 		CreatedAt:   urlEntity.CreatedAt,
 	}
 
+	u.publish(entities.WebhookEventURLCreated, u.urlEventData(urlEntity, urlEntity.CreatedAt))
+
 	return response, nil
 }
 
+// newURL dựng URL mới (chưa có short code) từ request. Link tạo trong workspace cần role editor
+// và nhận redirect type, UTM mặc định của workspace; redirect_type trong request được ưu tiên.
+func (u *urlUsecase) newURL(ctx context.Context, req entities.CreateURLRequest) (*entities.URL, error) {
+	if req.RedirectType != 0 && !slices.Contains(entities.RedirectTypes, req.RedirectType) {
+		return nil, ErrInvalidRequest.WithMessage("redirect_type must be one of %v", entities.RedirectTypes)
+	}
+
+	urlEntity := &entities.URL{
+		OriginalURL:  req.OriginalURL,
+		IsActive:     true,
+		OwnerID:      callerUserID(ctx),
+		RedirectType: entities.DefaultRedirectType,
+		Metadata:     u.initialMetadata(),
+	}
+	if req.WorkspaceID != nil {
+		workspace, err := u.workspaceRepo.WithContext(ctx).GetWorkspace(*req.WorkspaceID)
+		if err != nil {
+			return nil, notFoundError(err, ErrWorkspaceNotFound.WithMessage("workspace %d not found", *req.WorkspaceID), "failed to get workspace")
+		}
+		if err := requireWorkspaceRole(ctx, u.workspaceRepo, workspace.ID, entities.WorkspaceRoleEditor, ErrWorkspaceForbidden); err != nil {
+			return nil, err
+		}
+		urlEntity.WorkspaceID = &workspace.ID
+		if workspace.Settings.DefaultRedirectType != 0 {
+			urlEntity.RedirectType = workspace.Settings.DefaultRedirectType
+		}
+		urlEntity.OriginalURL = utils.AddMissingQuery(urlEntity.OriginalURL, workspace.Settings.DefaultUTM.Query())
+	}
+	if req.RedirectType != 0 {
+		urlEntity.RedirectType = req.RedirectType
+	}
+	return urlEntity, nil
+}
+
 // GetOriginalURL lấy original URL từ short code
-func (u *urlUsecase) GetOriginalURL(shortCode string) (string, error) {
-	urlEntity, err := u.urlRepo.GetByShortCode(shortCode)
+func (u *urlUsecase) GetOriginalURL(ctx context.Context, shortCode string) (string, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.GetOriginalURL")
+	defer span.End()
+
+	urlEntity, err := u.findActiveURL(ctx, shortCode)
 	if err != nil {
-		return "", fmt.Errorf("URL not found: %w", err)
+		return "", err
+	}
+	return urlEntity.OriginalURL, nil
+}
+
+// findActiveURL lấy URL theo short code, trả về ErrURLInactive nếu URL đã bị vô hiệu hóa
+func (u *urlUsecase) findActiveURL(ctx context.Context, shortCode string) (*entities.URL, error) {
+	urlEntity, err := u.findURL(ctx, shortCode)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if URL is active
 	if !urlEntity.IsActive {
-		return "", errors.New("URL is inactive")
+		return nil, ErrURLInactive
 	}
 
-	return urlEntity.OriginalURL, nil
+	return urlEntity, nil
 }
 
-// Redirect thực hiện redirect và ghi analytics
-func (u *urlUsecase) Redirect(shortCode string, ipAddress, userAgent, referer string) (string, error) {
+// Redirect thực hiện redirect và ghi analytics.
+// Khi doNotTrack, click vẫn được đếm nhưng không lưu IP, User-Agent, URL referer và fingerprint.
+func (u *urlUsecase) Redirect(ctx context.Context, shortCode string, ipAddress, userAgent, referer string, doNotTrack bool) (*entities.RedirectTarget, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.Redirect")
+	defer span.End()
+
 	// Get original URL
-	originalURL, err := u.GetOriginalURL(shortCode)
+	activeURL, err := u.findActiveURL(ctx, shortCode)
 	if err != nil {
-		return "", err
+		utils.RedirectsTotal.WithLabelValues(utils.RedirectMiss).Inc()
+		return nil, err
 	}
+	utils.RedirectsTotal.WithLabelValues(utils.RedirectHit).Inc()
 
 	// Increment click count
-	if err := u.urlRepo.IncrementClickCount(shortCode); err != nil {
+	clickCount, err := u.repo(ctx).IncrementClickCount(shortCode)
+	if err != nil {
 		// Log error but don't fail the redirect
 		fmt.Printf("Failed to increment click count: %v\n", err)
 	}
 
 	// Add analytics
+	ua := utils.ParseUserAgent(userAgent)
+	ref := utils.ParseReferrer(referer, u.baseURL)
 	analytics := &entities.Analytics{
-		URLID:     0, // Will be set by repository
-		IPAddress: ipAddress,
-		UserAgent: userAgent,
-		Referer:   referer,
-		ClickedAt: time.Now(),
+		URLID:           0, // Will be set by repository
+		ClickedAt:       time.Now().UTC(),
+		Browser:         ua.Browser,
+		BrowserVersion:  ua.BrowserVersion,
+		OS:              ua.OS,
+		DeviceType:      ua.DeviceType,
+		ReferrerHost:    ref.Host,
+		ReferrerChannel: ref.Channel,
+	}
+	var visitor string
+	if !doNotTrack || !u.honorDoNotTrack() {
+		analytics.IPAddress = u.anonymizeIP(ipAddress, analytics.ClickedAt)
+		analytics.UserAgent = userAgent
+		analytics.Referer = referer
+		// Chỉ lưu fingerprint có salt theo ngày; fingerprint thô chỉ đi vào HyperLogLog
+		analytics.VisitorHash = u.anonymizer().Fingerprint(ipAddress, userAgent, analytics.ClickedAt)
+		visitor = utils.VisitorFingerprint(ipAddress, userAgent)
 	}
 
 	// Get URL ID for analytics
-	urlEntity, err := u.urlRepo.GetByShortCode(shortCode)
+	urlEntity, err := u.repo(ctx).GetByShortCode(shortCode)
 	if err == nil {
 		analytics.URLID = urlEntity.ID
-		if err := u.urlRepo.AddAnalytics(analytics); err != nil {
+		if u.isClickMilestone(clickCount) {
+			u.publish(entities.WebhookEventClickMilestone, entities.ClickMilestoneEventData{
+				ShortCode:   urlEntity.ShortCode,
+				ShortURL:    fmt.Sprintf("%s/%s", u.baseURL, urlEntity.ShortCode),
+				OriginalURL: urlEntity.OriginalURL,
+				Milestone:   clickCount,
+				OccurredAt:  analytics.ClickedAt,
+			})
+		}
+		// Đếm lệnh ghi đang chạy; tăng cao khi DB nghẽn
+		utils.AnalyticsWritesInFlight.Inc()
+		err := u.repo(ctx).AddAnalytics(analytics)
+		utils.AnalyticsWritesInFlight.Dec()
+		if err != nil {
 			// Log error but don't fail the redirect
 			fmt.Printf("Failed to add analytics: %v\n", err)
+		} else {
+			u.publishClick(ctx, urlEntity.ShortCode, analytics)
+		}
+		if u.visitors != nil && visitor != "" {
+			if err := u.visitors.Add(ctx, urlEntity.ID, visitor, analytics.ClickedAt); err != nil {
+				fmt.Printf("Failed to count unique visitor: %v\n", err)
+			}
 		}
 	}
 
-	return originalURL, nil
+	return &entities.RedirectTarget{URL: activeURL.OriginalURL, StatusCode: activeURL.RedirectStatus()}, nil
 }
 
 // GetURLStats lấy thống kê URL
-func (u *urlUsecase) GetURLStats(shortCode string) (*entities.URLStatsResponse, error) {
-	urlEntity, err := u.urlRepo.GetByShortCode(shortCode)
-	if err != nil {
-		return nil, fmt.Errorf("URL not found: %w", err)
-	}
+func (u *urlUsecase) GetURLStats(ctx context.Context, shortCode string) (*entities.URLStatsResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.GetURLStats")
+	defer span.End()
 
-	// Get analytics
-	analytics, err := u.urlRepo.GetAnalytics(urlEntity.ID)
+	urlEntity, err := u.findAuthorizedURL(ctx, shortCode, entities.WorkspaceRoleAnalyst)
 	if err != nil {
-		analytics = []entities.Analytics{} // Return empty if no analytics
+		return nil, err
 	}
 
-	// Find last clicked time
-	var lastClicked *time.Time
-	if len(analytics) > 0 {
-		lastClicked = &analytics[len(analytics)-1].ClickedAt
+	// Find last clicked time, lịch sử click xem qua GET /api/v1/urls/:shortCode/clicks
+	lastClicked, err := u.repo(ctx).GetLastClickedAt(urlEntity.ID)
+	if err != nil {
+		fmt.Printf("Failed to get last clicked time: %v\n", err)
 	}
 
 	response := &entities.URLStatsResponse{
-		ShortCode:    urlEntity.ShortCode,
-		OriginalURL:  urlEntity.OriginalURL,
-		TotalClicks:  urlEntity.ClickCount,
-		CreatedAt:    urlEntity.CreatedAt,
-		LastClicked:  lastClicked,
-		ClickHistory: analytics,
+		ShortCode:        urlEntity.ShortCode,
+		OriginalURL:      urlEntity.OriginalURL,
+		TotalClicks:      urlEntity.ClickCount,
+		UniqueVisitors:   u.countUniqueVisitors(ctx, urlEntity.ID),
+		CreatedAt:        urlEntity.CreatedAt,
+		LastClicked:      lastClicked,
+		Browsers:         u.getClickBreakdown(ctx, urlEntity.ID, entities.DimensionBrowser),
+		OperatingSystems: u.getClickBreakdown(ctx, urlEntity.ID, entities.DimensionOS),
+		Devices:          u.getClickBreakdown(ctx, urlEntity.ID, entities.DimensionDevice),
 	}
 
 	return response, nil
 }
 
 // DeleteURL xóa URL
-func (u *urlUsecase) DeleteURL(shortCode string) error {
-	urlEntity, err := u.urlRepo.GetByShortCode(shortCode)
+func (u *urlUsecase) DeleteURL(ctx context.Context, shortCode string) error {
+	ctx, span := utils.StartSpan(ctx, "urlUsecase.DeleteURL")
+	defer span.End()
+
+	urlEntity, err := u.findAuthorizedURL(ctx, shortCode, entities.WorkspaceRoleEditor)
 	if err != nil {
-		return fmt.Errorf("URL not found: %w", err)
+		return err
+	}
+
+	if err := u.repo(ctx).Delete(urlEntity.ID); err != nil {
+		return err
 	}
 
-	return u.urlRepo.Delete(urlEntity.ID)
+	u.publish(entities.WebhookEventURLDeleted, u.urlEventData(urlEntity, time.Now().UTC()))
+	return nil
+}
+
+// repo trả về repository chạy query trong ctx của request
+func (u *urlUsecase) repo(ctx context.Context) repositories.IURLRepository {
+	return u.urlRepo.WithContext(ctx)
+}
+
+// publish phát event nếu usecase có event publisher
+func (u *urlUsecase) publish(eventType string, data interface{}) {
+	if u.events != nil {
+		u.events.Publish(eventType, data)
+	}
+}
+
+// urlEventData tạo data cho event url.created và url.deleted
+func (u *urlUsecase) urlEventData(urlEntity *entities.URL, occurredAt time.Time) entities.URLEventData {
+	return entities.URLEventData{
+		ShortCode:   urlEntity.ShortCode,
+		ShortURL:    fmt.Sprintf("%s/%s", u.baseURL, urlEntity.ShortCode),
+		OriginalURL: urlEntity.OriginalURL,
+		OccurredAt:  occurredAt.UTC(),
+	}
+}
+
+// isClickMilestone kiểm tra click_count vừa đạt một mốc cấu hình.
+// click_count tăng nguyên tử nên mỗi mốc chỉ được phát một lần.
+func (u *urlUsecase) isClickMilestone(clickCount int64) bool {
+	if u.config == nil || clickCount <= 0 {
+		return false
+	}
+	for _, milestone := range u.config.Webhook.ClickMilestones {
+		if clickCount == milestone {
+			return true
+		}
+	}
+	return false
 }
 
 // validateURL kiểm tra URL có hợp lệ không
 func (u *urlUsecase) validateURL(rawURL string) error {
 	if rawURL == "" {
-		return errors.New("URL cannot be empty")
+		return ErrInvalidURL.WithMessage("URL cannot be empty")
 	}
 
 	// Add protocol if missing
@@ -221,11 +396,11 @@ func (u *urlUsecase) validateURL(rawURL string) error {
 
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
-		return fmt.Errorf("invalid URL format: %w", err)
+		return ErrInvalidURL.WithMessage("invalid URL format: %v", err)
 	}
 
 	if parsedURL.Host == "" {
-		return errors.New("URL must have a valid host")
+		return ErrInvalidURL.WithMessage("URL must have a valid host")
 	}
 
 	return nil
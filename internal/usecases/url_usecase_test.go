@@ -3,11 +3,14 @@ package usecases
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/url-shorted2/internal/config"
 	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
 	"github.com/url-shorted2/internal/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -20,11 +23,20 @@ type MockURLRepository struct {
 	mock.Mock
 }
 
+func (m *MockURLRepository) WithContext(ctx context.Context) repositories.IURLRepository {
+	return m
+}
+
 func (m *MockURLRepository) Create(url *entities.URL) error {
 	args := m.Called(url)
 	return args.Error(0)
 }
 
+func (m *MockURLRepository) CreateBatch(urls []*entities.URL) error {
+	args := m.Called(urls)
+	return args.Error(0)
+}
+
 func (m *MockURLRepository) GetByShortCode(shortCode string) (*entities.URL, error) {
 	args := m.Called(shortCode)
 	if args.Get(0) == nil {
@@ -33,6 +45,14 @@ func (m *MockURLRepository) GetByShortCode(shortCode string) (*entities.URL, err
 	return args.Get(0).(*entities.URL), args.Error(1)
 }
 
+func (m *MockURLRepository) FindExistingShortCodes(codes []string) ([]string, error) {
+	args := m.Called(codes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockURLRepository) GetByID(id uint) (*entities.URL, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -51,16 +71,48 @@ func (m *MockURLRepository) Delete(id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockURLRepository) IncrementClickCount(shortCode string) error {
+func (m *MockURLRepository) IncrementClickCount(shortCode string) (int64, error) {
 	args := m.Called(shortCode)
-	return args.Error(0)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockURLRepository) GetAnalytics(urlID uint) ([]entities.Analytics, error) {
-	args := m.Called(urlID)
+func (m *MockURLRepository) ListURLs(filter entities.URLFilter) ([]entities.URL, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.URL), args.Error(1)
+}
+
+func (m *MockURLRepository) ListAnalytics(urlID uint, filter entities.ClickFilter) ([]entities.Analytics, error) {
+	args := m.Called(urlID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]entities.Analytics), args.Error(1)
 }
 
+// StreamAnalytics gọi fn cho từng dòng trong slice được cấu hình ở Return
+func (m *MockURLRepository) StreamAnalytics(urlID uint, filter entities.ClickFilter, fn func(*entities.Analytics) error) error {
+	args := m.Called(urlID, filter, fn)
+	if rows, ok := args.Get(0).([]entities.Analytics); ok {
+		for i := range rows {
+			if err := fn(&rows[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockURLRepository) GetLastClickedAt(urlID uint) (*time.Time, error) {
+	args := m.Called(urlID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
 func (m *MockURLRepository) AddAnalytics(analytics *entities.Analytics) error {
 	args := m.Called(analytics)
 	return args.Error(0)
@@ -71,6 +123,81 @@ func (m *MockURLRepository) GetLastID() (uint, error) {
 	return args.Get(0).(uint), args.Error(1)
 }
 
+func (m *MockURLRepository) GetClickTimeSeries(urlID uint, from, to time.Time, interval string, loc *time.Location, rollupBefore time.Time) ([]entities.TimeSeriesBucket, error) {
+	args := m.Called(urlID, from, to, interval, loc, rollupBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.TimeSeriesBucket), args.Error(1)
+}
+
+func (m *MockURLRepository) GetClickBreakdown(urlID uint, dimension string, rollupBefore time.Time, limit int) ([]entities.DimensionCount, error) {
+	args := m.Called(urlID, dimension, rollupBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.DimensionCount), args.Error(1)
+}
+
+func (m *MockURLRepository) GetTopReferrers(urlID uint, rollupBefore time.Time, limit int) ([]entities.ReferrerCount, error) {
+	args := m.Called(urlID, rollupBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.ReferrerCount), args.Error(1)
+}
+
+func (m *MockURLRepository) CountUniqueVisitors(urlID uint, from, to time.Time) (int64, error) {
+	args := m.Called(urlID, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockURLRepository) RollupAnalytics(granularity string, from, to time.Time) (int64, error) {
+	args := m.Called(granularity, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockURLRepository) GetRollupWatermark(granularity string) (*time.Time, error) {
+	args := m.Called(granularity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockURLRepository) GetOldestClickedAt() (*time.Time, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockURLRepository) PruneAnalytics(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockURLRepository) DeleteAnalytics(ipAddresses, fingerprints []string) (int64, error) {
+	args := m.Called(ipAddresses, fingerprints)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockURLRepository) ListPendingMetadata(limit int) ([]entities.URL, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]entities.URL), args.Error(1)
+}
+
+func (m *MockURLRepository) UpdateMetadata(id uint, metadata entities.URLMetadata) error {
+	args := m.Called(id, metadata)
+	return args.Error(0)
+}
+
+func (m *MockURLRepository) UpdatePreview(id uint, preview entities.URLPreview) error {
+	args := m.Called(id, preview)
+	return args.Error(0)
+}
+
 // MockIDLock là mock cho IDLock interface
 type MockIDLock struct {
 	mock.Mock
@@ -89,13 +216,29 @@ func (m *MockIDLock) Unlock(ctx context.Context, ld *utils.LockData) error {
 	return args.Error(0)
 }
 
+// MockVisitorCounter là mock cho IVisitorCounter interface
+type MockVisitorCounter struct {
+	mock.Mock
+}
+
+func (m *MockVisitorCounter) Add(ctx context.Context, urlID uint, fingerprint string, at time.Time) error {
+	args := m.Called(ctx, urlID, fingerprint, at)
+	return args.Error(0)
+}
+
+func (m *MockVisitorCounter) CountDays(ctx context.Context, urlID uint, from, to time.Time) (int64, error) {
+	args := m.Called(ctx, urlID, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestURLUsecase_CreateShortURL(t *testing.T) {
 	tests := []struct {
-		name    string
-		req     entities.CreateURLRequest
-		setup   func(*MockURLRepository, *MockIDLock)
-		want    *entities.CreateURLResponse
-		wantErr bool
+		name  string
+		req   entities.CreateURLRequest
+		setup func(*MockURLRepository, *MockIDLock)
+		want  *entities.CreateURLResponse
+		// wantCode là code của DomainError mong đợi, rỗng nếu không lỗi
+		wantCode string
 	}{
 		{
 			name: "Tạo short URL thành công",
@@ -114,7 +257,7 @@ func TestURLUsecase_CreateShortURL(t *testing.T) {
 				ShortURL:    "http://localhost:8080/1",
 				OriginalURL: "https://example.com",
 			},
-			wantErr: false,
+			wantCode: "",
 		},
 		{
 			name: "Tạo short URL với URL không hợp lệ",
@@ -124,8 +267,8 @@ func TestURLUsecase_CreateShortURL(t *testing.T) {
 			setup: func(mockRepo *MockURLRepository, mockLock *MockIDLock) {
 				// Không cần setup mock vì sẽ fail ở validateURL trước khi gọi Lock
 			},
-			want:    nil,
-			wantErr: true,
+			want:     nil,
+			wantCode: "invalid_url",
 		},
 		{
 			name: "Tạo short URL với URL rỗng",
@@ -135,8 +278,8 @@ func TestURLUsecase_CreateShortURL(t *testing.T) {
 			setup: func(mockRepo *MockURLRepository, mockLock *MockIDLock) {
 				// Không cần setup mock vì sẽ fail ở validateURL trước khi gọi Lock
 			},
-			want:    nil,
-			wantErr: true,
+			want:     nil,
+			wantCode: "invalid_url",
 		},
 		{
 			name: "Tạo short URL thất bại do lỗi database",
@@ -150,8 +293,22 @@ func TestURLUsecase_CreateShortURL(t *testing.T) {
 				mockRepo.On("GetLastID").Return(uint(0), gorm.ErrRecordNotFound)
 				mockRepo.On("Create", mock.AnythingOfType("*entities.URL")).Return(errors.New("database error"))
 			},
-			want:    nil,
-			wantErr: true,
+			want:     nil,
+			wantCode: "internal_error",
+		},
+		{
+			name: "Tạo short URL thất bại do lỗi đọc last ID",
+			req: entities.CreateURLRequest{
+				OriginalURL: "https://example.com",
+			},
+			setup: func(mockRepo *MockURLRepository, mockLock *MockIDLock) {
+				lockData := &utils.LockData{Key: "lock-create-shorted-link", Value: "lock123"}
+				mockLock.On("Lock", mock.Anything, "lock-create-shorted-link").Return(lockData, nil)
+				mockLock.On("Unlock", mock.Anything, lockData).Return(nil)
+				mockRepo.On("GetLastID").Return(uint(0), errors.New("database is locked"))
+			},
+			want:     nil,
+			wantCode: "internal_error",
 		},
 		{
 			name: "Tạo short URL thất bại do không lấy được lock",
@@ -161,8 +318,8 @@ func TestURLUsecase_CreateShortURL(t *testing.T) {
 			setup: func(mockRepo *MockURLRepository, mockLock *MockIDLock) {
 				mockLock.On("Lock", mock.Anything, "lock-create-shorted-link").Return(nil, errors.New("lock failed"))
 			},
-			want:    nil,
-			wantErr: true,
+			want:     nil,
+			wantCode: "lock_unavailable",
 		},
 	}
 
@@ -179,10 +336,11 @@ func TestURLUsecase_CreateShortURL(t *testing.T) {
 				config:  getTestConfig(),
 			}
 
-			got, err := usecase.CreateShortURL(tt.req)
+			got, err := usecase.CreateShortURL(context.Background(), tt.req)
 
-			if tt.wantErr {
+			if tt.wantCode != "" {
 				assert.Error(t, err)
+				assert.Equal(t, tt.wantCode, AsDomainError(err).Code)
 				assert.Nil(t, got)
 			} else {
 				assert.NoError(t, err)
@@ -198,13 +356,194 @@ func TestURLUsecase_CreateShortURL(t *testing.T) {
 	}
 }
 
+func TestURLUsecase_CreateShortURLs(t *testing.T) {
+	newUsecase := func(mockRepo *MockURLRepository, mockLock *MockIDLock) *urlUsecase {
+		cfg := getTestConfig()
+		cfg.Links.BatchMaxItems = 3
+		return &urlUsecase{
+			urlRepo: mockRepo,
+			baseURL: "http://localhost:8080",
+			locker:  mockLock,
+			config:  cfg,
+		}
+	}
+
+	t.Run("Tạo batch với item lỗi xen kẽ", func(t *testing.T) {
+		mockRepo := &MockURLRepository{}
+		mockLock := &MockIDLock{}
+		lockData := &utils.LockData{Key: "lock-create-shorted-link", Value: "lock123"}
+		mockLock.On("Lock", mock.Anything, "lock-create-shorted-link").Return(lockData, nil).Once()
+		mockLock.On("Unlock", mock.Anything, lockData).Return(nil).Once()
+		mockRepo.On("GetLastID").Return(uint(41), nil)
+		mockRepo.On("CreateBatch", mock.MatchedBy(func(urls []*entities.URL) bool {
+			return len(urls) == 2 && urls[0].ShortCode == "42" && urls[1].ShortCode == "43"
+		})).Return(nil)
+
+		got, err := newUsecase(mockRepo, mockLock).CreateShortURLs(context.Background(), entities.BatchCreateURLRequest{
+			Items: []entities.CreateURLRequest{
+				{OriginalURL: "https://example.com/a"},
+				{OriginalURL: "https://"},
+				{OriginalURL: "https://example.com/b"},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, got.Created)
+		assert.Equal(t, 1, got.Failed)
+		assert.Len(t, got.Results, 3)
+		assert.Equal(t, "42", got.Results[0].URL.ShortCode)
+		assert.Equal(t, "http://localhost:8080/42", got.Results[0].URL.ShortURL)
+		assert.Nil(t, got.Results[1].URL)
+		assert.NotEmpty(t, got.Results[1].Error)
+		assert.Equal(t, 2, got.Results[2].Index)
+		assert.Equal(t, "43", got.Results[2].URL.ShortCode)
+		mockRepo.AssertExpectations(t)
+		mockLock.AssertExpectations(t)
+	})
+
+	t.Run("Tất cả item lỗi thì không lấy lock", func(t *testing.T) {
+		mockRepo := &MockURLRepository{}
+		mockLock := &MockIDLock{}
+
+		got, err := newUsecase(mockRepo, mockLock).CreateShortURLs(context.Background(), entities.BatchCreateURLRequest{
+			Items: []entities.CreateURLRequest{{OriginalURL: ""}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, got.Created)
+		assert.Equal(t, 1, got.Failed)
+		mockLock.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Vượt quá số item tối đa", func(t *testing.T) {
+		items := make([]entities.CreateURLRequest, 4)
+		got, err := newUsecase(&MockURLRepository{}, &MockIDLock{}).CreateShortURLs(context.Background(), entities.BatchCreateURLRequest{Items: items})
+
+		assert.ErrorIs(t, err, ErrInvalidBatch)
+		assert.Nil(t, got)
+	})
+
+	t.Run("Lỗi database thì không tạo item nào", func(t *testing.T) {
+		mockRepo := &MockURLRepository{}
+		mockLock := &MockIDLock{}
+		lockData := &utils.LockData{Key: "lock-create-shorted-link", Value: "lock123"}
+		mockLock.On("Lock", mock.Anything, "lock-create-shorted-link").Return(lockData, nil)
+		mockLock.On("Unlock", mock.Anything, lockData).Return(nil)
+		mockRepo.On("GetLastID").Return(uint(0), gorm.ErrRecordNotFound)
+		mockRepo.On("CreateBatch", mock.Anything).Return(errors.New("database error"))
+
+		got, err := newUsecase(mockRepo, mockLock).CreateShortURLs(context.Background(), entities.BatchCreateURLRequest{
+			Items: []entities.CreateURLRequest{{OriginalURL: "https://example.com"}},
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+		mockLock.AssertExpectations(t)
+	})
+}
+
+func TestURLUsecase_ImportURLs(t *testing.T) {
+	// Header theo kiểu file export của Bitly: code là short URL đầy đủ
+	const bitlyCSV = "Bitlink,Long URL,Created,Clicks\n" +
+		"bit.ly/promo,https://example.com/promo,2023-05-01 10:00:00,\"1,234\"\n" +
+		"bit.ly/bad,https://,2023-05-01,1\n" +
+		"bit.ly/123,https://example.com/numeric,,\n" +
+		"bit.ly/taken,https://example.com/taken,,\n" +
+		"bit.ly/promo,https://example.com/dup,,\n" +
+		"bit.ly/docs,https://example.com/docs,1682935200,7\n"
+
+	newUsecase := func(mockRepo *MockURLRepository, mockLock *MockIDLock) *urlUsecase {
+		return &urlUsecase{
+			urlRepo: mockRepo,
+			baseURL: "http://localhost:8080",
+			locker:  mockLock,
+			config:  getTestConfig(),
+		}
+	}
+
+	t.Run("Dry-run báo cáo lỗi và không ghi database", func(t *testing.T) {
+		mockRepo := &MockURLRepository{}
+		mockLock := &MockIDLock{}
+		mockRepo.On("FindExistingShortCodes", []string{"promo", "taken", "docs"}).Return([]string{"taken"}, nil)
+
+		report, err := newUsecase(mockRepo, mockLock).ImportURLs(context.Background(), strings.NewReader(bitlyCSV), entities.ImportURLsRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.ImportModeDryRun, report.Mode)
+		assert.Equal(t, entities.ImportFormatCSV, report.Format)
+		assert.Equal(t, 6, report.Total)
+		assert.Equal(t, 2, report.Valid)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, 1, report.Invalid)
+		assert.Equal(t, 3, report.Conflicts)
+		var rows []int
+		for _, issue := range report.Issues {
+			rows = append(rows, issue.Row)
+		}
+		assert.Equal(t, []int{2, 3, 4, 5}, rows)
+		assert.Equal(t, entities.ImportRowConflict, report.Issues[2].Status)
+		assert.Equal(t, "code already exists", report.Issues[2].Error)
+		mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything)
+		mockLock.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Apply tạo link với code, ngày tạo và click gốc", func(t *testing.T) {
+		mockRepo := &MockURLRepository{}
+		mockLock := &MockIDLock{}
+		lockData := &utils.LockData{Key: "lock-create-shorted-link", Value: "lock123"}
+		mockLock.On("Lock", mock.Anything, "lock-create-shorted-link").Return(lockData, nil)
+		mockLock.On("Unlock", mock.Anything, lockData).Return(nil)
+		mockRepo.On("FindExistingShortCodes", mock.Anything).Return([]string{"taken"}, nil)
+		mockRepo.On("CreateBatch", mock.MatchedBy(func(urls []*entities.URL) bool {
+			return len(urls) == 2 &&
+				urls[0].ShortCode == "promo" && urls[0].ClickCount == 1234 &&
+				urls[0].CreatedAt.Equal(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)) &&
+				urls[1].ShortCode == "docs" && urls[1].ClickCount == 7 &&
+				urls[1].CreatedAt.Equal(time.Unix(1682935200, 0))
+		})).Return(nil)
+
+		report, err := newUsecase(mockRepo, mockLock).ImportURLs(context.Background(), strings.NewReader(bitlyCSV), entities.ImportURLsRequest{Mode: entities.ImportModeApply})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		mockRepo.AssertExpectations(t)
+		mockLock.AssertExpectations(t)
+	})
+
+	t.Run("JSON export dạng object bọc mảng", func(t *testing.T) {
+		mockRepo := &MockURLRepository{}
+		mockRepo.On("FindExistingShortCodes", []string{"kutt1"}).Return([]string{}, nil)
+		body := `{"data":[{"address":"kutt1","target":"https://example.com","visit_count":3,"created_at":"2023-01-02T03:04:05Z"}]}`
+
+		report, err := newUsecase(mockRepo, &MockIDLock{}).ImportURLs(context.Background(), strings.NewReader(body), entities.ImportURLsRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.ImportFormatJSON, report.Format)
+		assert.Equal(t, 1, report.Valid)
+		assert.Empty(t, report.Issues)
+	})
+
+	t.Run("Thiếu cột destination", func(t *testing.T) {
+		report, err := newUsecase(&MockURLRepository{}, &MockIDLock{}).ImportURLs(context.Background(), strings.NewReader("code,title\nabc,x\n"), entities.ImportURLsRequest{})
+
+		assert.ErrorIs(t, err, ErrInvalidImport)
+		assert.Nil(t, report)
+	})
+
+	t.Run("Mode không hợp lệ", func(t *testing.T) {
+		_, err := newUsecase(&MockURLRepository{}, &MockIDLock{}).ImportURLs(context.Background(), strings.NewReader(bitlyCSV), entities.ImportURLsRequest{Mode: "force"})
+
+		assert.ErrorIs(t, err, ErrInvalidImport)
+	})
+}
+
 func TestURLUsecase_GetOriginalURL(t *testing.T) {
 	tests := []struct {
 		name      string
 		shortCode string
 		setup     func(*MockURLRepository)
 		want      string
-		wantErr   bool
+		wantErr   error
 	}{
 		{
 			name:      "Lấy original URL thành công",
@@ -217,7 +556,7 @@ func TestURLUsecase_GetOriginalURL(t *testing.T) {
 				}, nil)
 			},
 			want:    "https://example.com",
-			wantErr: false,
+			wantErr: nil,
 		},
 		{
 			name:      "URL không tồn tại",
@@ -226,7 +565,7 @@ func TestURLUsecase_GetOriginalURL(t *testing.T) {
 				mockRepo.On("GetByShortCode", "notfound").Return(nil, gorm.ErrRecordNotFound)
 			},
 			want:    "",
-			wantErr: true,
+			wantErr: ErrURLNotFound,
 		},
 		{
 			name:      "URL không active",
@@ -239,7 +578,7 @@ func TestURLUsecase_GetOriginalURL(t *testing.T) {
 				}, nil)
 			},
 			want:    "",
-			wantErr: true,
+			wantErr: ErrURLInactive,
 		},
 	}
 
@@ -253,10 +592,10 @@ func TestURLUsecase_GetOriginalURL(t *testing.T) {
 				baseURL: "http://localhost:8080",
 			}
 
-			got, err := usecase.GetOriginalURL(tt.shortCode)
+			got, err := usecase.GetOriginalURL(context.Background(), tt.shortCode)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 				assert.Empty(t, got)
 			} else {
 				assert.NoError(t, err)
@@ -294,7 +633,7 @@ func TestURLUsecase_Redirect(t *testing.T) {
 					ID:          1,
 				}, nil)
 				// IncrementClickCount call
-				mockRepo.On("IncrementClickCount", "abc123").Return(nil)
+				mockRepo.On("IncrementClickCount", "abc123").Return(int64(1), nil)
 				// GetByShortCode call for analytics
 				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
 					ShortCode:   "abc123",
@@ -335,7 +674,7 @@ func TestURLUsecase_Redirect(t *testing.T) {
 					ID:          1,
 				}, nil)
 				// IncrementClickCount call fails
-				mockRepo.On("IncrementClickCount", "abc123").Return(errors.New("increment failed"))
+				mockRepo.On("IncrementClickCount", "abc123").Return(int64(0), errors.New("increment failed"))
 				// GetByShortCode call for analytics
 				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
 					ShortCode:   "abc123",
@@ -361,14 +700,15 @@ func TestURLUsecase_Redirect(t *testing.T) {
 				baseURL: "http://localhost:8080",
 			}
 
-			got, err := usecase.Redirect(tt.shortCode, tt.ipAddress, tt.userAgent, tt.referer)
+			got, err := usecase.Redirect(context.Background(), tt.shortCode, tt.ipAddress, tt.userAgent, tt.referer, false)
 
 			if tt.wantErr {
 				assert.Error(t, err)
-				assert.Empty(t, got)
+				assert.Nil(t, got)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
+				assert.Equal(t, tt.want, got.URL)
+				assert.Equal(t, entities.DefaultRedirectType, got.StatusCode)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -377,6 +717,8 @@ func TestURLUsecase_Redirect(t *testing.T) {
 }
 
 func TestURLUsecase_GetURLStats(t *testing.T) {
+	lastClicked := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name      string
 		shortCode string
@@ -396,21 +738,32 @@ func TestURLUsecase_GetURLStats(t *testing.T) {
 					ID:          1,
 				}
 				mockRepo.On("GetByShortCode", "abc123").Return(urlEntity, nil)
-
-				analytics := []entities.Analytics{
-					{
-						URLID:     1,
-						IPAddress: "192.168.1.1",
-						UserAgent: "Mozilla/5.0",
-						ClickedAt: time.Now(),
-					},
-				}
-				mockRepo.On("GetAnalytics", uint(1)).Return(analytics, nil)
+				mockRepo.On("GetLastClickedAt", uint(1)).Return(&lastClicked, nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalDay).Return(nil, nil)
+				mockRepo.On("GetClickBreakdown", uint(1), entities.DimensionBrowser, time.Time{}, breakdownLimit).Return([]entities.DimensionCount{
+					{Value: "Chrome", Clicks: 4},
+					{Value: "Firefox", Clicks: 1},
+				}, nil)
+				mockRepo.On("GetClickBreakdown", uint(1), entities.DimensionOS, time.Time{}, breakdownLimit).Return([]entities.DimensionCount{
+					{Value: "Windows", Clicks: 5},
+				}, nil)
+				mockRepo.On("GetClickBreakdown", uint(1), entities.DimensionDevice, time.Time{}, breakdownLimit).Return(nil, errors.New("query failed"))
+				mockRepo.On("CountUniqueVisitors", uint(1), mock.Anything, mock.Anything).Return(int64(3), nil)
 			},
 			want: &entities.URLStatsResponse{
-				ShortCode:   "abc123",
-				OriginalURL: "https://example.com",
-				TotalClicks: 5,
+				ShortCode:      "abc123",
+				OriginalURL:    "https://example.com",
+				TotalClicks:    5,
+				UniqueVisitors: 3,
+				LastClicked:    &lastClicked,
+				Browsers: []entities.DimensionCount{
+					{Value: "Chrome", Clicks: 4},
+					{Value: "Firefox", Clicks: 1},
+				},
+				OperatingSystems: []entities.DimensionCount{
+					{Value: "Windows", Clicks: 5},
+				},
+				Devices: []entities.DimensionCount{},
 			},
 			wantErr: false,
 		},
@@ -435,13 +788,18 @@ func TestURLUsecase_GetURLStats(t *testing.T) {
 					ID:          1,
 				}
 				mockRepo.On("GetByShortCode", "abc123").Return(urlEntity, nil)
-				mockRepo.On("GetAnalytics", uint(1)).Return([]entities.Analytics{}, gorm.ErrRecordNotFound)
+				mockRepo.On("GetLastClickedAt", uint(1)).Return(nil, nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalDay).Return(nil, nil)
+				mockRepo.On("GetClickBreakdown", uint(1), mock.Anything, time.Time{}, breakdownLimit).Return([]entities.DimensionCount{}, nil)
+				mockRepo.On("CountUniqueVisitors", uint(1), mock.Anything, mock.Anything).Return(int64(0), nil)
 			},
 			want: &entities.URLStatsResponse{
-				ShortCode:    "abc123",
-				OriginalURL:  "https://example.com",
-				TotalClicks:  0,
-				ClickHistory: []entities.Analytics{},
+				ShortCode:        "abc123",
+				OriginalURL:      "https://example.com",
+				TotalClicks:      0,
+				Browsers:         []entities.DimensionCount{},
+				OperatingSystems: []entities.DimensionCount{},
+				Devices:          []entities.DimensionCount{},
 			},
 			wantErr: false,
 		},
@@ -457,7 +815,7 @@ func TestURLUsecase_GetURLStats(t *testing.T) {
 				baseURL: "http://localhost:8080",
 			}
 
-			got, err := usecase.GetURLStats(tt.shortCode)
+			got, err := usecase.GetURLStats(WithSystemCaller(context.Background()), tt.shortCode)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -468,6 +826,11 @@ func TestURLUsecase_GetURLStats(t *testing.T) {
 				assert.Equal(t, tt.want.ShortCode, got.ShortCode)
 				assert.Equal(t, tt.want.OriginalURL, got.OriginalURL)
 				assert.Equal(t, tt.want.TotalClicks, got.TotalClicks)
+				assert.Equal(t, tt.want.UniqueVisitors, got.UniqueVisitors)
+				assert.Equal(t, tt.want.LastClicked, got.LastClicked)
+				assert.Equal(t, tt.want.Browsers, got.Browsers)
+				assert.Equal(t, tt.want.OperatingSystems, got.OperatingSystems)
+				assert.Equal(t, tt.want.Devices, got.Devices)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -475,44 +838,171 @@ func TestURLUsecase_GetURLStats(t *testing.T) {
 	}
 }
 
-func TestURLUsecase_DeleteURL(t *testing.T) {
+func TestURLUsecase_countUniqueVisitors(t *testing.T) {
+	// Redis và fallback database đếm trên cùng cửa sổ ngày UTC: 7 ngày tính cả hôm nay
+	windowStart := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -6)
+	cfg := getTestConfig()
+	cfg.Analytics.VisitorDayTTL = 7 * 24 * time.Hour
+
+	tests := []struct {
+		name  string
+		setup func(*MockURLRepository, *MockVisitorCounter)
+		want  int64
+	}{
+		{
+			name: "Đếm bằng Redis HyperLogLog theo ngày",
+			setup: func(mockRepo *MockURLRepository, mockVisitors *MockVisitorCounter) {
+				mockVisitors.On("CountDays", mock.Anything, uint(1), windowStart, mock.AnythingOfType("time.Time")).Return(int64(42), nil)
+			},
+			want: 42,
+		},
+		{
+			name: "Redis lỗi thì fallback sang database",
+			setup: func(mockRepo *MockURLRepository, mockVisitors *MockVisitorCounter) {
+				mockVisitors.On("CountDays", mock.Anything, uint(1), windowStart, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("connection refused"))
+				mockRepo.On("CountUniqueVisitors", uint(1), windowStart, mock.AnythingOfType("time.Time")).Return(int64(40), nil)
+			},
+			want: 40,
+		},
+		{
+			name: "Database cũng lỗi thì trả về 0",
+			setup: func(mockRepo *MockURLRepository, mockVisitors *MockVisitorCounter) {
+				mockVisitors.On("CountDays", mock.Anything, uint(1), windowStart, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("connection refused"))
+				mockRepo.On("CountUniqueVisitors", uint(1), windowStart, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("database error"))
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockURLRepository{}
+			mockVisitors := &MockVisitorCounter{}
+			tt.setup(mockRepo, mockVisitors)
+
+			usecase := &urlUsecase{
+				urlRepo:  mockRepo,
+				visitors: mockVisitors,
+				config:   cfg,
+			}
+
+			assert.Equal(t, tt.want, usecase.countUniqueVisitors(context.Background(), 1))
+
+			mockRepo.AssertExpectations(t)
+			mockVisitors.AssertExpectations(t)
+		})
+	}
+}
+
+func TestURLUsecase_GetClickTimeSeries(t *testing.T) {
+	rolledUntil := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	recentFrom := time.Now().UTC().AddDate(0, 0, -5).Truncate(time.Hour)
+
+	// Giữ click thô 30 ngày
+	cfg := getTestConfig()
+	cfg.Analytics.RawRetention = 30 * 24 * time.Hour
+
 	tests := []struct {
 		name      string
 		shortCode string
+		req       entities.TimeSeriesRequest
 		setup     func(*MockURLRepository)
-		wantErr   bool
+		wantTotal int64
+		wantErr   error
 	}{
 		{
-			name:      "Xóa URL thành công",
+			name:      "Lấy time series thành công",
 			shortCode: "abc123",
+			req: entities.TimeSeriesRequest{
+				Interval: "day",
+				From:     "2024-01-01",
+				To:       "2024-01-03",
+				TZ:       "Asia/Ho_Chi_Minh",
+			},
 			setup: func(mockRepo *MockURLRepository) {
-				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
-					ShortCode: "abc123",
-					ID:        1,
+				// Trước watermark của rollup theo giờ được đọc từ rollup
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalHour).Return(&rolledUntil, nil)
+				mockRepo.On("GetClickTimeSeries", uint(1), mock.Anything, mock.Anything, "day", mock.Anything, rolledUntil).Return([]entities.TimeSeriesBucket{
+					{Clicks: 3},
+					{Clicks: 0},
 				}, nil)
-				mockRepo.On("Delete", uint(1)).Return(nil)
 			},
-			wantErr: false,
+			wantTotal: 3,
 		},
 		{
-			name:      "URL không tồn tại",
-			shortCode: "notfound",
+			name:      "Timezone lệch nửa giờ trước retention",
+			shortCode: "abc123",
+			req: entities.TimeSeriesRequest{
+				Interval: "hour",
+				From:     "2024-01-01T12:00:00+05:30",
+				To:       "2024-01-02T12:00:00+05:30",
+				TZ:       "Asia/Kolkata",
+			},
 			setup: func(mockRepo *MockURLRepository) {
-				mockRepo.On("GetByShortCode", "notfound").Return(nil, gorm.ErrRecordNotFound)
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalHour).Return(&rolledUntil, nil)
 			},
-			wantErr: true,
+			wantErr: ErrInvalidStatsQuery,
 		},
 		{
-			name:      "Xóa URL thất bại",
+			name:      "Timezone lệch nửa giờ trong retention đọc click thô",
 			shortCode: "abc123",
+			req: entities.TimeSeriesRequest{
+				Interval: "day",
+				From:     recentFrom.Format(time.RFC3339),
+				To:       recentFrom.AddDate(0, 0, 2).Format(time.RFC3339),
+				TZ:       "Asia/Kolkata",
+			},
 			setup: func(mockRepo *MockURLRepository) {
-				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
-					ShortCode: "abc123",
-					ID:        1,
+				// Watermark rollup theo giờ ở sau from nhưng rollup không được đọc
+				rolledRecently := recentFrom.Add(24 * time.Hour)
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalHour).Return(&rolledRecently, nil)
+				rawOnly := mock.MatchedBy(func(rollupBefore time.Time) bool { return !rollupBefore.After(recentFrom) })
+				mockRepo.On("GetClickTimeSeries", uint(1), mock.Anything, mock.Anything, "day", mock.Anything, rawOnly).Return([]entities.TimeSeriesBucket{
+					{Clicks: 1},
+					{Clicks: 1},
 				}, nil)
-				mockRepo.On("Delete", uint(1)).Return(errors.New("delete failed"))
 			},
-			wantErr: true,
+			wantTotal: 2,
+		},
+		{
+			name:      "Interval không hợp lệ",
+			shortCode: "abc123",
+			req:       entities.TimeSeriesRequest{Interval: "minute"},
+			setup:     func(mockRepo *MockURLRepository) {},
+			wantErr:   ErrInvalidStatsQuery,
+		},
+		{
+			name:      "Timezone không hợp lệ",
+			shortCode: "abc123",
+			req:       entities.TimeSeriesRequest{TZ: "Mars/Olympus"},
+			setup:     func(mockRepo *MockURLRepository) {},
+			wantErr:   ErrInvalidStatsQuery,
+		},
+		{
+			name:      "From sau to",
+			shortCode: "abc123",
+			req:       entities.TimeSeriesRequest{From: "2024-02-01", To: "2024-01-01"},
+			setup:     func(mockRepo *MockURLRepository) {},
+			wantErr:   ErrInvalidStatsQuery,
+		},
+		{
+			name:      "Khoảng thời gian quá lớn",
+			shortCode: "abc123",
+			req:       entities.TimeSeriesRequest{Interval: "hour", From: "2020-01-01", To: "2024-01-01"},
+			setup:     func(mockRepo *MockURLRepository) {},
+			wantErr:   ErrInvalidStatsQuery,
+		},
+		{
+			name:      "URL không tồn tại",
+			shortCode: "notfound",
+			req:       entities.TimeSeriesRequest{},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "notfound").Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrURLNotFound,
 		},
 	}
 
@@ -524,12 +1014,790 @@ func TestURLUsecase_DeleteURL(t *testing.T) {
 			usecase := &urlUsecase{
 				urlRepo: mockRepo,
 				baseURL: "http://localhost:8080",
+				config:  cfg,
 			}
 
-			err := usecase.DeleteURL(tt.shortCode)
+			got, err := usecase.GetClickTimeSeries(WithSystemCaller(context.Background()), tt.shortCode, tt.req)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, got)
+				assert.Equal(t, tt.wantTotal, got.TotalClicks)
+				assert.Len(t, got.Buckets, 2)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestURLUsecase_GetReferrerStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		shortCode string
+		req       entities.ReferrerStatsRequest
+		setup     func(*MockURLRepository)
+		want      *entities.ReferrerStatsResponse
+		wantErr   error
+	}{
+		{
+			name:      "Lấy referrer stats thành công với limit mặc định",
+			shortCode: "abc123",
+			req:       entities.ReferrerStatsRequest{},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123", ClickCount: 7}, nil)
+				mockRepo.On("GetRollupWatermark", entities.IntervalDay).Return(nil, nil)
+				mockRepo.On("GetTopReferrers", uint(1), time.Time{}, defaultReferrerLimit).Return([]entities.ReferrerCount{
+					{Host: "google.com", Channel: "search", Clicks: 4},
+					{Host: "facebook.com", Channel: "social", Clicks: 2},
+				}, nil)
+				mockRepo.On("GetClickBreakdown", uint(1), entities.DimensionReferrerChannel, time.Time{}, breakdownLimit).Return([]entities.DimensionCount{
+					{Value: "search", Clicks: 4},
+					{Value: "social", Clicks: 2},
+					{Value: "direct", Clicks: 1},
+				}, nil)
+			},
+			want: &entities.ReferrerStatsResponse{
+				ShortCode:   "abc123",
+				TotalClicks: 7,
+				Channels: []entities.DimensionCount{
+					{Value: "search", Clicks: 4},
+					{Value: "social", Clicks: 2},
+					{Value: "direct", Clicks: 1},
+				},
+				Referrers: []entities.ReferrerCount{
+					{Host: "google.com", Channel: "search", Clicks: 4},
+					{Host: "facebook.com", Channel: "social", Clicks: 2},
+				},
+			},
+		},
+		{
+			name:      "Limit vượt quá giới hạn",
+			shortCode: "abc123",
+			req:       entities.ReferrerStatsRequest{Limit: 1000},
+			setup:     func(mockRepo *MockURLRepository) {},
+			wantErr:   ErrInvalidStatsQuery,
+		},
+		{
+			name:      "URL không tồn tại",
+			shortCode: "notfound",
+			req:       entities.ReferrerStatsRequest{Limit: 5},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "notfound").Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrURLNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockURLRepository{}
+			tt.setup(mockRepo)
+
+			usecase := &urlUsecase{
+				urlRepo: mockRepo,
+				baseURL: "http://localhost:8080",
+			}
+
+			got, err := usecase.GetReferrerStats(WithSystemCaller(context.Background()), tt.shortCode, tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestURLUsecase_ListClicks(t *testing.T) {
+	clicks := []entities.Analytics{{ID: 30}, {ID: 20}, {ID: 10}}
+
+	tests := []struct {
+		name           string
+		req            entities.ClickListRequest
+		setup          func(*MockURLRepository)
+		wantCount      int
+		wantHasMore    bool
+		wantNextCursor string
+		wantErr        error
+	}{
+		{
+			name: "Trang đầu còn trang sau",
+			req:  entities.ClickListRequest{Limit: 2, Country: "vn", Referrer: "https://www.google.com/search"},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+				mockRepo.On("ListAnalytics", uint(1), entities.ClickFilter{
+					Country:      "VN",
+					ReferrerHost: "google.com",
+					Limit:        3,
+				}).Return(clicks, nil)
+			},
+			wantCount:      2,
+			wantHasMore:    true,
+			wantNextCursor: encodeClickCursor(20),
+		},
+		{
+			name: "Trang cuối theo cursor",
+			req:  entities.ClickListRequest{Limit: 2, Cursor: encodeClickCursor(20)},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+				mockRepo.On("ListAnalytics", uint(1), entities.ClickFilter{BeforeID: 20, Limit: 3}).Return(clicks[2:], nil)
+			},
+			wantCount:   1,
+			wantHasMore: false,
+		},
+		{
+			name:    "Cursor không hợp lệ",
+			req:     entities.ClickListRequest{Cursor: "!!!"},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidStatsQuery,
+		},
+		{
+			name:    "Limit vượt quá giới hạn",
+			req:     entities.ClickListRequest{Limit: 10000},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidStatsQuery,
+		},
+		{
+			name:    "Khoảng thời gian không hợp lệ",
+			req:     entities.ClickListRequest{From: "2024-02-01", To: "2024-01-01"},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidStatsQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockURLRepository{}
+			tt.setup(mockRepo)
+
+			usecase := &urlUsecase{
+				urlRepo: mockRepo,
+				baseURL: "http://localhost:8080",
+			}
+
+			got, err := usecase.ListClicks(WithSystemCaller(context.Background()), "abc123", tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, got.Clicks, tt.wantCount)
+				assert.Equal(t, tt.wantHasMore, got.HasMore)
+				assert.Equal(t, tt.wantNextCursor, got.NextCursor)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestURLUsecase_ListURLs(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	urls := []entities.URL{
+		{ID: 3, ShortCode: "c", ClickCount: 30, CreatedAt: createdAt},
+		{ID: 2, ShortCode: "b", ClickCount: 20, CreatedAt: createdAt},
+		{ID: 1, ShortCode: "a", ClickCount: 10, CreatedAt: createdAt},
+	}
+	active := true
+
+	tests := []struct {
+		name           string
+		req            entities.URLListRequest
+		setup          func(*MockURLRepository)
+		wantCount      int
+		wantHasMore    bool
+		wantNextCursor string
+		wantErr        error
+	}{
+		{
+			name: "Trang đầu còn trang sau",
+			req:  entities.URLListRequest{Limit: 2, Active: &active, Host: "https://Example.com:8443/path", Q: " promo "},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("ListURLs", entities.URLFilter{
+					Active: &active,
+					Host:   "example.com",
+					Search: "promo",
+					SortBy: entities.URLSortCreated,
+					Limit:  3,
+				}).Return(urls, nil)
+			},
+			wantCount:      2,
+			wantHasMore:    true,
+			wantNextCursor: encodeURLCursor(entities.URLFilter{SortBy: entities.URLSortCreated}, &urls[1]),
+		},
+		{
+			name: "Trang sau theo cursor sắp xếp theo click",
+			req: entities.URLListRequest{
+				Sort:   entities.URLSortClicks,
+				Order:  "asc",
+				Cursor: encodeURLCursor(entities.URLFilter{SortBy: entities.URLSortClicks, Ascending: true}, &urls[2]),
+			},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("ListURLs", entities.URLFilter{
+					SortBy:    entities.URLSortClicks,
+					Ascending: true,
+					After:     &entities.URLCursor{ClickCount: 10, ID: 1},
+					Limit:     defaultURLPageSize + 1,
+				}).Return(urls[:2], nil)
+			},
+			wantCount:   2,
+			wantHasMore: false,
+		},
+		{
+			name:    "Cursor không khớp kiểu sắp xếp",
+			req:     entities.URLListRequest{Cursor: encodeURLCursor(entities.URLFilter{SortBy: entities.URLSortClicks}, &urls[0])},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidURLQuery,
+		},
+		{
+			name:    "Sort không hợp lệ",
+			req:     entities.URLListRequest{Sort: "name"},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidURLQuery,
+		},
+		{
+			name:    "Khoảng thời gian tạo không hợp lệ",
+			req:     entities.URLListRequest{CreatedFrom: "2024-02-01", CreatedTo: "2024-01-01"},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidURLQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockURLRepository{}
+			tt.setup(mockRepo)
+
+			usecase := &urlUsecase{
+				urlRepo: mockRepo,
+				baseURL: "http://localhost:8080",
+			}
+
+			got, err := usecase.ListURLs(WithSystemCaller(context.Background()), tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, got.URLs, tt.wantCount)
+				assert.Equal(t, tt.wantHasMore, got.HasMore)
+				assert.Equal(t, tt.wantNextCursor, got.NextCursor)
+				assert.Equal(t, "http://localhost:8080/"+got.URLs[0].ShortCode, got.URLs[0].ShortURL)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestURLUsecase_ExportClicks(t *testing.T) {
+	rows := []entities.Analytics{
+		{ID: 1, IPAddress: "192.168.1.1", Country: "VN", ClickedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, IPAddress: "10.0.0.1", UserAgent: "Mozilla/5.0, \"quoted\"", ClickedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	// Giá trị do visitor gửi lên bắt đầu bằng ký tự công thức
+	formulas := []entities.Analytics{
+		{ID: 3, UserAgent: "=HYPERLINK(\"http://evil.example\")", Referer: "+1", City: "-2", Browser: "@SUM(A1)", OS: "\tcmd", DeviceType: "\rcmd", ClickedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name            string
+		req             entities.ClickExportRequest
+		setup           func(*MockURLRepository)
+		wantContentType string
+		wantLines       int
+		wantContains    []string
+		wantErr         error
+	}{
+		{
+			name: "Export CSV mặc định",
+			req:  entities.ClickExportRequest{Country: "vn"},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+				mockRepo.On("StreamAnalytics", uint(1), entities.ClickFilter{Country: "VN"}, mock.Anything).Return(rows, nil)
+			},
+			wantContentType: "text/csv; charset=utf-8",
+			wantLines:       3, // header + 2 dòng
+		},
+		{
+			name: "Export NDJSON",
+			req:  entities.ClickExportRequest{Format: "ndjson"},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+				mockRepo.On("StreamAnalytics", uint(1), entities.ClickFilter{}, mock.Anything).Return(rows, nil)
+			},
+			wantContentType: "application/x-ndjson",
+			wantLines:       2,
+		},
+		{
+			name: "CSV escape giá trị bắt đầu bằng ký tự công thức",
+			req:  entities.ClickExportRequest{},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+				mockRepo.On("StreamAnalytics", uint(1), entities.ClickFilter{}, mock.Anything).Return(formulas, nil)
+			},
+			wantContentType: "text/csv; charset=utf-8",
+			wantLines:       2,
+			wantContains:    []string{`"'=HYPERLINK(""http://evil.example"")"`, ",'+1,", ",'-2,", ",'@SUM(A1),", ",'\tcmd,", "'\rcmd"},
+		},
+		{
+			name:    "Format không hỗ trợ",
+			req:     entities.ClickExportRequest{Format: "xlsx"},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidStatsQuery,
+		},
+		{
+			name: "URL không tồn tại",
+			req:  entities.ClickExportRequest{},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrURLNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockURLRepository{}
+			tt.setup(mockRepo)
+
+			usecase := &urlUsecase{
+				urlRepo: mockRepo,
+				baseURL: "http://localhost:8080",
+			}
+
+			export, err := usecase.ExportClicks(WithSystemCaller(context.Background()), "abc123", tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, export)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantContentType, export.ContentType)
+				assert.Contains(t, export.Filename, "clicks-abc123-")
+
+				var buf strings.Builder
+				assert.NoError(t, export.Stream(&buf))
+				assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), tt.wantLines)
+				for _, want := range tt.wantContains {
+					assert.Contains(t, buf.String(), want)
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestURLUsecase_StreamClicks(t *testing.T) {
+	t.Run("Replay từ Last-Event-ID rồi chuyển sang live", func(t *testing.T) {
+		mockRepo := new(MockURLRepository)
+		broker := utils.NewMemoryClickBroker()
+		usecase := &urlUsecase{urlRepo: mockRepo, baseURL: "http://localhost:8080", clicks: broker}
+
+		mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+		mockRepo.On("ListAnalytics", uint(1), entities.ClickFilter{AfterID: 10, Ascending: true, Limit: clickReplayPage}).
+			Return([]entities.Analytics{{ID: 11, URLID: 1}, {ID: 12, URLID: 1}}, nil)
+
+		ctx, cancel := context.WithCancel(WithSystemCaller(context.Background()))
+		defer cancel()
+
+		events, err := usecase.StreamClicks(ctx, "abc123", 10)
+		assert.NoError(t, err)
+
+		// Event đã replay bị bỏ qua; event mới tới không theo thứ tự id vẫn được chuyển tiếp
+		assert.NoError(t, broker.Publish(ctx, &entities.ClickEvent{ID: 12, URLID: 1}))
+		assert.NoError(t, broker.Publish(ctx, &entities.ClickEvent{ID: 14, URLID: 1}))
+		assert.NoError(t, broker.Publish(ctx, &entities.ClickEvent{ID: 13, URLID: 1}))
+
+		var ids []uint
+		for len(ids) < 4 {
+			select {
+			case event := <-events:
+				ids = append(ids, event.ID)
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for events, got %v", ids)
+			}
+		}
+		assert.Equal(t, []uint{11, 12, 14, 13}, ids)
+
+		cancel()
+		for range events {
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Replay nhiều hơn một trang theo thứ tự id tăng dần", func(t *testing.T) {
+		mockRepo := new(MockURLRepository)
+		usecase := &urlUsecase{urlRepo: mockRepo, baseURL: "http://localhost:8080", clicks: utils.NewMemoryClickBroker()}
+
+		// Client lỡ clickReplayPage + 2 click, id 11 trở đi
+		missed := make([]entities.Analytics, clickReplayPage+2)
+		for i := range missed {
+			missed[i] = entities.Analytics{ID: uint(11 + i), URLID: 1}
+		}
+		lastOfPage := missed[clickReplayPage-1].ID
+		mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+		mockRepo.On("ListAnalytics", uint(1), entities.ClickFilter{AfterID: 10, Ascending: true, Limit: clickReplayPage}).
+			Return(missed[:clickReplayPage], nil)
+		mockRepo.On("ListAnalytics", uint(1), entities.ClickFilter{AfterID: lastOfPage, Ascending: true, Limit: clickReplayPage}).
+			Return(missed[clickReplayPage:], nil)
+
+		ctx, cancel := context.WithCancel(WithSystemCaller(context.Background()))
+		defer cancel()
+
+		events, err := usecase.StreamClicks(ctx, "abc123", 10)
+		assert.NoError(t, err)
+
+		var ids []uint
+		for len(ids) < len(missed) {
+			select {
+			case event := <-events:
+				ids = append(ids, event.ID)
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for events, got %d", len(ids))
+			}
+		}
+		for i, id := range ids {
+			assert.Equal(t, missed[i].ID, id)
+		}
+
+		cancel()
+		for range events {
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Client đọc chậm không làm rơi live event", func(t *testing.T) {
+		mockRepo := new(MockURLRepository)
+		broker := utils.NewMemoryClickBroker()
+		usecase := &urlUsecase{urlRepo: mockRepo, baseURL: "http://localhost:8080", clicks: broker}
+		mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+
+		ctx, cancel := context.WithCancel(WithSystemCaller(context.Background()))
+		defer cancel()
+
+		events, err := usecase.StreamClicks(ctx, "abc123", 0)
+		assert.NoError(t, err)
+
+		// Publish nhiều hơn buffer của broker trong khi client chưa đọc
+		const total = 200
+		for id := uint(1); id <= total; id++ {
+			assert.NoError(t, broker.Publish(ctx, &entities.ClickEvent{ID: id, URLID: 1}))
+			if id%20 == 0 {
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+
+		for id := uint(1); id <= total; id++ {
+			select {
+			case event := <-events:
+				assert.Equal(t, id, event.ID)
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for event %d", id)
+			}
+		}
+	})
+
+	t.Run("URL không tồn tại", func(t *testing.T) {
+		mockRepo := new(MockURLRepository)
+		usecase := &urlUsecase{urlRepo: mockRepo, clicks: utils.NewMemoryClickBroker()}
+		mockRepo.On("GetByShortCode", "missing").Return(nil, errors.New("record not found"))
+
+		_, err := usecase.StreamClicks(context.Background(), "missing", 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("Không có broker", func(t *testing.T) {
+		mockRepo := new(MockURLRepository)
+		usecase := &urlUsecase{urlRepo: mockRepo}
+		mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{ID: 1, ShortCode: "abc123"}, nil)
+
+		_, err := usecase.StreamClicks(WithSystemCaller(context.Background()), "abc123", 0)
+		assert.ErrorIs(t, err, ErrClickStreamUnavailable)
+	})
+}
+
+func TestURLUsecase_RedirectPrivacy(t *testing.T) {
+	const ip, ua = "203.0.113.77", "Mozilla/5.0"
+
+	tests := []struct {
+		name        string
+		privacy     config.PrivacyConfig
+		doNotTrack  bool
+		wantIP      string
+		wantTracked bool
+	}{
+		{
+			name:        "Cắt IP về /24",
+			privacy:     config.PrivacyConfig{IPMode: utils.IPModeTruncate, IPHashSecret: "secret", HonorDNT: true},
+			wantIP:      "203.0.113.0",
+			wantTracked: true,
+		},
+		{
+			name:        "Lưu IP dạng hash có salt",
+			privacy:     config.PrivacyConfig{IPMode: utils.IPModeHash, IPHashSecret: "secret", SaltRotation: time.Hour},
+			wantTracked: true,
+		},
+		{
+			name:       "Tôn trọng DNT",
+			privacy:    config.PrivacyConfig{IPMode: utils.IPModeFull, HonorDNT: true},
+			doNotTrack: true,
+		},
+		{
+			name:        "Bỏ qua DNT khi tắt trong config",
+			privacy:     config.PrivacyConfig{IPMode: utils.IPModeFull},
+			doNotTrack:  true,
+			wantIP:      ip,
+			wantTracked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockURLRepository{}
+			mockVisitors := &MockVisitorCounter{}
+			urlEntity := &entities.URL{ID: 1, ShortCode: "abc123", OriginalURL: "https://example.com", IsActive: true}
+			mockRepo.On("GetByShortCode", "abc123").Return(urlEntity, nil)
+			mockRepo.On("IncrementClickCount", "abc123").Return(int64(1), nil)
+
+			var stored *entities.Analytics
+			mockRepo.On("AddAnalytics", mock.AnythingOfType("*entities.Analytics")).
+				Run(func(args mock.Arguments) { stored = args.Get(0).(*entities.Analytics) }).
+				Return(nil)
+			if tt.wantTracked {
+				mockVisitors.On("Add", mock.Anything, uint(1), utils.VisitorFingerprint(ip, ua), mock.AnythingOfType("time.Time")).Return(nil)
+			}
+
+			cfg := getTestConfig()
+			cfg.Privacy = tt.privacy
+			usecase := &urlUsecase{
+				urlRepo:  mockRepo,
+				baseURL:  "http://localhost:8080",
+				visitors: mockVisitors,
+				ipAnon:   newIPAnonymizer(cfg),
+				config:   cfg,
+			}
+
+			_, err := usecase.Redirect(context.Background(), "abc123", ip, ua, "https://www.google.com/search?q=secret", tt.doNotTrack)
+			assert.NoError(t, err)
+
+			// Dimension tổng hợp luôn được giữ lại
+			assert.Equal(t, "google.com", stored.ReferrerHost)
+			if tt.wantTracked {
+				assert.NotEmpty(t, stored.IPAddress)
+				if tt.wantIP != "" {
+					assert.Equal(t, tt.wantIP, stored.IPAddress)
+				} else {
+					assert.Len(t, stored.IPAddress, 32)
+				}
+				assert.Equal(t, ua, stored.UserAgent)
+				// Fingerprint lưu trong DB luôn có salt, không có secret thì không lưu
+				if tt.privacy.IPHashSecret != "" {
+					assert.Len(t, stored.VisitorHash, 64)
+					assert.NotEqual(t, utils.VisitorFingerprint(ip, ua), stored.VisitorHash)
+				} else {
+					assert.Empty(t, stored.VisitorHash)
+				}
+			} else {
+				assert.Empty(t, stored.IPAddress)
+				assert.Empty(t, stored.UserAgent)
+				assert.Empty(t, stored.Referer)
+				assert.Empty(t, stored.VisitorHash)
+			}
+			mockVisitors.AssertExpectations(t)
+		})
+	}
+}
+
+func TestURLUsecase_EraseAnalytics(t *testing.T) {
+	anon := utils.NewIPAnonymizer(utils.IPModeTruncate, "secret", 0)
+	fingerprint := anon.Fingerprint("203.0.113.77", "Mozilla/5.0", time.Now())
+
+	tests := []struct {
+		name    string
+		req     entities.EraseAnalyticsRequest
+		setup   func(*MockURLRepository)
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "Xóa theo IP và User-Agent",
+			req:  entities.EraseAnalyticsRequest{IPAddress: "203.0.113.77", UserAgent: "Mozilla/5.0"},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetOldestClickedAt").Return(nil, nil)
+				mockRepo.On("DeleteAnalytics",
+					mock.MatchedBy(func(ipForms []string) bool {
+						return slices.Contains(ipForms, "203.0.113.77") && !slices.Contains(ipForms, "203.0.113.0")
+					}),
+					mock.MatchedBy(func(fingerprints []string) bool { return slices.Contains(fingerprints, fingerprint) }),
+				).Return(int64(4), nil)
+			},
+			want: 4,
+		},
+		{
+			name: "Xóa theo fingerprint",
+			req:  entities.EraseAnalyticsRequest{Fingerprint: strings.ToUpper(fingerprint)},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("DeleteAnalytics", []string(nil), []string{fingerprint}).Return(int64(2), nil)
+			},
+			want: 2,
+		},
+		{
+			name:    "Thiếu IP và fingerprint",
+			req:     entities.EraseAnalyticsRequest{},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidEraseRequest,
+		},
+		{
+			name:    "IP không hợp lệ",
+			req:     entities.EraseAnalyticsRequest{IPAddress: "not-an-ip"},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidEraseRequest,
+		},
+		{
+			name:    "Fingerprint không hợp lệ",
+			req:     entities.EraseAnalyticsRequest{Fingerprint: "abc"},
+			setup:   func(mockRepo *MockURLRepository) {},
+			wantErr: ErrInvalidEraseRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockURLRepository{}
+			tt.setup(mockRepo)
+
+			usecase := &urlUsecase{urlRepo: mockRepo, ipAnon: anon, config: getTestConfig()}
+
+			got, err := usecase.EraseAnalytics(context.Background(), tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got.DeletedClicks)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestURLUsecase_DeleteURL(t *testing.T) {
+	ownerID, otherID := uint(7), uint(8)
+	errDelete := errors.New("delete failed")
+	tests := []struct {
+		name      string
+		shortCode string
+		apiKey    *entities.APIKey
+		anonymous bool
+		setup     func(*MockURLRepository)
+		wantErr   error
+	}{
+		{
+			name:      "Xóa URL thành công",
+			shortCode: "abc123",
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
+					ShortCode: "abc123",
+					ID:        1,
+				}, nil)
+				mockRepo.On("Delete", uint(1)).Return(nil)
+			},
+		},
+		{
+			name:      "Owner xóa URL của mình",
+			shortCode: "abc123",
+			apiKey:    &entities.APIKey{UserID: &ownerID, Scopes: entities.ScopeLinksWrite},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
+					ShortCode: "abc123",
+					ID:        1,
+					OwnerID:   &ownerID,
+				}, nil)
+				mockRepo.On("Delete", uint(1)).Return(nil)
+			},
+		},
+		{
+			name:      "User khác không được xóa",
+			shortCode: "abc123",
+			apiKey:    &entities.APIKey{UserID: &otherID, Scopes: entities.ScopeLinksWrite},
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
+					ShortCode: "abc123",
+					ID:        1,
+					OwnerID:   &ownerID,
+				}, nil)
+			},
+			wantErr: ErrURLForbidden,
+		},
+		{
+			name:      "Thiếu API key không được xóa",
+			shortCode: "abc123",
+			anonymous: true,
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
+					ShortCode: "abc123",
+					ID:        1,
+					OwnerID:   &ownerID,
+				}, nil)
+			},
+			wantErr: ErrURLForbidden,
+		},
+		{
+			name:      "URL không tồn tại",
+			shortCode: "notfound",
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "notfound").Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrURLNotFound,
+		},
+		{
+			name:      "Xóa URL thất bại",
+			shortCode: "abc123",
+			setup: func(mockRepo *MockURLRepository) {
+				mockRepo.On("GetByShortCode", "abc123").Return(&entities.URL{
+					ShortCode: "abc123",
+					ID:        1,
+				}, nil)
+				mockRepo.On("Delete", uint(1)).Return(errDelete)
+			},
+			wantErr: errDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockURLRepository{}
+			tt.setup(mockRepo)
+
+			usecase := &urlUsecase{
+				urlRepo: mockRepo,
+				baseURL: "http://localhost:8080",
+			}
+
+			ctx := WithSystemCaller(context.Background())
+			switch {
+			case tt.apiKey != nil:
+				ctx = WithAPIKey(context.Background(), tt.apiKey)
+			case tt.anonymous:
+				ctx = context.Background()
+			}
+			err := usecase.DeleteURL(ctx, tt.shortCode)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
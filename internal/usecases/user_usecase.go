@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// Lỗi của user
+var (
+	// ErrInvalidUser trả về khi tên hoặc email của user không hợp lệ
+	ErrInvalidUser = newDomainError(KindValidation, "invalid_user", "invalid user")
+	// ErrUserNotFound trả về khi user không tồn tại
+	ErrUserNotFound = newDomainError(KindNotFound, "user_not_found", "user not found")
+	// ErrUserExists trả về khi tạo user trùng email
+	ErrUserExists = newDomainError(KindConflict, "user_exists", "user already exists")
+)
+
+// maxUserNameLength giới hạn độ dài tên user
+const maxUserNameLength = 100
+
+type IUserUsecase interface {
+	CreateUser(ctx context.Context, req entities.CreateUserRequest) (*entities.User, error)
+	ListUsers(ctx context.Context) (*entities.UserListResponse, error)
+}
+
+type userUsecase struct {
+	userRepo repositories.IUserRepository
+}
+
+// NewUserUsecase tạo user usecase
+func NewUserUsecase(userRepo repositories.IUserRepository) IUserUsecase {
+	return &userUsecase{
+		userRepo: userRepo,
+	}
+}
+
+// CreateUser tạo user; email không phân biệt hoa thường và không được trùng
+func (u *userUsecase) CreateUser(ctx context.Context, req entities.CreateUserRequest) (*entities.User, error) {
+	ctx, span := utils.StartSpan(ctx, "userUsecase.CreateUser")
+	defer span.End()
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxUserNameLength {
+		return nil, ErrInvalidUser.WithMessage("name must be 1-%d characters", maxUserNameLength)
+	}
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = u.userRepo.WithContext(ctx).GetUserByEmail(email)
+	if err == nil {
+		return nil, ErrUserExists.WithMessage("user with email %s already exists", email)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user := &entities.User{Name: name, Email: email}
+	if err := u.userRepo.WithContext(ctx).CreateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+// ListUsers lấy tất cả user
+func (u *userUsecase) ListUsers(ctx context.Context) (*entities.UserListResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "userUsecase.ListUsers")
+	defer span.End()
+
+	users, err := u.userRepo.WithContext(ctx).ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	if users == nil {
+		users = []entities.User{}
+	}
+	return &entities.UserListResponse{Users: users}, nil
+}
+
+// normalizeEmail kiểm tra email dạng addr-spec (không kèm tên hiển thị) và chuyển về chữ thường
+func normalizeEmail(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value || len(value) > 254 {
+		return "", ErrInvalidUser.WithMessage("invalid email %q", value)
+	}
+	return strings.ToLower(value), nil
+}
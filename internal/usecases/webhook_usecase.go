@@ -0,0 +1,411 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/url-shorted2/internal/config"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+	// deliveryBatchSize là số delivery tối đa xử lý trong một lần poll
+	deliveryBatchSize  = 50
+	maxLastErrorLength = 500
+	// defaultPollInterval là chu kỳ poll delivery khi config không hợp lệ
+	defaultPollInterval = 5 * time.Second
+)
+
+// ErrInvalidWebhook trả về khi request đăng ký webhook không hợp lệ
+var ErrInvalidWebhook = newDomainError(KindValidation, "invalid_webhook", "invalid webhook")
+
+// ErrDeliveryNotRetryable trả về khi retry một delivery chưa nằm trong dead-letter
+var ErrDeliveryNotRetryable = newDomainError(KindPrecondition, "delivery_not_retryable", "delivery is not dead-lettered")
+
+// ErrWebhookNotFound trả về khi webhook không tồn tại
+var ErrWebhookNotFound = newDomainError(KindNotFound, "webhook_not_found", "webhook not found")
+
+// ErrDeliveryNotFound trả về khi delivery không tồn tại hoặc không thuộc webhook
+var ErrDeliveryNotFound = newDomainError(KindNotFound, "delivery_not_found", "delivery not found")
+
+var webhookEvents = map[string]bool{
+	entities.WebhookEventURLCreated:     true,
+	entities.WebhookEventURLDeleted:     true,
+	entities.WebhookEventClickMilestone: true,
+}
+
+// IEventPublisher nhận domain event từ urlUsecase
+type IEventPublisher interface {
+	Publish(eventType string, data interface{})
+}
+
+// IWebhookUsecase quản lý webhook và gửi event tới các endpoint đã đăng ký
+type IWebhookUsecase interface {
+	IEventPublisher
+	CreateWebhook(ctx context.Context, req entities.CreateWebhookRequest) (*entities.WebhookResponse, error)
+	ListWebhooks(ctx context.Context) ([]entities.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, webhookID uint, req entities.WebhookDeliveryListRequest) ([]entities.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, webhookID, deliveryID uint) (*entities.WebhookDelivery, error)
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+	Start(ctx context.Context)
+}
+
+type webhookUsecase struct {
+	webhookRepo repositories.IWebhookRepository
+	client      *http.Client
+	config      *config.Config
+}
+
+// NewWebhookUsecase tạo webhook usecase. Event được lưu vào bảng delivery trước khi gửi
+// nên không bị mất khi endpoint lỗi hoặc server khởi động lại.
+func NewWebhookUsecase(webhookRepo repositories.IWebhookRepository, cfg *config.Config) IWebhookUsecase {
+	return &webhookUsecase{
+		webhookRepo: webhookRepo,
+		client:      &http.Client{Timeout: cfg.Webhook.Timeout},
+		config:      cfg,
+	}
+}
+
+// CreateWebhook đăng ký webhook mới. Secret dùng để ký payload chỉ trả về một lần.
+func (u *webhookUsecase) CreateWebhook(ctx context.Context, req entities.CreateWebhookRequest) (*entities.WebhookResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "webhookUsecase.CreateWebhook")
+	defer span.End()
+
+	parsedURL, err := url.Parse(req.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, ErrInvalidWebhook.WithMessage("url must be an absolute http(s) URL")
+	}
+
+	events := make([]string, 0, len(req.Events))
+	seen := make(map[string]bool)
+	for _, event := range req.Events {
+		if !webhookEvents[event] {
+			return nil, ErrInvalidWebhook.WithMessage("unknown event %q", event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &entities.Webhook{
+		URL:      req.URL,
+		Secret:   secret,
+		Events:   strings.Join(events, ","),
+		IsActive: true,
+	}
+	if err := u.repo(ctx).CreateWebhook(webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	response := toWebhookResponse(webhook)
+	response.Secret = secret
+	return &response, nil
+}
+
+// ListWebhooks lấy danh sách webhook đã đăng ký
+func (u *webhookUsecase) ListWebhooks(ctx context.Context) ([]entities.WebhookResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "webhookUsecase.ListWebhooks")
+	defer span.End()
+
+	webhooks, err := u.repo(ctx).ListWebhooks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	responses := make([]entities.WebhookResponse, 0, len(webhooks))
+	for i := range webhooks {
+		responses = append(responses, toWebhookResponse(&webhooks[i]))
+	}
+	return responses, nil
+}
+
+// DeleteWebhook xóa webhook cùng hàng đợi delivery của nó
+func (u *webhookUsecase) DeleteWebhook(ctx context.Context, id uint) error {
+	ctx, span := utils.StartSpan(ctx, "webhookUsecase.DeleteWebhook")
+	defer span.End()
+
+	if err := u.repo(ctx).DeleteWebhook(id); err != nil {
+		return notFoundError(err, ErrWebhookNotFound, "failed to delete webhook")
+	}
+	return nil
+}
+
+// ListDeliveries lấy log delivery của webhook. Lọc status=dead để xem dead-letter.
+func (u *webhookUsecase) ListDeliveries(ctx context.Context, webhookID uint, req entities.WebhookDeliveryListRequest) ([]entities.WebhookDelivery, error) {
+	ctx, span := utils.StartSpan(ctx, "webhookUsecase.ListDeliveries")
+	defer span.End()
+
+	switch req.Status {
+	case "", entities.DeliveryStatusPending, entities.DeliveryStatusSucceeded, entities.DeliveryStatusDead:
+	default:
+		return nil, ErrInvalidWebhook.WithMessage("unknown status %q", req.Status)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	if limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+
+	if _, err := u.repo(ctx).GetWebhook(webhookID); err != nil {
+		return nil, notFoundError(err, ErrWebhookNotFound, "failed to get webhook")
+	}
+
+	deliveries, err := u.repo(ctx).ListDeliveries(webhookID, req.Status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery đưa một delivery trong dead-letter trở lại hàng đợi
+func (u *webhookUsecase) RetryDelivery(ctx context.Context, webhookID, deliveryID uint) (*entities.WebhookDelivery, error) {
+	ctx, span := utils.StartSpan(ctx, "webhookUsecase.RetryDelivery")
+	defer span.End()
+
+	delivery, err := u.repo(ctx).GetDelivery(webhookID, deliveryID)
+	if err != nil {
+		return nil, notFoundError(err, ErrDeliveryNotFound, "failed to get delivery")
+	}
+	if delivery.Status != entities.DeliveryStatusDead {
+		return nil, ErrDeliveryNotRetryable
+	}
+
+	delivery.Status = entities.DeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	if err := u.repo(ctx).UpdateDelivery(delivery); err != nil {
+		return nil, fmt.Errorf("failed to requeue delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// Publish đưa event vào hàng đợi của mọi webhook đang đăng ký event đó.
+// Lỗi chỉ được log để không làm hỏng thao tác phát sinh event.
+func (u *webhookUsecase) Publish(eventType string, data interface{}) {
+	webhooks, err := u.webhookRepo.ListActiveWebhooks()
+	if err != nil {
+		fmt.Printf("Failed to list webhooks for %s: %v\n", eventType, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	event := entities.WebhookEvent{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		fmt.Printf("Failed to encode webhook event %s: %v\n", eventType, err)
+		return
+	}
+
+	var deliveries []entities.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !subscribesTo(&webhook, eventType) {
+			continue
+		}
+		deliveries = append(deliveries, entities.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        entities.DeliveryStatusPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	if err := u.webhookRepo.EnqueueDeliveries(deliveries); err != nil {
+		fmt.Printf("Failed to enqueue webhook event %s: %v\n", eventType, err)
+	}
+}
+
+// Start gửi các delivery tới hạn theo PollInterval cho tới khi ctx kết thúc
+func (u *webhookUsecase) Start(ctx context.Context) {
+	ticker := time.NewTicker(u.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		if _, err := u.DeliverDue(ctx, time.Now()); err != nil {
+			fmt.Printf("Webhook delivery failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// repo trả về webhook repository chạy với ctx của request
+func (u *webhookUsecase) repo(ctx context.Context) repositories.IWebhookRepository {
+	return u.webhookRepo.WithContext(ctx)
+}
+
+// pollInterval trả về chu kỳ poll theo config, giá trị <= 0 dùng mặc định
+func (u *webhookUsecase) pollInterval() time.Duration {
+	if u.config == nil || u.config.Webhook.PollInterval <= 0 {
+		return defaultPollInterval
+	}
+	return u.config.Webhook.PollInterval
+}
+
+// DeliverDue gửi tối đa deliveryBatchSize delivery pending đã tới hạn, trả về số delivery đã xử lý.
+// Mỗi delivery được claim ngay trước khi gửi nên lease chỉ cần phủ một lần gửi.
+func (u *webhookUsecase) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := utils.StartSpan(ctx, "webhookUsecase.DeliverDue")
+	defer span.End()
+
+	// Lease dài hơn timeout để instance khác không nhận lại delivery đang gửi
+	lease := 2 * u.config.Webhook.Timeout
+	start := time.Now()
+
+	webhooks := make(map[uint]*entities.Webhook)
+	processed := 0
+	for processed < deliveryBatchSize {
+		// Lease tính từ lúc claim: cộng thời gian đã gửi các delivery trước trong lần poll này
+		deliveries, err := u.repo(ctx).ClaimDueDeliveries(now, lease+time.Since(start), 1)
+		if err != nil {
+			return processed, fmt.Errorf("failed to claim deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			break
+		}
+
+		delivery := &deliveries[0]
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = u.repo(ctx).GetWebhook(delivery.WebhookID)
+			if err != nil {
+				return processed, fmt.Errorf("failed to get webhook %d: %w", delivery.WebhookID, err)
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		u.attempt(ctx, webhook, delivery)
+		if err := u.repo(ctx).UpdateDelivery(delivery); err != nil {
+			return processed, fmt.Errorf("failed to update delivery %d: %w", delivery.ID, err)
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// attempt gửi một delivery và cập nhật trạng thái theo kết quả.
+// Lần retry được hẹn từ lúc gửi xong, không từ lúc bắt đầu poll, để delivery cuối batch không bị retry sớm.
+func (u *webhookUsecase) attempt(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) {
+	delivery.Attempts++
+	statusCode, err := u.send(ctx, webhook, delivery)
+	delivery.LastStatusCode = statusCode
+
+	if err == nil {
+		deliveredAt := time.Now().UTC()
+		delivery.Status = entities.DeliveryStatusSucceeded
+		delivery.DeliveredAt = &deliveredAt
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > maxLastErrorLength {
+		delivery.LastError = delivery.LastError[:maxLastErrorLength]
+	}
+	if delivery.Attempts >= u.config.Webhook.MaxAttempts {
+		delivery.Status = entities.DeliveryStatusDead
+		return
+	}
+	delivery.NextAttemptAt = time.Now().Add(u.backoff(delivery.Attempts)).UTC()
+}
+
+// send POST payload đã ký tới webhook, trả về lỗi nếu status không phải 2xx
+func (u *webhookUsecase) send(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "url-shortener-webhook/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", delivery.EventID)
+	req.Header.Set(utils.WebhookSignatureHeader, utils.SignWebhookPayload(webhook.Secret, time.Now(), delivery.Payload))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Đọc bỏ body (có giới hạn) để connection được tái sử dụng
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff tính thời gian chờ trước lần gửi tiếp theo: base * 2^(attempts-1), tối đa BackoffMax
+func (u *webhookUsecase) backoff(attempts int) time.Duration {
+	delay := u.config.Webhook.BackoffBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= u.config.Webhook.BackoffMax {
+			return u.config.Webhook.BackoffMax
+		}
+	}
+	return delay
+}
+
+// subscribesTo kiểm tra webhook có đăng ký event không
+func subscribesTo(webhook *entities.Webhook, eventType string) bool {
+	for _, event := range strings.Split(webhook.Events, ",") {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func toWebhookResponse(webhook *entities.Webhook) entities.WebhookResponse {
+	return entities.WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    strings.Split(webhook.Events, ","),
+		IsActive:  webhook.IsActive,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// generateWebhookSecret tạo secret ngẫu nhiên 32 byte dạng hex
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,306 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/domain/repositories"
+	"github.com/url-shorted2/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// Lỗi của workspace
+var (
+	// ErrInvalidWorkspace trả về khi tên, settings, role hoặc owner của workspace không hợp lệ
+	ErrInvalidWorkspace = newDomainError(KindValidation, "invalid_workspace", "invalid workspace")
+	// ErrWorkspaceNotFound trả về khi workspace không tồn tại
+	ErrWorkspaceNotFound = newDomainError(KindNotFound, "workspace_not_found", "workspace not found")
+	// ErrWorkspaceForbidden trả về khi người gọi không có role đủ cao trong workspace
+	ErrWorkspaceForbidden = newDomainError(KindForbidden, "workspace_forbidden", "insufficient workspace role")
+	// ErrMemberNotFound trả về khi user không phải member của workspace
+	ErrMemberNotFound = newDomainError(KindNotFound, "member_not_found", "workspace member not found")
+	// ErrMemberExists trả về khi mời user đã là member
+	ErrMemberExists = newDomainError(KindConflict, "member_exists", "user is already a workspace member")
+	// ErrLastWorkspaceOwner trả về khi xóa hoặc hạ role owner cuối cùng của workspace
+	ErrLastWorkspaceOwner = newDomainError(KindPrecondition, "last_workspace_owner", "workspace must keep at least one owner")
+)
+
+// Giới hạn độ dài tên workspace và giá trị UTM, khớp với cột trong DB
+const (
+	maxWorkspaceNameLength = 100
+	maxUTMValueLength      = 100
+)
+
+type IWorkspaceUsecase interface {
+	CreateWorkspace(ctx context.Context, req entities.CreateWorkspaceRequest) (*entities.Workspace, error)
+	ListWorkspaces(ctx context.Context) (*entities.WorkspaceListResponse, error)
+	GetWorkspace(ctx context.Context, id uint) (*entities.Workspace, error)
+	UpdateSettings(ctx context.Context, id uint, settings entities.WorkspaceSettings) (*entities.Workspace, error)
+	ListMembers(ctx context.Context, id uint) (*entities.WorkspaceMemberListResponse, error)
+	InviteMember(ctx context.Context, id uint, req entities.InviteMemberRequest) (*entities.WorkspaceMember, error)
+	UpdateMember(ctx context.Context, id, userID uint, req entities.UpdateMemberRequest) (*entities.WorkspaceMember, error)
+	RemoveMember(ctx context.Context, id, userID uint) error
+}
+
+type workspaceUsecase struct {
+	workspaceRepo repositories.IWorkspaceRepository
+	userRepo      repositories.IUserRepository
+}
+
+// NewWorkspaceUsecase tạo workspace usecase
+func NewWorkspaceUsecase(workspaceRepo repositories.IWorkspaceRepository, userRepo repositories.IUserRepository) IWorkspaceUsecase {
+	return &workspaceUsecase{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// CreateWorkspace tạo workspace với owner là user của API key, hoặc owner_id khi người gọi có scope admin
+func (u *workspaceUsecase) CreateWorkspace(ctx context.Context, req entities.CreateWorkspaceRequest) (*entities.Workspace, error) {
+	ctx, span := utils.StartSpan(ctx, "workspaceUsecase.CreateWorkspace")
+	defer span.End()
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxWorkspaceNameLength {
+		return nil, ErrInvalidWorkspace.WithMessage("name must be 1-%d characters", maxWorkspaceNameLength)
+	}
+
+	ownerID := callerUserID(ctx)
+	if req.OwnerID != nil {
+		if !isAdminCaller(ctx) && (ownerID == nil || *ownerID != *req.OwnerID) {
+			return nil, ErrInsufficientScope.WithMessage("creating a workspace for another user requires admin scope")
+		}
+		ownerID = req.OwnerID
+	}
+	if ownerID == nil {
+		return nil, ErrInvalidWorkspace.WithMessage("owner_id is required for API keys without a user")
+	}
+	if _, err := u.userRepo.WithContext(ctx).GetUser(*ownerID); err != nil {
+		return nil, notFoundError(err, ErrUserNotFound.WithMessage("user %d not found", *ownerID), "failed to get user")
+	}
+
+	workspace := &entities.Workspace{
+		Name:     name,
+		Settings: entities.WorkspaceSettings{DefaultRedirectType: entities.DefaultRedirectType},
+	}
+	owner := &entities.WorkspaceMember{UserID: *ownerID, Role: entities.WorkspaceRoleOwner}
+	if err := u.workspaceRepo.WithContext(ctx).CreateWorkspace(workspace, owner); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// ListWorkspaces lấy workspace người gọi là member; admin thấy mọi workspace
+func (u *workspaceUsecase) ListWorkspaces(ctx context.Context) (*entities.WorkspaceListResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "workspaceUsecase.ListWorkspaces")
+	defer span.End()
+
+	var userID *uint
+	if !isAdminCaller(ctx) {
+		if userID = callerUserID(ctx); userID == nil {
+			return &entities.WorkspaceListResponse{Workspaces: []entities.Workspace{}}, nil
+		}
+	}
+	workspaces, err := u.workspaceRepo.WithContext(ctx).ListWorkspaces(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	if workspaces == nil {
+		workspaces = []entities.Workspace{}
+	}
+	return &entities.WorkspaceListResponse{Workspaces: workspaces}, nil
+}
+
+// GetWorkspace lấy workspace kèm settings, cần role viewer
+func (u *workspaceUsecase) GetWorkspace(ctx context.Context, id uint) (*entities.Workspace, error) {
+	ctx, span := utils.StartSpan(ctx, "workspaceUsecase.GetWorkspace")
+	defer span.End()
+
+	return u.findWorkspace(ctx, id, entities.WorkspaceRoleViewer)
+}
+
+// UpdateSettings thay toàn bộ settings của workspace, cần role owner
+func (u *workspaceUsecase) UpdateSettings(ctx context.Context, id uint, settings entities.WorkspaceSettings) (*entities.Workspace, error) {
+	ctx, span := utils.StartSpan(ctx, "workspaceUsecase.UpdateSettings")
+	defer span.End()
+
+	workspace, err := u.findWorkspace(ctx, id, entities.WorkspaceRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if settings.DefaultRedirectType == 0 {
+		settings.DefaultRedirectType = entities.DefaultRedirectType
+	}
+	if !slices.Contains(entities.RedirectTypes, settings.DefaultRedirectType) {
+		return nil, ErrInvalidWorkspace.WithMessage("default_redirect_type must be one of %v", entities.RedirectTypes)
+	}
+	if settings.DefaultUTM, err = normalizeUTM(settings.DefaultUTM); err != nil {
+		return nil, err
+	}
+
+	workspace.Settings = settings
+	if err := u.workspaceRepo.WithContext(ctx).UpdateWorkspace(workspace); err != nil {
+		return nil, fmt.Errorf("failed to update workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// ListMembers lấy member của workspace, cần role viewer
+func (u *workspaceUsecase) ListMembers(ctx context.Context, id uint) (*entities.WorkspaceMemberListResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "workspaceUsecase.ListMembers")
+	defer span.End()
+
+	if _, err := u.findWorkspace(ctx, id, entities.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	members, err := u.workspaceRepo.WithContext(ctx).ListMembers(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace members: %w", err)
+	}
+	if members == nil {
+		members = []entities.WorkspaceMember{}
+	}
+	return &entities.WorkspaceMemberListResponse{Members: members}, nil
+}
+
+// InviteMember thêm user đã có tài khoản (theo email) vào workspace với role, cần role owner
+func (u *workspaceUsecase) InviteMember(ctx context.Context, id uint, req entities.InviteMemberRequest) (*entities.WorkspaceMember, error) {
+	ctx, span := utils.StartSpan(ctx, "workspaceUsecase.InviteMember")
+	defer span.End()
+
+	if _, err := u.findWorkspace(ctx, id, entities.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+	if err := validateWorkspaceRole(req.Role); err != nil {
+		return nil, err
+	}
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userRepo.WithContext(ctx).GetUserByEmail(email)
+	if err != nil {
+		return nil, notFoundError(err, ErrUserNotFound.WithMessage("no user with email %s", email), "failed to get user")
+	}
+
+	_, err = u.workspaceRepo.WithContext(ctx).GetMember(id, user.ID)
+	if err == nil {
+		return nil, ErrMemberExists.WithMessage("%s is already a member of workspace %d", email, id)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get workspace member: %w", err)
+	}
+
+	member := &entities.WorkspaceMember{WorkspaceID: id, UserID: user.ID, Role: req.Role}
+	if err := u.workspaceRepo.WithContext(ctx).CreateMember(member); err != nil {
+		return nil, fmt.Errorf("failed to create workspace member: %w", err)
+	}
+	member.User = user
+	return member, nil
+}
+
+// UpdateMember đổi role của member, cần role owner. Không hạ role owner cuối cùng.
+func (u *workspaceUsecase) UpdateMember(ctx context.Context, id, userID uint, req entities.UpdateMemberRequest) (*entities.WorkspaceMember, error) {
+	ctx, span := utils.StartSpan(ctx, "workspaceUsecase.UpdateMember")
+	defer span.End()
+
+	if _, err := u.findWorkspace(ctx, id, entities.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+	if err := validateWorkspaceRole(req.Role); err != nil {
+		return nil, err
+	}
+	member, err := u.findMember(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == req.Role {
+		return member, nil
+	}
+
+	previous := member.Role
+	member.Role = req.Role
+	if err := u.workspaceRepo.WithContext(ctx).UpdateMember(member); err != nil {
+		return nil, memberWriteError(err, previous, "failed to update workspace member")
+	}
+	return member, nil
+}
+
+// RemoveMember xóa member khỏi workspace; owner xóa được mọi member, member tự rời được workspace.
+// Link của member vẫn thuộc workspace.
+func (u *workspaceUsecase) RemoveMember(ctx context.Context, id, userID uint) error {
+	ctx, span := utils.StartSpan(ctx, "workspaceUsecase.RemoveMember")
+	defer span.End()
+
+	role := entities.WorkspaceRoleOwner
+	if callerID := callerUserID(ctx); callerID != nil && *callerID == userID {
+		role = entities.WorkspaceRoleViewer
+	}
+	if _, err := u.findWorkspace(ctx, id, role); err != nil {
+		return err
+	}
+	member, err := u.findMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.workspaceRepo.WithContext(ctx).DeleteMember(id, userID); err != nil {
+		return memberWriteError(err, member.Role, "failed to delete workspace member")
+	}
+	return nil
+}
+
+// findWorkspace lấy workspace và kiểm tra người gọi có role tối thiểu role
+func (u *workspaceUsecase) findWorkspace(ctx context.Context, id uint, role string) (*entities.Workspace, error) {
+	workspace, err := u.workspaceRepo.WithContext(ctx).GetWorkspace(id)
+	if err != nil {
+		return nil, notFoundError(err, ErrWorkspaceNotFound, "failed to get workspace")
+	}
+	if err := requireWorkspaceRole(ctx, u.workspaceRepo, id, role, ErrWorkspaceForbidden); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// findMember lấy member của workspace; user không phải member trả về ErrMemberNotFound
+func (u *workspaceUsecase) findMember(ctx context.Context, id, userID uint) (*entities.WorkspaceMember, error) {
+	member, err := u.workspaceRepo.WithContext(ctx).GetMember(id, userID)
+	if err != nil {
+		return nil, notFoundError(err, ErrMemberNotFound.WithMessage("user %d is not a member of workspace %d", userID, id), "failed to get workspace member")
+	}
+	return member, nil
+}
+
+// memberWriteError chuyển lỗi khi đổi role hoặc xóa member. Repository không ghi dòng nào khi member
+// là owner cuối cùng (kiểm tra trong cùng câu lệnh) hoặc member vừa bị xóa.
+func memberWriteError(err error, role, action string) error {
+	if role == entities.WorkspaceRoleOwner {
+		return notFoundError(err, ErrLastWorkspaceOwner, action)
+	}
+	return notFoundError(err, ErrMemberNotFound, action)
+}
+
+// validateWorkspaceRole kiểm tra role thuộc owner, editor, analyst, viewer
+func validateWorkspaceRole(role string) error {
+	if !entities.IsValidWorkspaceRole(role) {
+		return ErrInvalidWorkspace.WithMessage("role must be one of owner, editor, analyst, viewer")
+	}
+	return nil
+}
+
+// normalizeUTM bỏ khoảng trắng thừa và kiểm tra độ dài từng giá trị UTM
+func normalizeUTM(utm entities.UTMParams) (entities.UTMParams, error) {
+	for _, value := range []*string{&utm.Source, &utm.Medium, &utm.Campaign, &utm.Term, &utm.Content} {
+		*value = strings.TrimSpace(*value)
+		if utf8.RuneCountInString(*value) > maxUTMValueLength {
+			return utm, ErrInvalidWorkspace.WithMessage("UTM values must be at most %d characters", maxUTMValueLength)
+		}
+	}
+	return utm, nil
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/url-shorted2/internal/domain/entities"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// subscriberBuffer là kích thước buffer của channel mỗi subscriber. Subscriber đọc không kịp
+// làm đầy buffer thì event mới bị bỏ, nên subscriber cần đọc liên tục và tự xếp hàng nếu xử lý chậm.
+const subscriberBuffer = 64
+
+// IClickBroker fan-out click event tới các subscriber của từng link
+type IClickBroker interface {
+	Publish(ctx context.Context, event *entities.ClickEvent) error
+	// Subscribe trả về channel nhận event của urlID, channel bị đóng khi ctx kết thúc
+	Subscribe(ctx context.Context, urlID uint) (<-chan entities.ClickEvent, error)
+}
+
+type redisClickBroker struct {
+	prefix string
+	client *redis.Client
+}
+
+// NewRedisClickBroker tạo broker dùng Redis pub/sub để fan-out giữa nhiều instance
+func NewRedisClickBroker(rclient *redis.Client) IClickBroker {
+	return &redisClickBroker{
+		prefix: "clicks",
+		client: rclient,
+	}
+}
+
+func (b *redisClickBroker) Publish(ctx context.Context, event *entities.ClickEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel(event.URLID), payload).Err()
+}
+
+func (b *redisClickBroker) Subscribe(ctx context.Context, urlID uint) (<-chan entities.ClickEvent, error) {
+	pubsub := b.client.Subscribe(ctx, b.channel(urlID))
+	// Chờ Redis xác nhận subscription để không mất event publish ngay sau khi trả về
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	out := make(chan entities.ClickEvent, subscriberBuffer)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event entities.ClickEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					log.Printf("Invalid click event on %s: %v\n", msg.Channel, err)
+					continue
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (b *redisClickBroker) channel(urlID uint) string {
+	return fmt.Sprintf("%s:%d", b.prefix, urlID)
+}
+
+// memoryClickBroker là broker in-process, dùng cho test hoặc khi chạy một instance
+type memoryClickBroker struct {
+	mu   sync.RWMutex
+	subs map[uint]map[chan entities.ClickEvent]struct{}
+}
+
+// NewMemoryClickBroker tạo broker in-memory
+func NewMemoryClickBroker() IClickBroker {
+	return &memoryClickBroker{
+		subs: make(map[uint]map[chan entities.ClickEvent]struct{}),
+	}
+}
+
+func (b *memoryClickBroker) Publish(ctx context.Context, event *entities.ClickEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subs[event.URLID] {
+		// Bỏ qua subscriber chậm thay vì block redirect
+		select {
+		case ch <- *event:
+		default:
+		}
+	}
+	return nil
+}
+
+func (b *memoryClickBroker) Subscribe(ctx context.Context, urlID uint) (<-chan entities.ClickEvent, error) {
+	ch := make(chan entities.ClickEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subs[urlID] == nil {
+		b.subs[urlID] = make(map[chan entities.ClickEvent]struct{})
+	}
+	b.subs[urlID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs[urlID], ch)
+		if len(b.subs[urlID]) == 0 {
+			delete(b.subs, urlID)
+		}
+		close(ch)
+		b.mu.Unlock()
+	}()
+	return ch, nil
+}
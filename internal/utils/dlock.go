@@ -73,21 +73,26 @@ func mustId() string {
 func (rlock *redisLock) Lock(ctx context.Context, key string) (*LockData, error) {
 	val := mustId()
 	var ld = &LockData{Key: key, Value: val}
+	start := time.Now()
 	ctxEx, cancel := context.WithTimeout(context.TODO(), rlock.config.MaxTryTime)
 	defer cancel()
 
 	for {
 		select {
 		case <-ctxEx.Done():
+			LockWaitDuration.WithLabelValues(LockTimeout).Observe(time.Since(start).Seconds())
+			LockTimeoutsTotal.Inc()
 			return nil, ErrTimeout
 
 		case <-time.After(20 * time.Millisecond):
 			success, err := rlock.tryLock(ctx, ld)
 			if nil != err {
+				LockWaitDuration.WithLabelValues(LockError).Observe(time.Since(start).Seconds())
 				return nil, err
 			}
 
 			if success {
+				LockWaitDuration.WithLabelValues(LockAcquired).Observe(time.Since(start).Seconds())
 				return ld, nil
 			}
 		}
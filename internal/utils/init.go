@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -46,5 +48,10 @@ func GetRedisWithConfig(redisUrl string, poolSize, minIdleConns, maxRetries int,
 	opts.WriteTimeout = writeTimeout
 	opts.PoolTimeout = poolTimeout
 
-	return redis.NewClient(opts)
+	client := redis.NewClient(opts)
+	// Tạo span cho mỗi lệnh Redis (lock, pub/sub, HyperLogLog)
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		fmt.Printf("Failed to instrument Redis tracing: %v\n", err)
+	}
+	return client
 }
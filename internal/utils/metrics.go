@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "url_shortener"
+
+// Kết quả redirect
+const (
+	RedirectHit  = "hit"
+	RedirectMiss = "miss"
+)
+
+// Kết quả lấy lock
+const (
+	LockAcquired = "acquired"
+	LockTimeout  = "timeout"
+	LockError    = "error"
+)
+
+// MetricsRegistry chứa mọi metric của service, expose qua /metrics
+var MetricsRegistry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestsTotal đếm request theo method, route template và status
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration đo latency theo method, route template và status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"method", "route", "status"})
+
+	// RedirectsTotal đếm redirect tìm thấy (hit) và không tìm thấy/inactive (miss)
+	RedirectsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "redirects_total",
+		Help:      "Total number of redirects by result (hit or miss).",
+	}, []string{"result"})
+
+	// LockWaitDuration đo thời gian chờ lấy distributed lock
+	LockWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "lock_wait_seconds",
+		Help:      "Time spent waiting for the distributed lock by result.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"result"})
+
+	// LockTimeoutsTotal đếm số lần lấy lock bị timeout
+	LockTimeoutsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "lock_timeouts_total",
+		Help:      "Total number of distributed lock acquisitions that timed out.",
+	})
+
+	// AnalyticsWritesInFlight là số lệnh ghi click vào bảng analytics đang chạy (ghi đồng bộ trong redirect)
+	AnalyticsWritesInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "analytics_writes_in_flight",
+		Help:      "Number of click analytics inserts currently in progress.",
+	})
+)
+
+func init() {
+	MetricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		RedirectsTotal,
+		LockWaitDuration,
+		LockTimeoutsTotal,
+		AnalyticsWritesInFlight,
+	)
+}
+
+// RegisterDBStats expose thống kê connection pool của database (sql.DB.Stats)
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return MetricsRegistry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// MetricsHandler trả về HTTP handler xuất metric theo Prometheus text format
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(MetricsRegistry, promhttp.HandlerOpts{})
+}
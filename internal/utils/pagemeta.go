@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// metadataMaxRedirects giới hạn số lần redirect khi fetch trang đích
+const metadataMaxRedirects = 5
+
+// metadataUserAgent nhận diện bot với trang đích, giống cách các link preview bot khác làm
+const metadataUserAgent = "Mozilla/5.0 (compatible; url-shortener-preview/1.0)"
+
+// ErrPrivateHost trả về khi host phân giải ra IP thuộc deniedPrefixes (chống SSRF)
+var ErrPrivateHost = errors.New("destination resolves to a private address")
+
+// ErrNotHTML trả về khi trang đích không phải HTML
+var ErrNotHTML = errors.New("destination is not an HTML page")
+
+// PageMetadata là thông tin preview của một trang HTML; các URL đã được resolve thành tuyệt đối
+type PageMetadata struct {
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+	FaviconURL  string
+}
+
+// MetadataFetcher tải phần <head> của trang đích với timeout và giới hạn kích thước
+type MetadataFetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+// NewMetadataFetcher tạo fetcher. allowPrivate=false chặn kết nối tới IP nội bộ ở bước dial,
+// sau khi DNS đã phân giải, nên redirect hay DNS rebinding cũng không vượt qua được.
+func NewMetadataFetcher(timeout time.Duration, maxBytes int64, allowPrivate bool) *MetadataFetcher {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = denyPrivateAddress
+	}
+	transport := &http.Transport{
+		// Không dùng proxy từ môi trường: kiểm tra IP ở bước dial sẽ chỉ thấy IP của proxy
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &MetadataFetcher{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= metadataMaxRedirects {
+					return fmt.Errorf("stopped after %d redirects", metadataMaxRedirects)
+				}
+				return nil
+			},
+		},
+		maxBytes: maxBytes,
+	}
+}
+
+// Fetch tải trang đích và parse metadata. Chỉ đọc tối đa maxBytes, đủ cho <head> của hầu hết trang.
+func (f *MetadataFetcher) Fetch(ctx context.Context, rawURL string) (*PageMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", metadataUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && errors.Is(opErr.Err, ErrPrivateHost) {
+			return nil, ErrPrivateHost
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("%w (%s)", ErrNotHTML, contentType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBytes), contentType)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePageMetadata(body, resp.Request.URL), nil
+}
+
+// ParsePageMetadata đọc <title>, meta description, og:* và link icon trong <head>.
+// og:title, og:description được ưu tiên hơn <title>, meta description. Favicon mặc định là /favicon.ico.
+func ParsePageMetadata(r io.Reader, base *url.URL) *PageMetadata {
+	var title, ogTitle, description, ogDescription string
+	meta := &PageMetadata{}
+
+	z := html.NewTokenizer(r)
+	for done := false; !done; {
+		switch z.Next() {
+		case html.ErrorToken:
+			// EOF hoặc chạm giới hạn kích thước: dùng những gì đã đọc được
+			done = true
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			switch token.Data {
+			case "body":
+				done = true
+			case "title":
+				if title == "" && z.Next() == html.TextToken {
+					title = string(z.Text())
+				}
+			case "meta":
+				key := strings.ToLower(attr(token, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(token, "name"))
+				}
+				content := attr(token, "content")
+				switch key {
+				case "og:title":
+					ogTitle = firstNonEmpty(ogTitle, content)
+				case "og:description":
+					ogDescription = firstNonEmpty(ogDescription, content)
+				case "description":
+					description = firstNonEmpty(description, content)
+				case "og:image", "og:image:url", "og:image:secure_url":
+					meta.ImageURL = firstNonEmpty(meta.ImageURL, resolveURL(base, content))
+				case "og:site_name":
+					meta.SiteName = firstNonEmpty(meta.SiteName, cleanText(content))
+				}
+			case "link":
+				for _, rel := range strings.Fields(strings.ToLower(attr(token, "rel"))) {
+					if rel == "icon" && meta.FaviconURL == "" {
+						meta.FaviconURL = resolveURL(base, attr(token, "href"))
+					}
+				}
+			}
+		}
+	}
+
+	meta.Title = cleanText(firstNonEmpty(ogTitle, title))
+	meta.Description = cleanText(firstNonEmpty(ogDescription, description))
+	if meta.FaviconURL == "" && base != nil {
+		meta.FaviconURL = resolveURL(base, "/favicon.ico")
+	}
+	return meta
+}
+
+// deniedPrefixes là các dải không phải địa chỉ public unicast (IANA special-purpose registry).
+// Dải IPv6 nhúng IPv4 (NAT64, 6to4, Teredo) bị chặn toàn bộ vì có thể trỏ tới IPv4 nội bộ.
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("10.0.0.0/8"),      // private
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local, metadata của cloud
+	netip.MustParsePrefix("172.16.0.0/12"),   // private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, broadcast
+	netip.MustParsePrefix("::/128"),          // unspecified
+	netip.MustParsePrefix("::1/128"),         // loopback
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // NAT64 local-use
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2001::/32"),       // Teredo
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+// isDeniedAddress cho biết ip thuộc deniedPrefixes; IPv4-mapped (::ffff:a.b.c.d) được so như IPv4
+func isDeniedAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// denyPrivateAddress là Control của net.Dialer, chạy với IP đã phân giải trước khi kết nối
+func denyPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || ip.Zone() != "" || isDeniedAddress(ip) {
+		return ErrPrivateHost
+	}
+	return nil
+}
+
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// resolveURL resolve href theo URL của trang, chỉ giữ http(s)
+func resolveURL(base *url.URL, href string) string {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || href == "" {
+		return ""
+	}
+	if base != nil {
+		ref = base.ResolveReference(ref)
+	}
+	if ref.Scheme != "http" && ref.Scheme != "https" {
+		return ""
+	}
+	return ref.String()
+}
+
+// cleanText gộp khoảng trắng (xuống dòng, tab) thành một dấu cách
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageMetadata(t *testing.T) {
+	base, _ := url.Parse("https://shop.example.com/sale/tv?ref=1")
+
+	tests := []struct {
+		name string
+		html string
+		want PageMetadata
+	}{
+		{
+			name: "Open Graph được ưu tiên hơn title và description",
+			html: `<html><head>
+				<title>Fallback</title>
+				<meta name="description" content="Plain description">
+				<meta property="og:title" content="Black Friday TV">
+				<meta property="og:description" content="50% off &amp; free shipping">
+				<meta property="og:image" content="/img/tv.png">
+				<meta property="og:site_name" content="Shop">
+				<link rel="shortcut icon" href="https://cdn.example.com/favicon.png">
+			</head><body></body></html>`,
+			want: PageMetadata{
+				Title:       "Black Friday TV",
+				Description: "50% off & free shipping",
+				ImageURL:    "https://shop.example.com/img/tv.png",
+				SiteName:    "Shop",
+				FaviconURL:  "https://cdn.example.com/favicon.png",
+			},
+		},
+		{
+			name: "Chỉ có title, favicon mặc định",
+			html: "<title>\n  Hello\n  world </title><meta name=description content='Desc'>",
+			want: PageMetadata{
+				Title:       "Hello world",
+				Description: "Desc",
+				FaviconURL:  "https://shop.example.com/favicon.ico",
+			},
+		},
+		{
+			name: "Bỏ qua thẻ trong body và URL không phải http(s)",
+			html: `<head><meta property="og:image" content="javascript:alert(1)"></head>
+				<body><title>Not the title</title><meta property="og:title" content="Nope"></body>`,
+			want: PageMetadata{FaviconURL: "https://shop.example.com/favicon.ico"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParsePageMetadata(strings.NewReader(tt.html), base)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
+
+func TestMetadataFetcher(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>Landing</title><link rel="icon" href="/icon.svg"></head></html>`))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/latin1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		w.Write([]byte("<title>Caf\xe9</title>"))
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<head>" + strings.Repeat("<meta name=x content=y>", 1000) + "<title>Too late</title>"))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fetcher := NewMetadataFetcher(300*time.Millisecond, 4096, true)
+	ctx := context.Background()
+
+	// Test case 1: Theo redirect, favicon resolve theo URL cuối cùng
+	t.Run("Fetch after redirect", func(t *testing.T) {
+		meta, err := fetcher.Fetch(ctx, server.URL+"/redirect")
+		require.NoError(t, err)
+		assert.Equal(t, "Landing", meta.Title)
+		assert.Equal(t, server.URL+"/icon.svg", meta.FaviconURL)
+	})
+
+	// Test case 2: Giải mã charset khai báo trong Content-Type
+	t.Run("Decode charset", func(t *testing.T) {
+		meta, err := fetcher.Fetch(ctx, server.URL+"/latin1")
+		require.NoError(t, err)
+		assert.Equal(t, "Café", meta.Title)
+	})
+
+	// Test case 3: Chỉ đọc tối đa maxBytes
+	t.Run("Size limit", func(t *testing.T) {
+		meta, err := fetcher.Fetch(ctx, server.URL+"/huge")
+		require.NoError(t, err)
+		assert.Empty(t, meta.Title)
+	})
+
+	// Test case 4: Các trường hợp lỗi
+	t.Run("Errors", func(t *testing.T) {
+		_, err := fetcher.Fetch(ctx, server.URL+"/image.png")
+		assert.ErrorIs(t, err, ErrNotHTML)
+
+		_, err = fetcher.Fetch(ctx, server.URL+"/missing")
+		assert.ErrorContains(t, err, "unexpected status 404")
+
+		_, err = fetcher.Fetch(ctx, server.URL+"/loop")
+		assert.ErrorContains(t, err, "redirects")
+
+		start := time.Now()
+		_, err = fetcher.Fetch(ctx, server.URL+"/slow")
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	// Test case 5: Mặc định chặn địa chỉ loopback/private (SSRF)
+	t.Run("Block private hosts", func(t *testing.T) {
+		strict := NewMetadataFetcher(300*time.Millisecond, 4096, false)
+		_, err := strict.Fetch(ctx, server.URL+"/page")
+		assert.ErrorIs(t, err, ErrPrivateHost)
+	})
+}
+
+func TestDenyPrivateAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		denied  bool
+	}{
+		{"93.184.216.34:443", false},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", false},
+		{"127.0.0.1:80", true},
+		{"10.1.2.3:80", true},
+		{"169.254.169.254:80", true},
+		{"0.0.0.0:80", true},
+		{"100.64.0.1:80", true},
+		{"192.0.0.170:80", true},
+		{"198.18.0.1:80", true},
+		{"255.255.255.255:80", true},
+		{"[::1]:80", true},
+		{"[::ffff:127.0.0.1]:80", true},
+		{"[::ffff:10.0.0.1]:80", true},
+		{"[64:ff9b::a9fe:a9fe]:80", true},
+		{"[fd00::1]:80", true},
+		{"[fe80::1%eth0]:80", true},
+		{"localhost:80", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := denyPrivateAddress("tcp", tt.address, nil)
+			if tt.denied {
+				assert.ErrorIs(t, err, ErrPrivateHost)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
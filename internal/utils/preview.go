@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/url"
+)
+
+// PreviewPage là nội dung trang HTML trả cho bot unfurl
+type PreviewPage struct {
+	Title       string
+	Description string
+	ImageURL    string
+	// URL là short URL, dùng cho og:url để preview gắn với link rút gọn
+	URL string
+	// RedirectURL là trang đích; người dùng mở trang này sẽ được chuyển tiếp
+	RedirectURL string
+}
+
+// previewTemplate chỉ chứa thẻ meta; html/template escape mọi giá trị theo ngữ cảnh
+var previewTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta property="og:type" content="website">
+<meta property="og:url" content="{{.URL}}">
+<meta property="og:title" content="{{.Title}}">
+{{- if .Description}}
+<meta name="description" content="{{.Description}}">
+<meta property="og:description" content="{{.Description}}">
+{{- end}}
+{{- if .ImageURL}}
+<meta property="og:image" content="{{.ImageURL}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="{{.ImageURL}}">
+{{- else}}
+<meta name="twitter:card" content="summary">
+{{- end}}
+<meta name="twitter:title" content="{{.Title}}">
+{{- if .Description}}
+<meta name="twitter:description" content="{{.Description}}">
+{{- end}}
+<meta http-equiv="refresh" content="0; url={{.RedirectURL}}">
+</head>
+<body><a href="{{.RedirectURL}}">{{.Title}}</a></body>
+</html>
+`))
+
+// RenderPreviewPage render trang HTML chứa thẻ Open Graph và Twitter Card.
+// RedirectURL phải là URL http(s) tuyệt đối vì được đặt vào meta refresh.
+func RenderPreviewPage(page PreviewPage) ([]byte, error) {
+	if !IsHTTPURL(page.RedirectURL) {
+		return nil, fmt.Errorf("redirect URL must be an absolute http(s) URL: %q", page.RedirectURL)
+	}
+
+	var buf bytes.Buffer
+	if err := previewTemplate.Execute(&buf, page); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// IsHTTPURL cho biết rawURL là URL http(s) tuyệt đối có host
+func IsHTTPURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
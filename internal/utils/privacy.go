@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"time"
+)
+
+// Các chế độ lưu IP của click
+const (
+	IPModeFull     = "full"
+	IPModeTruncate = "truncate"
+	IPModeHash     = "hash"
+)
+
+// IPAnonymizer chuyển IP của client thành giá trị được phép lưu theo privacy mode
+type IPAnonymizer struct {
+	mode     string
+	secret   []byte
+	rotation time.Duration
+}
+
+// NewIPAnonymizer tạo anonymizer. Ở chế độ hash, salt của mỗi chu kỳ rotation được
+// dẫn xuất từ secret nên mọi instance dùng cùng secret cho ra cùng giá trị; đổi secret
+// làm mọi hash cũ không còn liên kết được với IP.
+func NewIPAnonymizer(mode, secret string, rotation time.Duration) *IPAnonymizer {
+	if rotation <= 0 {
+		rotation = 24 * time.Hour
+	}
+	return &IPAnonymizer{
+		mode:     mode,
+		secret:   []byte(secret),
+		rotation: rotation,
+	}
+}
+
+// Anonymize trả về giá trị IP được lưu cho click tại thời điểm at
+func (a *IPAnonymizer) Anonymize(ipAddress string, at time.Time) string {
+	if ipAddress == "" {
+		return ""
+	}
+	switch a.mode {
+	case IPModeTruncate:
+		return TruncateIP(ipAddress)
+	case IPModeHash:
+		return a.hash(ipAddress, a.period(at))
+	default:
+		return ipAddress
+	}
+}
+
+// StoredForms liệt kê các giá trị đã lưu trong khoảng [from, to] chỉ định danh được ipAddress
+// (IP gốc và hash theo từng chu kỳ salt), dùng để xóa dữ liệu theo IP. Prefix đã cắt
+// dùng chung cho mọi IP cùng /24 hoặc /48 nên không được liệt kê.
+func (a *IPAnonymizer) StoredForms(ipAddress string, from, to time.Time) []string {
+	forms := []string{ipAddress}
+	if len(a.secret) == 0 || from.After(to) {
+		return forms
+	}
+	for period := a.period(from); period <= a.period(to); period++ {
+		forms = append(forms, a.hash(ipAddress, period))
+	}
+	return forms
+}
+
+// fingerprintRotation là chu kỳ xoay salt của fingerprint, cố định theo ngày UTC
+// để số visitor duy nhất trong ngày vẫn đếm được
+const fingerprintRotation = 24 * time.Hour
+
+// Fingerprint trả về fingerprint được phép lưu của visitor: HMAC-SHA256 của IP + User-Agent
+// với salt theo ngày dẫn xuất từ secret. Không có secret thì trả về chuỗi rỗng (không lưu).
+func (a *IPAnonymizer) Fingerprint(ipAddress, userAgent string, at time.Time) string {
+	if len(a.secret) == 0 {
+		return ""
+	}
+	return a.fingerprint(ipAddress, userAgent, fingerprintDay(at))
+}
+
+// FingerprintForms liệt kê fingerprint đã có thể lưu cho IP + User-Agent trong khoảng [from, to],
+// dùng để xóa dữ liệu của một visitor
+func (a *IPAnonymizer) FingerprintForms(ipAddress, userAgent string, from, to time.Time) []string {
+	if len(a.secret) == 0 || from.After(to) {
+		return nil
+	}
+	var forms []string
+	for day := fingerprintDay(from); day <= fingerprintDay(to); day++ {
+		forms = append(forms, a.fingerprint(ipAddress, userAgent, day))
+	}
+	return forms
+}
+
+// fingerprintDay là số thứ tự ngày UTC chứa thời điểm at
+func fingerprintDay(at time.Time) int64 {
+	return at.Unix() / int64(fingerprintRotation/time.Second)
+}
+
+// fingerprint tính HMAC-SHA256 (64 ký tự hex) của IP + User-Agent với salt của ngày.
+// Salt tách biệt với salt hash IP để fingerprint không suy ra được từ IP đã hash.
+func (a *IPAnonymizer) fingerprint(ipAddress, userAgent string, day int64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(day))
+	saltMAC := hmac.New(sha256.New, a.secret)
+	saltMAC.Write([]byte("fingerprint\x00"))
+	saltMAC.Write(buf[:])
+
+	mac := hmac.New(sha256.New, saltMAC.Sum(nil))
+	mac.Write([]byte(ipAddress + "\x00" + userAgent))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// period là số thứ tự chu kỳ rotation của salt chứa thời điểm at
+func (a *IPAnonymizer) period(at time.Time) int64 {
+	return at.Unix() / int64(a.rotation/time.Second)
+}
+
+// hash tính HMAC-SHA256 của IP với salt của chu kỳ, rút gọn còn 128 bit (32 ký tự hex)
+func (a *IPAnonymizer) hash(ipAddress string, period int64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(period))
+	saltMAC := hmac.New(sha256.New, a.secret)
+	saltMAC.Write(buf[:])
+
+	mac := hmac.New(sha256.New, saltMAC.Sum(nil))
+	mac.Write([]byte(ipAddress))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// TruncateIP giữ lại prefix /24 với IPv4 và /48 với IPv6, trả về chuỗi rỗng nếu IP không hợp lệ
+func TruncateIP(ipAddress string) string {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return ""
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// DoNotTrack kiểm tra header DNT hoặc Sec-GPC của request có yêu cầu không theo dõi không
+func DoNotTrack(dnt, gpc string) bool {
+	return dnt == "1" || gpc == "1"
+}
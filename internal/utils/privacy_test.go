@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{name: "IPv4", ip: "203.0.113.77", want: "203.0.113.0"},
+		{name: "IPv4-mapped IPv6", ip: "::ffff:203.0.113.77", want: "203.0.113.0"},
+		{name: "IPv6", ip: "2001:db8:abcd:12:34::1", want: "2001:db8:abcd::"},
+		{name: "Không hợp lệ", ip: "not-an-ip", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TruncateIP(tt.ip))
+		})
+	}
+}
+
+func TestIPAnonymizer_Anonymize(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "203.0.113.77", NewIPAnonymizer(IPModeFull, "", 0).Anonymize("203.0.113.77", at))
+	assert.Equal(t, "203.0.113.0", NewIPAnonymizer(IPModeTruncate, "", 0).Anonymize("203.0.113.77", at))
+	assert.Equal(t, "", NewIPAnonymizer(IPModeTruncate, "", 0).Anonymize("", at))
+
+	hasher := NewIPAnonymizer(IPModeHash, "secret", 24*time.Hour)
+	hashed := hasher.Anonymize("203.0.113.77", at)
+	assert.Len(t, hashed, 32)
+	assert.NotContains(t, hashed, "203")
+
+	// Cùng chu kỳ cho cùng hash, chu kỳ sau salt đã xoay
+	assert.Equal(t, hashed, hasher.Anonymize("203.0.113.77", at.Add(time.Hour)))
+	assert.NotEqual(t, hashed, hasher.Anonymize("203.0.113.77", at.Add(24*time.Hour)))
+	assert.NotEqual(t, hashed, NewIPAnonymizer(IPModeHash, "other", 24*time.Hour).Anonymize("203.0.113.77", at))
+}
+
+func TestIPAnonymizer_StoredForms(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	hasher := NewIPAnonymizer(IPModeHash, "secret", 24*time.Hour)
+
+	forms := hasher.StoredForms("203.0.113.77", at.AddDate(0, 0, -2), at)
+	assert.Len(t, forms, 4) // IP gốc và 3 chu kỳ salt
+	assert.Contains(t, forms, "203.0.113.77")
+	assert.NotContains(t, forms, "203.0.113.0")
+	assert.Contains(t, forms, hasher.Anonymize("203.0.113.77", at.AddDate(0, 0, -1)))
+
+	// Không có secret thì không có dạng hash
+	assert.Equal(t, []string{"203.0.113.77"}, NewIPAnonymizer(IPModeTruncate, "", 0).StoredForms("203.0.113.77", at, at))
+}
+
+func TestIPAnonymizer_Fingerprint(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	anon := NewIPAnonymizer(IPModeTruncate, "secret", time.Hour)
+
+	fingerprint := anon.Fingerprint("203.0.113.77", "Mozilla/5.0", at)
+	assert.Len(t, fingerprint, 64)
+	assert.NotEqual(t, VisitorFingerprint("203.0.113.77", "Mozilla/5.0"), fingerprint)
+
+	// Salt xoay theo ngày UTC, không theo chu kỳ hash IP
+	assert.Equal(t, fingerprint, anon.Fingerprint("203.0.113.77", "Mozilla/5.0", at.Add(11*time.Hour)))
+	assert.NotEqual(t, fingerprint, anon.Fingerprint("203.0.113.77", "Mozilla/5.0", at.Add(12*time.Hour)))
+	assert.NotEqual(t, fingerprint, anon.Fingerprint("203.0.113.77", "curl/8.0", at))
+	assert.NotEqual(t, fingerprint, NewIPAnonymizer(IPModeTruncate, "other", 0).Fingerprint("203.0.113.77", "Mozilla/5.0", at))
+
+	// Không có secret thì không lưu fingerprint
+	assert.Empty(t, NewIPAnonymizer(IPModeTruncate, "", 0).Fingerprint("203.0.113.77", "Mozilla/5.0", at))
+
+	forms := anon.FingerprintForms("203.0.113.77", "Mozilla/5.0", at.AddDate(0, 0, -2), at)
+	assert.Len(t, forms, 3)
+	assert.Contains(t, forms, fingerprint)
+	assert.Nil(t, NewIPAnonymizer(IPModeTruncate, "", 0).FingerprintForms("203.0.113.77", "Mozilla/5.0", at, at))
+}
+
+func TestDoNotTrack(t *testing.T) {
+	assert.True(t, DoNotTrack("1", ""))
+	assert.True(t, DoNotTrack("", "1"))
+	assert.False(t, DoNotTrack("0", ""))
+	assert.False(t, DoNotTrack("", ""))
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // cho phép logo JPEG
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/draw"
+)
+
+// Các mức sửa lỗi QR code (tỉ lệ module có thể hỏng: 7%, 15%, 25%, 30%)
+const (
+	QRLevelLow     = "L"
+	QRLevelMedium  = "M"
+	QRLevelQuarter = "Q"
+	QRLevelHigh    = "H"
+)
+
+// qrLogoRatio là tỉ lệ cạnh logo so với cạnh QR code; đủ nhỏ để mức H vẫn đọc được
+const qrLogoRatio = 0.22
+
+// qrLevels ánh xạ mức sửa lỗi sang go-qrcode (High của thư viện là mức Q)
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	QRLevelLow:     qrcode.Low,
+	QRLevelMedium:  qrcode.Medium,
+	QRLevelQuarter: qrcode.High,
+	QRLevelHigh:    qrcode.Highest,
+}
+
+// QROptions là tùy chọn render QR code
+type QROptions struct {
+	Size       int
+	Level      string
+	Foreground color.RGBA
+	Background color.RGBA
+	// Logo đặt giữa QR code, nil nếu không dùng
+	Logo image.Image
+}
+
+// ValidQRLevel kiểm tra mức sửa lỗi có được hỗ trợ
+func ValidQRLevel(level string) bool {
+	_, ok := qrLevels[level]
+	return ok
+}
+
+// ParseHexColor đọc màu dạng RGB, RRGGBB hoặc RRGGBBAA (có hoặc không có #)
+func ParseHexColor(value string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", value)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", value)
+	}
+	return color.RGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}, nil
+}
+
+// LoadQRLogo đọc logo PNG hoặc JPEG từ file
+func LoadQRLogo(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	logo, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode logo: %w", err)
+	}
+	return logo, nil
+}
+
+// RenderQRPNG render QR code của content thành ảnh PNG opts.Size x opts.Size
+func RenderQRPNG(content string, opts QROptions) ([]byte, error) {
+	q, err := newQRCode(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Size, opts.Size))
+	draw.Draw(img, img.Bounds(), q.Image(opts.Size), image.Point{}, draw.Src)
+	if opts.Logo != nil {
+		box, logoRect := qrLogoBox(opts.Size, opts.Logo.Bounds())
+		draw.Draw(img, box, &image.Uniform{C: opts.Background}, image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(img, logoRect, opts.Logo, opts.Logo.Bounds(), draw.Over, nil)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderQRSVG render QR code của content thành SVG; mỗi module là một ô trong viewBox
+func RenderQRSVG(content string, opts QROptions) ([]byte, error) {
+	q, err := newQRCode(content, opts)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := q.Bitmap()
+	modules := len(bitmap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" %s/>`, modules, modules, svgFill(opts.Background))
+	buf.WriteString(`<path ` + svgFill(opts.Foreground) + ` d="`)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			// Gộp các module liền nhau trên cùng hàng thành một đoạn
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if opts.Logo != nil {
+		// Logo nhúng dạng PNG data URI để SVG không phụ thuộc file ngoài
+		var logoPNG bytes.Buffer
+		if err := png.Encode(&logoPNG, opts.Logo); err != nil {
+			return nil, err
+		}
+		scale := float64(modules) / float64(opts.Size)
+		box, logoRect := qrLogoBox(opts.Size, opts.Logo.Bounds())
+		fmt.Fprintf(&buf, `<rect x="%.3f" y="%.3f" width="%.3f" height="%.3f" %s/>`,
+			float64(box.Min.X)*scale, float64(box.Min.Y)*scale, float64(box.Dx())*scale, float64(box.Dy())*scale, svgFill(opts.Background))
+		fmt.Fprintf(&buf, `<image x="%.3f" y="%.3f" width="%.3f" height="%.3f" href="data:image/png;base64,%s"/>`,
+			float64(logoRect.Min.X)*scale, float64(logoRect.Min.Y)*scale, float64(logoRect.Dx())*scale, float64(logoRect.Dy())*scale,
+			base64.StdEncoding.EncodeToString(logoPNG.Bytes()))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// newQRCode tạo QR code với mức sửa lỗi và màu theo opts
+func newQRCode(content string, opts QROptions) (*qrcode.QRCode, error) {
+	level, ok := qrLevels[opts.Level]
+	if !ok {
+		return nil, errors.New("invalid error correction level")
+	}
+	q, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	q.ForegroundColor = opts.Foreground
+	q.BackgroundColor = opts.Background
+	return q, nil
+}
+
+// qrLogoBox trả về ô nền (có viền) và vùng vẽ logo ở giữa ảnh, giữ nguyên tỉ lệ logo
+func qrLogoBox(size int, logo image.Rectangle) (image.Rectangle, image.Rectangle) {
+	side := int(float64(size) * qrLogoRatio)
+	padding := side / 10
+	w, h := side, side
+	if logo.Dx() > logo.Dy() {
+		h = side * logo.Dy() / logo.Dx()
+	} else if logo.Dy() > logo.Dx() {
+		w = side * logo.Dx() / logo.Dy()
+	}
+	logoRect := image.Rect((size-w)/2, (size-h)/2, (size-w)/2+w, (size-h)/2+h)
+	return logoRect.Inset(-padding), logoRect
+}
+
+// svgFill trả về thuộc tính fill (kèm độ trong suốt nếu có) của màu
+func svgFill(c color.RGBA) string {
+	fill := fmt.Sprintf(`fill="#%02x%02x%02x"`, c.R, c.G, c.B)
+	if c.A != 0xff {
+		fill += fmt.Sprintf(` fill-opacity="%.3f"`, float64(c.A)/255)
+	}
+	return fill
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{name: "RRGGBB", value: "1a2b3c", want: color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}},
+		{name: "Có dấu #", value: "#ffffff", want: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+		{name: "Viết tắt RGB", value: "f00", want: color.RGBA{R: 0xff, A: 0xff}},
+		{name: "Có alpha", value: "00000000", want: color.RGBA{}},
+		{name: "Sai độ dài", value: "12345", wantErr: true},
+		{name: "Không phải hex", value: "zzzzzz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHexColor(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderQRPNG(t *testing.T) {
+	fg := color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff}
+	bg := color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+	red := color.RGBA{R: 0xff, A: 0xff}
+
+	logo := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			logo.Set(x, y, red)
+		}
+	}
+
+	t.Run("Kích thước và màu theo tùy chọn", func(t *testing.T) {
+		data, err := RenderQRPNG("http://localhost:8080/abc", QROptions{Size: 300, Level: QRLevelMedium, Foreground: fg, Background: bg})
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, 300, img.Bounds().Dx())
+		assert.Equal(t, 300, img.Bounds().Dy())
+		// Góc ảnh thuộc vùng quiet zone nên là màu nền
+		assert.Equal(t, bg, color.RGBAModel.Convert(img.At(0, 0)))
+
+		foundFG := false
+		for x := 0; x < 300 && !foundFG; x++ {
+			foundFG = color.RGBAModel.Convert(img.At(x, 150)) == fg
+		}
+		assert.True(t, foundFG)
+	})
+
+	t.Run("Logo ở giữa ảnh", func(t *testing.T) {
+		data, err := RenderQRPNG("http://localhost:8080/abc", QROptions{Size: 300, Level: QRLevelHigh, Foreground: fg, Background: bg, Logo: logo})
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, red, color.RGBAModel.Convert(img.At(150, 150)))
+		// Logo giữ tỉ lệ 2:1 nên phía trên logo là viền màu nền
+		assert.Equal(t, bg, color.RGBAModel.Convert(img.At(150, 150-20)))
+	})
+
+	t.Run("Mức sửa lỗi không hợp lệ", func(t *testing.T) {
+		_, err := RenderQRPNG("http://localhost:8080/abc", QROptions{Size: 300, Level: "X", Foreground: fg, Background: bg})
+		assert.Error(t, err)
+	})
+}
+
+func TestRenderQRSVG(t *testing.T) {
+	data, err := RenderQRSVG("http://localhost:8080/abc", QROptions{
+		Size:       256,
+		Level:      QRLevelLow,
+		Foreground: color.RGBA{A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff},
+	})
+	require.NoError(t, err)
+
+	svg := string(data)
+	// Version 2 (25 module) cộng quiet zone 4 module mỗi bên
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="256" height="256" viewBox="0 0 33 33"`))
+	assert.Contains(t, svg, `fill="#ffffff" fill-opacity="0.000"`)
+	assert.Contains(t, svg, `<path fill="#000000" d="M`)
+	assert.True(t, strings.HasSuffix(svg, `</svg>`))
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Các channel phân loại nguồn truy cập
+const (
+	ChannelDirect   = "direct"
+	ChannelSearch   = "search"
+	ChannelSocial   = "social"
+	ChannelEmail    = "email"
+	ChannelInternal = "internal"
+	ChannelReferral = "referral"
+)
+
+// ReferrerInfo là referrer đã được chuẩn hóa
+type ReferrerInfo struct {
+	Host    string
+	Channel string
+}
+
+// searchDomains là các search engine, so khớp theo label domain (google.com, google.com.vn, ...)
+var searchDomains = []string{
+	"google", "bing", "yahoo", "duckduckgo", "baidu", "yandex", "ecosia", "coccoc", "naver", "search.brave",
+}
+
+// socialDomains là các mạng xã hội và shortener của chúng
+var socialDomains = []string{
+	"facebook.com", "fb.com", "fb.me", "messenger.com", "instagram.com", "twitter.com", "x.com", "t.co",
+	"linkedin.com", "lnkd.in", "reddit.com", "pinterest.com", "tiktok.com", "youtube.com", "youtu.be",
+	"threads.net", "zalo.me", "telegram.org", "t.me", "discord.com", "news.ycombinator.com",
+}
+
+// emailDomains là các webmail client
+var emailDomains = []string{
+	"mail.google.com", "outlook.live.com", "outlook.office.com", "outlook.office365.com", "mail.yahoo.com",
+	"mail.proton.me", "mail.zoho.com", "com.google.android.gm",
+}
+
+// ParseReferrer chuẩn hóa Referer header thành host và phân loại channel.
+// selfHost là host của chính service, dùng để nhận diện traffic internal.
+func ParseReferrer(referer, selfHost string) ReferrerInfo {
+	referer = strings.TrimSpace(referer)
+	if referer == "" {
+		return ReferrerInfo{Channel: ChannelDirect}
+	}
+
+	host := NormalizeHost(referer)
+	if host == "" {
+		return ReferrerInfo{Channel: ChannelDirect}
+	}
+
+	return ReferrerInfo{
+		Host:    host,
+		Channel: classifyReferrerHost(host, NormalizeHost(selfHost)),
+	}
+}
+
+// NormalizeHost lấy host từ URL, bỏ port, bỏ tiền tố www. / m. và chuyển về lowercase
+func NormalizeHost(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, prefix := range []string{"www.", "m.", "l.", "lm."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return host
+}
+
+// classifyReferrerHost phân loại channel theo host đã chuẩn hóa
+func classifyReferrerHost(host, selfHost string) string {
+	switch {
+	case selfHost != "" && host == selfHost:
+		return ChannelInternal
+	case matchDomain(host, emailDomains) || strings.HasPrefix(host, "mail.") || strings.HasPrefix(host, "webmail."):
+		return ChannelEmail
+	case matchDomain(host, socialDomains):
+		return ChannelSocial
+	case matchSearch(host):
+		return ChannelSearch
+	default:
+		return ChannelReferral
+	}
+}
+
+// matchDomain kiểm tra host bằng hoặc là subdomain của một domain trong danh sách
+func matchDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSearch kiểm tra host có thuộc search engine không, bất kể TLD
+func matchSearch(host string) bool {
+	for _, engine := range searchDomains {
+		if host == engine || strings.HasPrefix(host, engine+".") || strings.Contains(host, "."+engine+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReferrer(t *testing.T) {
+	const selfHost = "http://localhost:8080"
+
+	tests := []struct {
+		name    string
+		referer string
+		want    ReferrerInfo
+	}{
+		{
+			name:    "Không có referer",
+			referer: "",
+			want:    ReferrerInfo{Channel: ChannelDirect},
+		},
+		{
+			name:    "Google search với TLD quốc gia",
+			referer: "https://www.google.com.vn/search?q=abc",
+			want:    ReferrerInfo{Host: "google.com.vn", Channel: ChannelSearch},
+		},
+		{
+			name:    "Facebook link shim",
+			referer: "https://l.facebook.com/l.php?u=https%3A%2F%2Fexample.com",
+			want:    ReferrerInfo{Host: "facebook.com", Channel: ChannelSocial},
+		},
+		{
+			name:    "Twitter shortener",
+			referer: "https://t.co/abc",
+			want:    ReferrerInfo{Host: "t.co", Channel: ChannelSocial},
+		},
+		{
+			name:    "Gmail web",
+			referer: "https://mail.google.com/",
+			want:    ReferrerInfo{Host: "mail.google.com", Channel: ChannelEmail},
+		},
+		{
+			name:    "Gmail Android app",
+			referer: "android-app://com.google.android.gm/",
+			want:    ReferrerInfo{Host: "com.google.android.gm", Channel: ChannelEmail},
+		},
+		{
+			name:    "Traffic internal",
+			referer: "http://localhost:8080/dashboard",
+			want:    ReferrerInfo{Host: "localhost", Channel: ChannelInternal},
+		},
+		{
+			name:    "Blog bất kỳ",
+			referer: "https://Blog.Example.com:443/post/1",
+			want:    ReferrerInfo{Host: "blog.example.com", Channel: ChannelReferral},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseReferrer(tt.referer, selfHost))
+		})
+	}
+}
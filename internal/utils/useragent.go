@@ -0,0 +1,180 @@
+package utils
+
+import "strings"
+
+// Các loại thiết bị được phân loại từ User-Agent
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+// UserAgentInfo chứa các dimension được trích xuất từ User-Agent
+type UserAgentInfo struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	DeviceType     string
+}
+
+// botMarkers là các chuỗi (lowercase) nhận diện crawler, bot và HTTP client
+var botMarkers = []string{
+	"bot", "spider", "slurp", "crawl", "headless",
+	"facebookexternalhit", "whatsapp", "preview", "curl/", "wget/",
+	"python-requests", "python-urllib", "go-http-client", "postmanruntime",
+	"okhttp", "java/", "libwww", "httpclient",
+}
+
+// unfurlBotMarkers là các chuỗi (lowercase) nhận diện crawler tạo link preview của mạng xã hội
+// và ứng dụng chat. iMessage dùng User-Agent của facebookexternalhit và Twitterbot.
+var unfurlBotMarkers = []string{
+	"facebookexternalhit", "facebot", "twitterbot", "slackbot", "slack-imgproxy",
+	"linkedinbot", "discordbot", "telegrambot", "whatsapp", "skypeuripreview",
+	"pinterest", "redditbot", "embedly", "vkshare", "iframely", "mastodon",
+	"bluesky", "viber", "zalo", "snapchat",
+}
+
+// browserRule ánh xạ token trong User-Agent sang browser family.
+// Thứ tự quan trọng: Edge/Opera/Samsung chứa cả token "Chrome/" và "Safari/".
+type browserRule struct {
+	token  string
+	family string
+}
+
+var browserRules = []browserRule{
+	{"EdgA/", "Edge"},
+	{"EdgiOS/", "Edge"},
+	{"Edg/", "Edge"},
+	{"Edge/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Opera/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"YaBrowser/", "Yandex"},
+	{"UCBrowser/", "UC Browser"},
+	{"FxiOS/", "Firefox"},
+	{"Firefox/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"MSIE ", "Internet Explorer"},
+	{"curl/", "curl"},
+	{"Wget/", "Wget"},
+	{"PostmanRuntime/", "Postman"},
+	{"Go-http-client/", "Go-http-client"},
+	{"python-requests/", "python-requests"},
+	{"okhttp/", "okhttp"},
+}
+
+// IsUnfurlBot cho biết User-Agent là bot lấy link preview (Open Graph) của mạng xã hội, app chat.
+// Search engine crawler không nằm trong danh sách để vẫn nhận redirect.
+func IsUnfurlBot(ua string) bool {
+	lower := strings.ToLower(ua)
+	for _, marker := range unfurlBotMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseUserAgent trích xuất browser, version, OS và loại thiết bị từ User-Agent.
+// Parser dựa trên token phổ biến, không nhằm đầy đủ như các thư viện UA database.
+func ParseUserAgent(ua string) UserAgentInfo {
+	info := UserAgentInfo{
+		Browser:    "Other",
+		OS:         "Other",
+		DeviceType: DeviceDesktop,
+	}
+
+	// UA rỗng thường đến từ script hoặc client tự viết, không phải trình duyệt
+	if strings.TrimSpace(ua) == "" {
+		info.DeviceType = DeviceBot
+		return info
+	}
+
+	info.Browser, info.BrowserVersion = parseBrowser(ua)
+	info.OS = parseOS(ua)
+	info.DeviceType = parseDeviceType(ua)
+	return info
+}
+
+// parseBrowser tìm browser family và version
+func parseBrowser(ua string) (string, string) {
+	for _, rule := range browserRules {
+		if idx := strings.Index(ua, rule.token); idx >= 0 {
+			return rule.family, readVersion(ua[idx+len(rule.token):])
+		}
+	}
+
+	// IE 11 không còn token MSIE
+	if idx := strings.Index(ua, "Trident/"); idx >= 0 {
+		if rv := strings.Index(ua, "rv:"); rv >= 0 {
+			return "Internet Explorer", readVersion(ua[rv+len("rv:"):])
+		}
+		return "Internet Explorer", ""
+	}
+
+	// Safari phải kiểm tra sau Chrome vì Chrome cũng gửi token "Safari/"
+	if strings.Contains(ua, "Safari/") {
+		if idx := strings.Index(ua, "Version/"); idx >= 0 {
+			return "Safari", readVersion(ua[idx+len("Version/"):])
+		}
+		return "Safari", ""
+	}
+
+	return "Other", ""
+}
+
+// parseOS tìm hệ điều hành
+func parseOS(ua string) string {
+	switch {
+	case strings.Contains(ua, "Windows Phone"):
+		return "Windows Phone"
+	case strings.Contains(ua, "Windows"):
+		return "Windows"
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"), strings.Contains(ua, "iPod"):
+		return "iOS"
+	case strings.Contains(ua, "Mac OS X"), strings.Contains(ua, "Macintosh"):
+		return "macOS"
+	case strings.Contains(ua, "Android"):
+		return "Android"
+	case strings.Contains(ua, "CrOS"):
+		return "Chrome OS"
+	case strings.Contains(ua, "Linux"):
+		return "Linux"
+	default:
+		return "Other"
+	}
+}
+
+// parseDeviceType phân loại mobile, tablet, desktop hoặc bot
+func parseDeviceType(ua string) string {
+	lower := strings.ToLower(ua)
+	for _, marker := range botMarkers {
+		if strings.Contains(lower, marker) {
+			return DeviceBot
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "iPad"), strings.Contains(lower, "tablet"):
+		return DeviceTablet
+	// Android tablet không gửi token "Mobile"
+	case strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi"), strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPod"),
+		strings.Contains(ua, "Windows Phone"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+// readVersion đọc version (chữ số và dấu chấm) ở đầu chuỗi
+func readVersion(s string) string {
+	end := 0
+	for end < len(s) && (s[end] == '.' || (s[end] >= '0' && s[end] <= '9')) {
+		end++
+	}
+	return strings.TrimSuffix(s[:end], ".")
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want UserAgentInfo
+	}{
+		{
+			name: "Chrome trên Windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			want: UserAgentInfo{Browser: "Chrome", BrowserVersion: "120.0.0.0", OS: "Windows", DeviceType: DeviceDesktop},
+		},
+		{
+			name: "Edge trên Windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			want: UserAgentInfo{Browser: "Edge", BrowserVersion: "120.0.2210.91", OS: "Windows", DeviceType: DeviceDesktop},
+		},
+		{
+			name: "Safari trên iPhone",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			want: UserAgentInfo{Browser: "Safari", BrowserVersion: "17.2", OS: "iOS", DeviceType: DeviceMobile},
+		},
+		{
+			name: "Safari trên iPad",
+			ua:   "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			want: UserAgentInfo{Browser: "Safari", BrowserVersion: "16.6", OS: "iOS", DeviceType: DeviceTablet},
+		},
+		{
+			name: "Chrome trên Android phone",
+			ua:   "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			want: UserAgentInfo{Browser: "Chrome", BrowserVersion: "120.0.6099.144", OS: "Android", DeviceType: DeviceMobile},
+		},
+		{
+			name: "Samsung Internet trên Android tablet",
+			ua:   "Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36",
+			want: UserAgentInfo{Browser: "Samsung Internet", BrowserVersion: "23.0", OS: "Android", DeviceType: DeviceTablet},
+		},
+		{
+			name: "Firefox trên Linux",
+			ua:   "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			want: UserAgentInfo{Browser: "Firefox", BrowserVersion: "121.0", OS: "Linux", DeviceType: DeviceDesktop},
+		},
+		{
+			name: "Safari trên macOS",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			want: UserAgentInfo{Browser: "Safari", BrowserVersion: "17.1", OS: "macOS", DeviceType: DeviceDesktop},
+		},
+		{
+			name: "Googlebot",
+			ua:   "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want: UserAgentInfo{Browser: "Other", OS: "Other", DeviceType: DeviceBot},
+		},
+		{
+			name: "curl",
+			ua:   "curl/7.68.0",
+			want: UserAgentInfo{Browser: "curl", BrowserVersion: "7.68.0", OS: "Other", DeviceType: DeviceBot},
+		},
+		{
+			name: "User-Agent rỗng",
+			ua:   "",
+			want: UserAgentInfo{Browser: "Other", OS: "Other", DeviceType: DeviceBot},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseUserAgent(tt.ua))
+		})
+	}
+}
+
+func TestIsUnfurlBot(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want bool
+	}{
+		{"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", true},
+		{"Twitterbot/1.0", true},
+		{"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", true},
+		{"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", true},
+		{"TelegramBot (like TwitterBot)", true},
+		{"WhatsApp/2.23.20.0", true},
+		{"LinkedInBot/1.0 (compatible; Mozilla/5.0; Apache-HttpClient +http://www.linkedin.com)", true},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false},
+		{"curl/7.68.0", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsUnfurlBot(tt.ua), tt.ua)
+	}
+}
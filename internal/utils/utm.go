@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// AddMissingQuery thêm các query param chưa có trong URL (theo thứ tự tên), giữ nguyên query
+// và fragment sẵn có. URL không parse được trả về nguyên vẹn.
+func AddMissingQuery(rawURL string, params map[string]string) string {
+	if len(params) == 0 {
+		return rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	existing := parsed.Query()
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if _, ok := existing[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return rawURL
+	}
+	sort.Strings(names)
+
+	added := make([]string, 0, len(names))
+	for _, name := range names {
+		added = append(added, url.QueryEscape(name)+"="+url.QueryEscape(params[name]))
+	}
+	if parsed.RawQuery != "" {
+		added = append([]string{parsed.RawQuery}, added...)
+	}
+	parsed.RawQuery = strings.Join(added, "&")
+	return parsed.String()
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddMissingQuery(t *testing.T) {
+	utm := map[string]string{"utm_source": "newsletter", "utm_medium": "email"}
+
+	tests := []struct {
+		name   string
+		rawURL string
+		params map[string]string
+		want   string
+	}{
+		{
+			name:   "URL chưa có query",
+			rawURL: "https://example.com/page",
+			params: utm,
+			want:   "https://example.com/page?utm_medium=email&utm_source=newsletter",
+		},
+		{
+			name:   "Giữ nguyên thứ tự query sẵn có",
+			rawURL: "https://example.com/?b=2&a=1",
+			params: utm,
+			want:   "https://example.com/?b=2&a=1&utm_medium=email&utm_source=newsletter",
+		},
+		{
+			name:   "Không ghi đè param đã có",
+			rawURL: "https://example.com/?utm_source=ads",
+			params: utm,
+			want:   "https://example.com/?utm_source=ads&utm_medium=email",
+		},
+		{
+			name:   "Giữ fragment và escape giá trị",
+			rawURL: "https://example.com/#section",
+			params: map[string]string{"utm_campaign": "black friday"},
+			want:   "https://example.com/?utm_campaign=black+friday#section",
+		},
+		{
+			name:   "Đã có đủ param",
+			rawURL: "https://example.com/?utm_source=a&utm_medium=b",
+			params: utm,
+			want:   "https://example.com/?utm_source=a&utm_medium=b",
+		},
+		{
+			name:   "Không có param",
+			rawURL: "https://example.com/",
+			params: nil,
+			want:   "https://example.com/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AddMissingQuery(tt.rawURL, tt.params))
+		})
+	}
+}
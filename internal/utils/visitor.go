@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// VisitorFingerprint tạo fingerprint thô của visitor từ IP và User-Agent (SHA-256 hex).
+// Không có salt nên chỉ dùng làm phần tử HyperLogLog, không được lưu hay trả ra ngoài.
+func VisitorFingerprint(ipAddress, userAgent string) string {
+	sum := sha256.Sum256([]byte(ipAddress + "\x00" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// IVisitorCounter đếm gần đúng số visitor duy nhất của mỗi link
+type IVisitorCounter interface {
+	Add(ctx context.Context, urlID uint, fingerprint string, at time.Time) error
+	// CountDays cộng số visitor duy nhất của từng ngày UTC từ ngày của from tới ngày của to.
+	// Visitor quay lại vào ngày khác được tính lại, giống COUNT(DISTINCT visitor_hash) với salt theo ngày.
+	CountDays(ctx context.Context, urlID uint, from, to time.Time) (int64, error)
+}
+
+type redisVisitorCounter struct {
+	prefix string
+	dayTTL time.Duration
+	client *redis.Client
+}
+
+// NewRedisVisitorCounter tạo visitor counter dùng Redis HyperLogLog (PFADD/PFCOUNT).
+// Mỗi link có một HLL theo ngày UTC, hết hạn sau dayTTL.
+func NewRedisVisitorCounter(rclient *redis.Client, dayTTL time.Duration) IVisitorCounter {
+	return &redisVisitorCounter{
+		prefix: "visitors",
+		dayTTL: dayTTL,
+		client: rclient,
+	}
+}
+
+func (c *redisVisitorCounter) Add(ctx context.Context, urlID uint, fingerprint string, at time.Time) error {
+	dayKey := c.dayKey(urlID, at)
+	pipe := c.client.TxPipeline()
+	pipe.PFAdd(ctx, dayKey, fingerprint)
+	pipe.Expire(ctx, dayKey, c.dayTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *redisVisitorCounter) CountDays(ctx context.Context, urlID uint, from, to time.Time) (int64, error) {
+	pipe := c.client.Pipeline()
+	var counts []*redis.IntCmd
+	for day := from.UTC().Truncate(24 * time.Hour); !day.After(to); day = day.Add(24 * time.Hour) {
+		counts = append(counts, pipe.PFCount(ctx, c.dayKey(urlID, day)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, count := range counts {
+		total += count.Val()
+	}
+	return total, nil
+}
+
+func (c *redisVisitorCounter) dayKey(urlID uint, day time.Time) string {
+	return fmt.Sprintf("%s:%d:%s", c.prefix, urlID, day.UTC().Format("2006-01-02"))
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader là header chứa chữ ký HMAC của webhook payload
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// SignWebhookPayload ký payload theo dạng "t=<unix>,v1=<hex>", trong đó
+// v1 = HMAC-SHA256(secret, "<unix>.<body>"). Timestamp giúp bên nhận chống replay.
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, webhookMAC(secret, ts, body))
+}
+
+// VerifyWebhookSignature kiểm tra chữ ký và độ lệch thời gian không quá tolerance
+func VerifyWebhookSignature(secret, signature string, body []byte, now time.Time, tolerance time.Duration) bool {
+	var ts, mac string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			mac = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || mac == "" {
+		return false
+	}
+	if skew := now.Sub(time.Unix(unix, 0)); skew > tolerance || skew < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(mac), []byte(webhookMAC(secret, ts, body)))
+}
+
+func webhookMAC(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
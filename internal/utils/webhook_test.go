@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"url.created"}`)
+	signature := SignWebhookPayload("secret", now, body)
+
+	assert.Regexp(t, `^t=1710072000,v1=[0-9a-f]{64}$`, signature)
+	assert.True(t, VerifyWebhookSignature("secret", signature, body, now.Add(time.Minute), 5*time.Minute))
+
+	// Sai secret, body bị sửa, quá hạn hoặc sai định dạng
+	assert.False(t, VerifyWebhookSignature("other", signature, body, now, 5*time.Minute))
+	assert.False(t, VerifyWebhookSignature("secret", signature, []byte(`{}`), now, 5*time.Minute))
+	assert.False(t, VerifyWebhookSignature("secret", signature, body, now.Add(time.Hour), 5*time.Minute))
+	assert.False(t, VerifyWebhookSignature("secret", "garbage", body, now, 5*time.Minute))
+}
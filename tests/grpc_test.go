@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	shortenerv1 "github.com/url-shorted2/api/proto/shortener/v1"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/infrastructure/grpcserver"
+	"github.com/url-shorted2/internal/infrastructure/repositories"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+// setupGRPC chạy gRPC server in-process qua bufconn và trả về client đã kết nối.
+// Client tự gắn API key của testUser với đủ scope links và stats, trừ khi ctx đã có metadata authorization.
+func setupGRPC(t *testing.T) (*gorm.DB, usecases.IURLUsecase, *grpc.ClientConn) {
+	db := setupTestDB()
+	urlUsecase := usecases.NewURLUsecase(repositories.NewURLRepositoryImpl(db), repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), repositories.NewUserRepositoryImpl(db), "")
+	testUser := &entities.User{Name: "gRPC", Email: "grpc@example.com"}
+	require.NoError(t, db.Create(testUser).Error)
+	apiKey, err := apiKeyUsecase.CreateAPIKey(context.Background(), entities.CreateAPIKeyRequest{
+		Name:   "grpc-test",
+		UserID: &testUser.ID,
+		Scopes: []string{entities.ScopeLinksRead, entities.ScopeLinksWrite, entities.ScopeStatsRead},
+	})
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpcserver.NewServer(urlUsecase, apiKeyUsecase)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	withKey := func(ctx context.Context) context.Context {
+		if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+			return ctx
+		}
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+apiKey.Key)
+	}
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withKey(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withKey(ctx), desc, cc, method, opts...)
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return db, urlUsecase, conn
+}
+
+func TestGRPCURLService(t *testing.T) {
+	db, _, conn := setupGRPC(t)
+	client := shortenerv1.NewURLShortenerServiceClient(conn)
+	ctx := context.Background()
+
+	// Test case 1: Tạo short URL rồi lấy lại URL gốc và thống kê
+	t.Run("Create, get and stats", func(t *testing.T) {
+		created, err := client.CreateShortURL(ctx, &shortenerv1.CreateShortURLRequest{Url: "https://example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "1", created.ShortCode)
+		assert.Equal(t, "http://localhost:8080/1", created.ShortUrl)
+		assert.False(t, created.CreatedAt.AsTime().IsZero())
+
+		got, err := client.GetURL(ctx, &shortenerv1.GetURLRequest{ShortCode: created.ShortCode})
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com", got.OriginalUrl)
+
+		stats, err := client.GetURLStats(ctx, &shortenerv1.GetURLStatsRequest{ShortCode: created.ShortCode})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats.TotalClicks)
+		assert.Nil(t, stats.LastClicked)
+	})
+
+	// Test case 2: Lỗi của usecase được chuyển thành gRPC status code
+	t.Run("Error status codes", func(t *testing.T) {
+		_, err := client.CreateShortURL(ctx, &shortenerv1.CreateShortURLRequest{Url: ""})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.GetURL(ctx, &shortenerv1.GetURLRequest{ShortCode: "missing"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		// Code ổn định giống field code của problem+json nằm trong ErrorInfo
+		details := status.Convert(err).Details()
+		require.Len(t, details, 1)
+		info, ok := details[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, "url_not_found", info.Reason)
+
+		_, err = client.GetURLStats(ctx, &shortenerv1.GetURLStatsRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.ListClicks(ctx, &shortenerv1.ListClicksRequest{ShortCode: "1", Limit: 100000})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	// Test case 3: Lịch sử click theo trang
+	t.Run("List clicks", func(t *testing.T) {
+		var urlEntity entities.URL
+		require.NoError(t, db.Where("short_code = ?", "1").First(&urlEntity).Error)
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 3; i++ {
+			db.Create(&entities.Analytics{URLID: urlEntity.ID, Country: "VN", Browser: "Chrome", ClickedAt: base.Add(time.Duration(i) * time.Hour)})
+		}
+
+		page, err := client.ListClicks(ctx, &shortenerv1.ListClicksRequest{ShortCode: "1", Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, page.Clicks, 2)
+		assert.True(t, page.HasMore)
+		assert.True(t, page.Clicks[0].ClickedAt.AsTime().Equal(base.Add(2*time.Hour)))
+		assert.Equal(t, "Chrome", page.Clicks[0].Browser)
+
+		next, err := client.ListClicks(ctx, &shortenerv1.ListClicksRequest{ShortCode: "1", Limit: 2, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		assert.Len(t, next.Clicks, 1)
+		assert.False(t, next.HasMore)
+	})
+
+	// Test case 4: Xóa URL
+	t.Run("Delete", func(t *testing.T) {
+		_, err := client.DeleteURL(ctx, &shortenerv1.DeleteURLRequest{ShortCode: "1"})
+		require.NoError(t, err)
+
+		_, err = client.GetURL(ctx, &shortenerv1.GetURLRequest{ShortCode: "1"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = client.DeleteURL(ctx, &shortenerv1.DeleteURLRequest{ShortCode: "1"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	// Test case 5: Health check không cần API key
+	t.Run("Health check", func(t *testing.T) {
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+	})
+
+	// Test case 6: API key sai hoặc thiếu scope
+	t.Run("API key and scopes", func(t *testing.T) {
+		invalidCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer usk_invalid")
+		_, err := client.GetURL(invalidCtx, &shortenerv1.GetURLRequest{ShortCode: "1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		var testUser entities.User
+		require.NoError(t, db.Where("email = ?", "grpc@example.com").First(&testUser).Error)
+		readOnly, err := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), repositories.NewUserRepositoryImpl(db), "").CreateAPIKey(ctx, entities.CreateAPIKeyRequest{
+			Name:   "read-only",
+			UserID: &testUser.ID,
+			Scopes: []string{entities.ScopeLinksRead},
+		})
+		require.NoError(t, err)
+		readOnlyCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+readOnly.Key)
+
+		_, err = client.CreateShortURL(readOnlyCtx, &shortenerv1.CreateShortURLRequest{Url: "https://example.com"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = client.GetURL(readOnlyCtx, &shortenerv1.GetURLRequest{ShortCode: "missing"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestGRPCStreamClicks(t *testing.T) {
+	db, urlUsecase, conn := setupGRPC(t)
+	client := shortenerv1.NewURLShortenerServiceClient(conn)
+
+	var testUser entities.User
+	require.NoError(t, db.Where("email = ?", "grpc@example.com").First(&testUser).Error)
+	urlEntity := &entities.URL{ShortCode: "live123", OriginalURL: "https://example.com", IsActive: true, OwnerID: &testUser.ID}
+	db.Create(urlEntity)
+	seenClick := &entities.Analytics{URLID: urlEntity.ID, Country: "US", ClickedAt: time.Now().UTC()}
+	db.Create(seenClick)
+	missedClick := &entities.Analytics{URLID: urlEntity.ID, Country: "VN", ClickedAt: time.Now().UTC()}
+	db.Create(missedClick)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Test case 1: Replay click bị lỡ rồi nhận click live
+	t.Run("Replay then live click", func(t *testing.T) {
+		stream, err := client.StreamClicks(ctx, &shortenerv1.StreamClicksRequest{ShortCode: "live123", LastEventId: uint64(seenClick.ID)})
+		require.NoError(t, err)
+
+		replayed, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(missedClick.ID), replayed.Click.Id)
+		assert.Equal(t, "VN", replayed.Click.Country)
+
+		// Replay chỉ chạy sau khi đã subscribe nên click này chắc chắn được nhận
+		_, err = urlUsecase.Redirect(ctx, "live123", "203.0.113.7", "Mozilla/5.0", "https://www.google.com/", false)
+		require.NoError(t, err)
+
+		live, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Greater(t, live.Click.Id, uint64(missedClick.ID))
+		assert.Equal(t, "live123", live.Click.ShortCode)
+		assert.Equal(t, "google.com", live.Click.ReferrerHost)
+	})
+
+	// Test case 2: Short code không tồn tại
+	t.Run("Unknown short code", func(t *testing.T) {
+		stream, err := client.StreamClicks(ctx, &shortenerv1.StreamClicksRequest{ShortCode: "missing"})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
@@ -0,0 +1,289 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/url-shorted2/docs"
+	"github.com/url-shorted2/internal/domain/entities"
+	"github.com/url-shorted2/internal/infrastructure/handlers"
+	"github.com/url-shorted2/internal/infrastructure/repositories"
+	"github.com/url-shorted2/internal/infrastructure/routes"
+	"github.com/url-shorted2/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPIDoc là phần của OpenAPI document cần cho việc so sánh với code
+type openAPIDoc struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Parameters map[string]openAPIParameter `json:"parameters"`
+		Schemas    map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `json:"operationId"`
+	Parameters  []openAPIParameter `json:"parameters"`
+}
+
+type openAPIParameter struct {
+	Ref  string `json:"$ref"`
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+// loadOpenAPI parse docs.OpenAPI và thay $ref của parameter bằng định nghĩa trong components
+func loadOpenAPI(t *testing.T) openAPIDoc {
+	var doc openAPIDoc
+	require.NoError(t, json.Unmarshal(docs.OpenAPI, &doc))
+	for path, operations := range doc.Paths {
+		for method, op := range operations {
+			for i, param := range op.Parameters {
+				if param.Ref != "" {
+					resolved, ok := doc.Components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+					require.True(t, ok, "%s %s: unknown parameter %s", method, path, param.Ref)
+					op.Parameters[i] = resolved
+				}
+			}
+		}
+	}
+	return doc
+}
+
+// setupFullRouter đăng ký toàn bộ route giống cmd/main.go
+func setupFullRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := getTestConfig()
+
+	webhookUsecase := usecases.NewWebhookUsecase(repositories.NewWebhookRepositoryImpl(db), cfg)
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	tagRepo := repositories.NewTagRepositoryImpl(db)
+	userRepo := repositories.NewUserRepositoryImpl(db)
+	workspaceRepo := repositories.NewWorkspaceRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, workspaceRepo, cfg.Server.BaseURL, cfg, webhookUsecase)
+	tagUsecase := usecases.NewTagUsecase(tagRepo, urlRepo, workspaceRepo, cfg.Server.BaseURL)
+	folderUsecase := usecases.NewFolderUsecase(repositories.NewFolderRepositoryImpl(db), tagRepo, urlRepo, workspaceRepo)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), userRepo, cfg.Admin.Token)
+
+	router := gin.New()
+	routes.SetupRoutes(router, handlers.NewURLHandler(urlUsecase), handlers.NewWebhookHandler(webhookUsecase),
+		handlers.NewTagHandler(tagUsecase), handlers.NewFolderHandler(folderUsecase), handlers.NewAPIKeyHandler(apiKeyUsecase),
+		handlers.NewUserHandler(usecases.NewUserUsecase(userRepo)),
+		handlers.NewWorkspaceHandler(usecases.NewWorkspaceUsecase(workspaceRepo, userRepo)), apiKeyUsecase, cfg)
+	return router
+}
+
+var ginParamPattern = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	doc := loadOpenAPI(t)
+	assert.True(t, strings.HasPrefix(doc.OpenAPI, "3."))
+
+	registered := map[string]bool{}
+	for _, route := range setupFullRouter().Routes() {
+		path := ginParamPattern.ReplaceAllString(route.Path, "{$1}")
+		registered[strings.ToLower(route.Method)+" "+path] = true
+	}
+	documented := map[string]bool{}
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			documented[method+" "+path] = true
+		}
+	}
+
+	var missing, stale []string
+	for key := range registered {
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	assert.Empty(t, missing, "routes missing from docs/openapi.json")
+	assert.Empty(t, stale, "documented operations without a route")
+
+	// Path param khai báo khớp với {param} trong path và operationId không trùng
+	operationIDs := map[string]string{}
+	for path, operations := range doc.Paths {
+		var want []string
+		for _, match := range regexp.MustCompile(`\{([^}]+)\}`).FindAllStringSubmatch(path, -1) {
+			want = append(want, match[1])
+		}
+		for method, op := range operations {
+			var got []string
+			for _, param := range op.Parameters {
+				if param.In == "path" {
+					got = append(got, param.Name)
+				}
+			}
+			assert.ElementsMatch(t, want, got, "%s %s path parameters", method, path)
+
+			require.NotEmpty(t, op.OperationID, "%s %s has no operationId", method, path)
+			if other, ok := operationIDs[op.OperationID]; ok {
+				t.Errorf("operationId %s used by %s and %s %s", op.OperationID, other, method, path)
+			}
+			operationIDs[op.OperationID] = method + " " + path
+		}
+	}
+}
+
+func TestOpenAPIMatchesEntities(t *testing.T) {
+	doc := loadOpenAPI(t)
+
+	// Schema trong components phải có đúng các field JSON của entity tương ứng
+	schemas := map[string]interface{}{
+		"CreateURLRequest":            entities.CreateURLRequest{},
+		"CreateURLResponse":           entities.CreateURLResponse{},
+		"BatchCreateURLRequest":       entities.BatchCreateURLRequest{},
+		"BatchCreateURLResult":        entities.BatchCreateURLResult{},
+		"BatchCreateURLResponse":      entities.BatchCreateURLResponse{},
+		"URLListItem":                 entities.URLListItem{},
+		"URLMetadata":                 entities.URLMetadata{},
+		"URLPreviewRequest":           entities.URLPreviewRequest{},
+		"URLPreview":                  entities.URLPreview{},
+		"URLListResponse":             entities.URLListResponse{},
+		"DimensionCount":              entities.DimensionCount{},
+		"URLStatsResponse":            entities.URLStatsResponse{},
+		"TimeSeriesBucket":            entities.TimeSeriesBucket{},
+		"TimeSeriesResponse":          entities.TimeSeriesResponse{},
+		"ReferrerCount":               entities.ReferrerCount{},
+		"ReferrerStatsResponse":       entities.ReferrerStatsResponse{},
+		"Analytics":                   entities.Analytics{},
+		"ClickListResponse":           entities.ClickListResponse{},
+		"ClickEvent":                  entities.ClickEvent{},
+		"EraseAnalyticsRequest":       entities.EraseAnalyticsRequest{},
+		"EraseAnalyticsResponse":      entities.EraseAnalyticsResponse{},
+		"ImportRowIssue":              entities.ImportRowIssue{},
+		"ImportReport":                entities.ImportReport{},
+		"CreateWebhookRequest":        entities.CreateWebhookRequest{},
+		"WebhookResponse":             entities.WebhookResponse{},
+		"WebhookDelivery":             entities.WebhookDelivery{},
+		"TagRequest":                  entities.TagRequest{},
+		"TagResponse":                 entities.TagResponse{},
+		"TagListResponse":             entities.TagListResponse{},
+		"TagStatsResponse":            entities.TagStatsResponse{},
+		"SetURLTagsRequest":           entities.SetURLTagsRequest{},
+		"FolderRequest":               entities.FolderRequest{},
+		"FolderResponse":              entities.FolderResponse{},
+		"FolderListResponse":          entities.FolderListResponse{},
+		"SetURLFolderRequest":         entities.SetURLFolderRequest{},
+		"URLOrganizationResponse":     entities.URLOrganizationResponse{},
+		"CreateAPIKeyRequest":         entities.CreateAPIKeyRequest{},
+		"APIKeyResponse":              entities.APIKeyResponse{},
+		"CreateUserRequest":           entities.CreateUserRequest{},
+		"User":                        entities.User{},
+		"UserListResponse":            entities.UserListResponse{},
+		"Workspace":                   entities.Workspace{},
+		"WorkspaceSettings":           entities.WorkspaceSettings{},
+		"UTMParams":                   entities.UTMParams{},
+		"CreateWorkspaceRequest":      entities.CreateWorkspaceRequest{},
+		"WorkspaceListResponse":       entities.WorkspaceListResponse{},
+		"WorkspaceMember":             entities.WorkspaceMember{},
+		"WorkspaceMemberListResponse": entities.WorkspaceMemberListResponse{},
+		"InviteMemberRequest":         entities.InviteMemberRequest{},
+		"UpdateMemberRequest":         entities.UpdateMemberRequest{},
+		"Problem":                     entities.Problem{},
+	}
+	// Relationship của gorm không bao giờ được trả về qua API
+	ignored := map[string][]string{"Analytics": {"url"}}
+
+	for name, value := range schemas {
+		schema, ok := doc.Components.Schemas[name]
+		if !assert.True(t, ok, "schema %s missing", name) {
+			continue
+		}
+		want := structFields(reflect.TypeOf(value), "json", ignored[name])
+		var got []string
+		for field := range schema.Properties {
+			got = append(got, field)
+		}
+		assert.ElementsMatch(t, want, got, "schema %s", name)
+	}
+
+	// Query params của operation phải khớp tag form của request struct
+	queries := map[string]interface{}{
+		"listURLs":              entities.URLListRequest{},
+		"getClickTimeSeries":    entities.TimeSeriesRequest{},
+		"getReferrerStats":      entities.ReferrerStatsRequest{},
+		"listClicks":            entities.ClickListRequest{},
+		"exportClicks":          entities.ClickExportRequest{},
+		"getQRCode":             entities.QRCodeRequest{},
+		"importURLs":            entities.ImportURLsRequest{},
+		"listWebhookDeliveries": entities.WebhookDeliveryListRequest{},
+		"getTagStats":           entities.TagStatsRequest{},
+	}
+	found := map[string]bool{}
+	for _, operations := range doc.Paths {
+		for _, op := range operations {
+			value, ok := queries[op.OperationID]
+			if !ok {
+				continue
+			}
+			found[op.OperationID] = true
+			var got []string
+			for _, param := range op.Parameters {
+				if param.In == "query" {
+					got = append(got, param.Name)
+				}
+			}
+			assert.ElementsMatch(t, structFields(reflect.TypeOf(value), "form", nil), got, "query parameters of %s", op.OperationID)
+		}
+	}
+	assert.Len(t, found, len(queries))
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	router := setupFullRouter()
+
+	req := httptest.NewRequest("GET", "/api/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.JSONEq(t, string(docs.OpenAPI), w.Body.String())
+
+	req = httptest.NewRequest("GET", "/api/docs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), `fetch("openapi.json")`)
+}
+
+// structFields trả về tên field theo tag (json hoặc form), bỏ field "-" và field trong ignored
+func structFields(typ reflect.Type, tag string, ignored []string) []string {
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get(tag), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		skip := false
+		for _, ignore := range ignored {
+			skip = skip || ignore == name
+		}
+		if !skip {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
@@ -1,22 +1,40 @@
 package tests
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"image/png"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/url-shorted2/internal/config"
 	"github.com/url-shorted2/internal/domain/entities"
 	"github.com/url-shorted2/internal/infrastructure/handlers"
+	"github.com/url-shorted2/internal/infrastructure/middleware"
 	"github.com/url-shorted2/internal/infrastructure/repositories"
 	"github.com/url-shorted2/internal/usecases"
+	"github.com/url-shorted2/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
 func setupTestDB() *gorm.DB {
@@ -26,10 +44,16 @@ func setupTestDB() *gorm.DB {
 	}
 
 	// Auto migrate
-	db.AutoMigrate(&entities.URL{}, &entities.Analytics{})
+	db.AutoMigrate(&entities.URL{}, &entities.Analytics{}, &entities.HourlyRollup{}, &entities.DailyRollup{}, &entities.RollupState{}, &entities.Webhook{}, &entities.WebhookDelivery{}, &entities.Tag{}, &entities.Folder{}, &entities.APIKey{}, &entities.User{}, &entities.Workspace{}, &entities.WorkspaceMember{})
 	return db
 }
 
+// systemCaller chạy request với quyền hệ thống cho các test không kiểm tra xác thực API key
+func systemCaller(c *gin.Context) {
+	c.Request = c.Request.WithContext(usecases.WithSystemCaller(c.Request.Context()))
+	c.Next()
+}
+
 // getTestConfig tạo config cho test
 func getTestConfig() *config.Config {
 	return &config.Config{
@@ -59,6 +83,9 @@ func getTestConfig() *config.Config {
 			MaxTime:    30,
 			MaxTryTime: 10,
 		},
+		Privacy: config.PrivacyConfig{
+			IPHashSecret: "test-secret",
+		},
 	}
 }
 
@@ -69,11 +96,12 @@ func TestCreateShortURL(t *testing.T) {
 
 	// Khởi tạo dependencies
 	urlRepo := repositories.NewURLRepositoryImpl(db)
-	urlUsecase := usecases.NewURLUsecase(urlRepo, "http://localhost:8080", getTestConfig())
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
 	urlHandler := handlers.NewURLHandler(urlUsecase)
 
 	// Tạo router
 	router := gin.New()
+	router.Use(systemCaller)
 	router.POST("/api/v1/urls", urlHandler.CreateShortURL)
 
 	// Test case 1: Tạo short URL thành công
@@ -138,6 +166,245 @@ func TestCreateShortURL(t *testing.T) {
 	})
 }
 
+func TestBatchCreateURLs(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	cfg := getTestConfig()
+	cfg.Links.BatchMaxItems = 3
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", cfg, nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.POST("/api/v1/urls", urlHandler.CreateShortURL)
+	router.POST("/api/v1/urls/batch", urlHandler.CreateShortURLs)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Test case 1: Partial failure - item hợp lệ vẫn được tạo với code liên tiếp
+	t.Run("Create batch with partial failure", func(t *testing.T) {
+		w := post("/api/v1/urls", map[string]string{"url": "https://example.com"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = post("/api/v1/urls/batch", map[string]interface{}{
+			"items": []map[string]string{
+				{"url": "https://example.com/a"},
+				{"url": "not a url"},
+				{"url": "https://example.com/b"},
+			},
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.BatchCreateURLResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Created)
+		assert.Equal(t, 1, response.Failed)
+		assert.Equal(t, "2", response.Results[0].URL.ShortCode)
+		assert.NotEmpty(t, response.Results[1].Error)
+		assert.Equal(t, "3", response.Results[2].URL.ShortCode)
+
+		var saved entities.URL
+		assert.NoError(t, db.Where("short_code = ?", "3").First(&saved).Error)
+		assert.Equal(t, "https://example.com/b", saved.OriginalURL)
+	})
+
+	// Test case 2: Vượt quá số item tối đa
+	t.Run("Reject batch over the limit", func(t *testing.T) {
+		items := []map[string]string{}
+		for i := 0; i < 4; i++ {
+			items = append(items, map[string]string{"url": "https://example.com"})
+		}
+		w := post("/api/v1/urls/batch", map[string]interface{}{"items": items})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 3: Batch rỗng
+	t.Run("Reject empty batch", func(t *testing.T) {
+		w := post("/api/v1/urls/batch", map[string]interface{}{"items": []map[string]string{}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestImportURLs(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.POST("/api/v1/urls", urlHandler.CreateShortURL)
+	router.POST("/api/v1/admin/urls/import", urlHandler.ImportURLs)
+
+	db.Create(&entities.URL{ShortCode: "taken", OriginalURL: "https://example.com", IsActive: true})
+
+	const exportCSV = "keyword,url,title,timestamp,ip,clicks\n" +
+		"promo,https://example.com/promo,Promo,2023-05-01 10:00:00,127.0.0.1,42\n" +
+		"taken,https://example.com/taken,,,,\n" +
+		"broken,,,,,\n"
+
+	upload := func(mode string) (int, entities.ImportReport) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "yourls-export.csv")
+		part.Write([]byte(exportCSV))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/v1/admin/urls/import?mode="+mode, body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var report entities.ImportReport
+		json.Unmarshal(w.Body.Bytes(), &report)
+		return w.Code, report
+	}
+
+	// Test case 1: Dry-run không ghi database
+	t.Run("Dry-run reports without writing", func(t *testing.T) {
+		code, report := upload("dry-run")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 3, report.Total)
+		assert.Equal(t, 1, report.Valid)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, 1, report.Invalid)
+		assert.Equal(t, 1, report.Conflicts)
+
+		var count int64
+		db.Model(&entities.URL{}).Where("short_code = ?", "promo").Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	// Test case 2: Apply giữ nguyên code, ngày tạo và click count
+	t.Run("Apply keeps code, created date and clicks", func(t *testing.T) {
+		code, report := upload("apply")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, report.Created)
+
+		var imported entities.URL
+		assert.NoError(t, db.Where("short_code = ?", "promo").First(&imported).Error)
+		assert.Equal(t, "https://example.com/promo", imported.OriginalURL)
+		assert.Equal(t, int64(42), imported.ClickCount)
+		assert.True(t, imported.CreatedAt.Equal(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)))
+	})
+
+	// Test case 3: Import lại thì code đã có bị báo conflict
+	t.Run("Re-import reports conflicts", func(t *testing.T) {
+		code, report := upload("apply")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, 2, report.Conflicts)
+	})
+
+	// Test case 4: Link tạo mới sau import vẫn nhận code tự sinh không trùng
+	t.Run("Generated codes continue after import", func(t *testing.T) {
+		jsonData, _ := json.Marshal(map[string]string{"url": "https://example.com/new"})
+		req := httptest.NewRequest("POST", "/api/v1/urls", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	// Test case 5: File sai định dạng
+	t.Run("Reject file without required columns", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/admin/urls/import?format=csv", strings.NewReader("name,value\na,b\n"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestQRCode(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	cfg := getTestConfig()
+	cfg.Server.BaseURL = "https://sho.rt"
+	cfg.QR.CacheMaxAge = 3600
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", cfg, nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls/:shortCode/qr", urlHandler.GetQRCode)
+
+	db.Create(&entities.URL{ShortCode: "promo", OriginalURL: "https://example.com", IsActive: true})
+
+	get := func(query string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/urls/promo/qr"+query, nil)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Test case 1: PNG mặc định kèm header cache
+	t.Run("PNG with caching headers", func(t *testing.T) {
+		w := get("?size=128", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+
+		img, err := png.Decode(w.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, 128, img.Bounds().Dx())
+
+		// Cùng params thì ETag giống nhau, CDN/browser nhận 304
+		w2 := get("?size=128", map[string]string{"If-None-Match": w.Header().Get("ETag")})
+		assert.Equal(t, http.StatusNotModified, w2.Code)
+		assert.Empty(t, w2.Body.Bytes())
+	})
+
+	// Test case 2: SVG với màu tùy chỉnh
+	t.Run("SVG with custom colors", func(t *testing.T) {
+		w := get("?format=svg&ecc=h&fg=%23ff0000&bg=fff", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `fill="#ff0000"`)
+	})
+
+	// Test case 3: Params không hợp lệ
+	t.Run("Reject invalid params", func(t *testing.T) {
+		for _, query := range []string{"?format=gif", "?size=10", "?ecc=Z", "?fg=red", "?fg=000&bg=000", "?logo=true"} {
+			w := get(query, nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	// Test case 4: Short code không tồn tại
+	t.Run("Unknown short code", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/urls/missing/qr", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestRedirect(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
@@ -145,11 +412,12 @@ func TestRedirect(t *testing.T) {
 
 	// Khởi tạo dependencies
 	urlRepo := repositories.NewURLRepositoryImpl(db)
-	urlUsecase := usecases.NewURLUsecase(urlRepo, "http://localhost:8080", getTestConfig())
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
 	urlHandler := handlers.NewURLHandler(urlUsecase)
 
 	// Tạo router
 	router := gin.New()
+	router.Use(systemCaller)
 	router.GET("/:shortCode", urlHandler.Redirect)
 
 	// Tạo URL test trước
@@ -190,11 +458,12 @@ func TestGetURLStats(t *testing.T) {
 
 	// Khởi tạo dependencies
 	urlRepo := repositories.NewURLRepositoryImpl(db)
-	urlUsecase := usecases.NewURLUsecase(urlRepo, "http://localhost:8080", getTestConfig())
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
 	urlHandler := handlers.NewURLHandler(urlUsecase)
 
 	// Tạo router
 	router := gin.New()
+	router.Use(systemCaller)
 	router.GET("/api/v1/urls/:shortCode/stats", urlHandler.GetURLStats)
 
 	// Tạo URL test trước
@@ -223,7 +492,37 @@ func TestGetURLStats(t *testing.T) {
 		assert.Equal(t, int64(5), response.TotalClicks)
 	})
 
-	// Test case 2: Short code không tồn tại
+	// Test case 2: Breakdown theo browser, OS, device sau khi redirect
+	t.Run("Get URL stats with user agent breakdowns", func(t *testing.T) {
+		router.GET("/:shortCode", urlHandler.Redirect)
+		for _, ua := range []string{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		} {
+			req, _ := http.NewRequest("GET", "/stats123", nil)
+			req.Header.Set("User-Agent", ua)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		req, _ := http.NewRequest("GET", "/api/v1/urls/stats123/stats", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.URLStatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.DimensionCount{{Value: "Chrome", Clicks: 2}, {Value: "Safari", Clicks: 1}}, response.Browsers)
+		assert.Equal(t, []entities.DimensionCount{{Value: "Windows", Clicks: 2}, {Value: "iOS", Clicks: 1}}, response.OperatingSystems)
+		assert.Equal(t, []entities.DimensionCount{{Value: "desktop", Clicks: 2}, {Value: "mobile", Clicks: 1}}, response.Devices)
+		// Hai click đầu cùng IP + User-Agent nên chỉ tính là một visitor
+		assert.Equal(t, int64(2), response.UniqueVisitors)
+	})
+
+	// Test case 3: Short code không tồn tại
 	t.Run("Get stats for non-existent short code", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/api/v1/urls/nonexistent/stats", nil)
 		w := httptest.NewRecorder()
@@ -233,3 +532,2540 @@ func TestGetURLStats(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 }
+
+func TestGetClickTimeSeries(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls/:shortCode/stats/timeseries", urlHandler.GetClickTimeSeries)
+
+	// Tạo URL và analytics test trước
+	urlEntity := &entities.URL{
+		ShortCode:   "series123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	}
+	db.Create(urlEntity)
+	for _, clickedAt := range []time.Time{
+		time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC), // 2024-01-02 06:30 giờ Việt Nam
+		time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC), // Ngoài khoảng thời gian
+	} {
+		db.Create(&entities.Analytics{URLID: urlEntity.ID, ClickedAt: clickedAt})
+	}
+
+	// Test case 1: Gom theo ngày, có zero-fill
+	t.Run("Get daily time series with zero-filled gaps", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/series123/stats/timeseries?interval=day&from=2024-01-01&to=2024-01-05", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.TimeSeriesResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), response.TotalClicks)
+		if assert.Len(t, response.Buckets, 4) {
+			assert.Equal(t, []int64{2, 0, 1, 0}, []int64{
+				response.Buckets[0].Clicks,
+				response.Buckets[1].Clicks,
+				response.Buckets[2].Clicks,
+				response.Buckets[3].Clicks,
+			})
+		}
+	})
+
+	// Test case 2: Gom theo ngày theo múi giờ
+	t.Run("Get daily time series in timezone", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/series123/stats/timeseries?interval=day&from=2024-01-01&to=2024-01-04&tz=Asia/Ho_Chi_Minh", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.TimeSeriesResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Asia/Ho_Chi_Minh", response.Timezone)
+		if assert.Len(t, response.Buckets, 3) {
+			assert.Equal(t, int64(1), response.Buckets[0].Clicks)
+			assert.Equal(t, int64(1), response.Buckets[1].Clicks)
+			assert.Equal(t, int64(1), response.Buckets[2].Clicks)
+		}
+	})
+
+	// Test case 3: Interval không hợp lệ
+	t.Run("Get time series with invalid interval", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/series123/stats/timeseries?interval=minute", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 4: Short code không tồn tại
+	t.Run("Get time series for non-existent short code", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/nonexistent/stats/timeseries", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetReferrerStats(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/:shortCode", urlHandler.Redirect)
+	router.GET("/api/v1/urls/:shortCode/stats/referrers", urlHandler.GetReferrerStats)
+
+	// Tạo URL test và giả lập click từ nhiều nguồn
+	db.Create(&entities.URL{
+		ShortCode:   "ref123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	})
+	for _, referer := range []string{
+		"https://www.google.com/search?q=shortener",
+		"https://google.com/",
+		"https://l.facebook.com/l.php",
+		"",
+	} {
+		req, _ := http.NewRequest("GET", "/ref123", nil)
+		req.Header.Set("Referer", referer)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Test case 1: Lấy top referrer và channel
+	t.Run("Get referrer stats successfully", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/ref123/stats/referrers?limit=5", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.ReferrerStatsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), response.TotalClicks)
+		assert.Equal(t, []entities.ReferrerCount{
+			{Host: "google.com", Channel: "search", Clicks: 2},
+			{Host: "facebook.com", Channel: "social", Clicks: 1},
+		}, response.Referrers)
+		assert.Equal(t, []entities.DimensionCount{
+			{Value: "search", Clicks: 2},
+			{Value: "direct", Clicks: 1},
+			{Value: "social", Clicks: 1},
+		}, response.Channels)
+	})
+
+	// Test case 2: Limit không hợp lệ
+	t.Run("Get referrer stats with invalid limit", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/ref123/stats/referrers?limit=abc", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 3: Short code không tồn tại
+	t.Run("Get referrer stats for non-existent short code", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/nonexistent/stats/referrers", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestListClicks(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls/:shortCode/clicks", urlHandler.ListClicks)
+
+	// Tạo URL và 5 click test trước
+	urlEntity := &entities.URL{
+		ShortCode:   "clicks123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	}
+	db.Create(urlEntity)
+	for i, country := range []string{"VN", "US", "VN", "VN", "JP"} {
+		db.Create(&entities.Analytics{
+			URLID:        urlEntity.ID,
+			Country:      country,
+			ReferrerHost: "google.com",
+			ClickedAt:    time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC),
+		})
+	}
+
+	listClicks := func(query string) entities.ClickListResponse {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/clicks123/clicks"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.ClickListResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// Test case 1: Duyệt hết các trang bằng cursor
+	t.Run("Paginate clicks with cursor", func(t *testing.T) {
+		first := listClicks("?limit=2")
+		assert.Len(t, first.Clicks, 2)
+		assert.True(t, first.HasMore)
+		assert.NotEmpty(t, first.NextCursor)
+		assert.Equal(t, 5, first.Clicks[0].ClickedAt.Day()) // Mới nhất trước
+
+		second := listClicks("?limit=2&cursor=" + first.NextCursor)
+		assert.Len(t, second.Clicks, 2)
+		assert.True(t, second.HasMore)
+
+		last := listClicks("?limit=2&cursor=" + second.NextCursor)
+		assert.Len(t, last.Clicks, 1)
+		assert.False(t, last.HasMore)
+		assert.Empty(t, last.NextCursor)
+	})
+
+	// Test case 2: Lọc theo country và khoảng thời gian
+	t.Run("Filter clicks by country and date range", func(t *testing.T) {
+		response := listClicks("?country=vn&from=2024-01-02&to=2024-01-05&referrer=www.google.com")
+		assert.Len(t, response.Clicks, 2)
+		for _, click := range response.Clicks {
+			assert.Equal(t, "VN", click.Country)
+		}
+	})
+
+	// Test case 3: Cursor không hợp lệ
+	t.Run("List clicks with invalid cursor", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/clicks123/clicks?cursor=***", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExportClicks(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls/:shortCode/clicks/export", urlHandler.ExportClicks)
+
+	// Tạo URL và click test trước
+	urlEntity := &entities.URL{
+		ShortCode:   "export123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	}
+	db.Create(urlEntity)
+	for i := 1; i <= 3; i++ {
+		db.Create(&entities.Analytics{
+			URLID:     urlEntity.ID,
+			IPAddress: "192.168.1.1",
+			Country:   "VN",
+			ClickedAt: time.Date(2024, 1, i, 0, 0, 0, 0, time.UTC),
+		})
+	}
+
+	// Test case 1: Export CSV theo khoảng thời gian
+	t.Run("Export clicks as CSV", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/export123/clicks/export?format=csv&from=2024-01-02", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment; filename=clicks-export123-")
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 3) // header + 2 click
+		assert.True(t, strings.HasPrefix(lines[0], "id,clicked_at,ip_address"))
+		assert.Contains(t, lines[1], "2024-01-02T00:00:00Z")
+	})
+
+	// Test case 2: Export NDJSON
+	t.Run("Export clicks as NDJSON", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/export123/clicks/export?format=ndjson", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 3)
+		var click entities.Analytics
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &click))
+		assert.Equal(t, "VN", click.Country)
+	})
+
+	// Test case 3: Format không hỗ trợ
+	t.Run("Export clicks with unsupported format", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/export123/clicks/export?format=xml", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestStreamClicks(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router, SSE cần kết nối thật nên dùng httptest.Server
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls/:shortCode/events", urlHandler.StreamClicks)
+	router.GET("/:shortCode", urlHandler.Redirect)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// Tạo URL và hai click cũ, client đã nhận click đầu tiên
+	urlEntity := &entities.URL{
+		ShortCode:   "live123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	}
+	db.Create(urlEntity)
+	seenClick := &entities.Analytics{URLID: urlEntity.ID, Country: "US", ClickedAt: time.Now().UTC()}
+	db.Create(seenClick)
+	missedClick := &entities.Analytics{URLID: urlEntity.ID, Country: "VN", ClickedAt: time.Now().UTC()}
+	db.Create(missedClick)
+
+	// readEvent đọc tới event click tiếp theo, trả về id và data
+	readEvent := func(t *testing.T, reader *bufio.Reader) (string, entities.ClickEvent) {
+		var id string
+		var event entities.ClickEvent
+		for {
+			line, err := reader.ReadString('\n')
+			if !assert.NoError(t, err) {
+				return id, event
+			}
+			switch {
+			case strings.HasPrefix(line, "id:"):
+				id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+			case strings.HasPrefix(line, "data:{"):
+				assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event))
+				return id, event
+			}
+		}
+	}
+
+	// Test case 1: Nhận click live sau khi redirect
+	t.Run("Receive live click", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/v1/urls/live123/events", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"))
+
+		reader := bufio.NewReader(resp.Body)
+		// Chờ dòng retry để chắc chắn đã subscribe trước khi click
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "retry:3000\n", line)
+
+		redirectReq, _ := http.NewRequest("GET", "/live123", nil)
+		redirectReq.Header.Set("Referer", "https://www.google.com/")
+		router.ServeHTTP(httptest.NewRecorder(), redirectReq)
+
+		id, event := readEvent(t, reader)
+		assert.NotEqual(t, fmt.Sprint(missedClick.ID), id)
+		assert.Equal(t, "live123", event.ShortCode)
+		assert.Equal(t, "google.com", event.ReferrerHost)
+	})
+
+	// Test case 2: Reconnect với Last-Event-ID nhận lại click bị lỡ
+	t.Run("Replay missed clicks with Last-Event-ID", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/v1/urls/live123/events", nil)
+		req.Header.Set("Last-Event-ID", fmt.Sprint(seenClick.ID))
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer resp.Body.Close()
+
+		id, event := readEvent(t, bufio.NewReader(resp.Body))
+		assert.Equal(t, fmt.Sprint(missedClick.ID), id)
+		assert.Equal(t, "VN", event.Country)
+	})
+
+	// Test case 3: Last-Event-ID không hợp lệ
+	t.Run("Invalid Last-Event-ID", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", server.URL+"/api/v1/urls/live123/events?last_event_id=abc", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	// Test case 4: URL không tồn tại
+	t.Run("Stream events for non-existent URL", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", server.URL+"/api/v1/urls/nonexistent/events", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	// Test case 5: Lỡ nhiều hơn một trang replay (500 click) vẫn nhận đủ, click cũ nhất trước
+	t.Run("Replay more than one page of missed clicks", func(t *testing.T) {
+		backlogURL := &entities.URL{ShortCode: "backlog1", OriginalURL: "https://example.com", IsActive: true}
+		db.Create(backlogURL)
+		missed := make([]entities.Analytics, 1201)
+		for i := range missed {
+			missed[i] = entities.Analytics{URLID: backlogURL.ID, ClickedAt: time.Now().UTC()}
+		}
+		require.NoError(t, db.CreateInBatches(missed, 200).Error)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/v1/urls/backlog1/events", nil)
+		req.Header.Set("Last-Event-ID", fmt.Sprint(missed[0].ID))
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+		for _, click := range missed[1:] {
+			id, _ := readEvent(t, reader)
+			if !assert.Equal(t, fmt.Sprint(click.ID), id) {
+				return
+			}
+		}
+	})
+}
+
+func TestAnalyticsRollup(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Giữ click thô 30 ngày, cũ hơn sẽ được đọc từ rollup
+	cfg := getTestConfig()
+	cfg.Analytics.RawRetention = 30 * 24 * time.Hour
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", cfg, nil)
+	rollupUsecase := usecases.NewAnalyticsRollupUsecase(urlRepo, cfg)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls/:shortCode/stats", urlHandler.GetURLStats)
+	router.GET("/api/v1/urls/:shortCode/stats/timeseries", urlHandler.GetClickTimeSeries)
+	router.GET("/api/v1/urls/:shortCode/stats/referrers", urlHandler.GetReferrerStats)
+
+	// Tạo URL, hai click cũ và một click mới
+	urlEntity := &entities.URL{
+		ShortCode:   "rollup123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+		ClickCount:  3,
+	}
+	db.Create(urlEntity)
+	old := time.Now().UTC().AddDate(0, 0, -60)
+	for i := 0; i < 2; i++ {
+		db.Create(&entities.Analytics{
+			URLID:           urlEntity.ID,
+			Country:         "VN",
+			Browser:         "Chrome",
+			ReferrerHost:    "google.com",
+			ReferrerChannel: "search",
+			ClickedAt:       old.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	recent := time.Now().UTC().Add(-2 * time.Hour)
+	db.Create(&entities.Analytics{
+		URLID:           urlEntity.ID,
+		Country:         "US",
+		Browser:         "Safari",
+		ReferrerHost:    "facebook.com",
+		ReferrerChannel: "social",
+		ClickedAt:       recent,
+	})
+
+	result, err := rollupUsecase.RunRollup(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.PrunedRows)
+
+	var rawCount int64
+	db.Model(&entities.Analytics{}).Where("url_id = ?", urlEntity.ID).Count(&rawCount)
+	assert.Equal(t, int64(1), rawCount)
+
+	// Chạy lại không làm thay đổi số liệu
+	_, err = rollupUsecase.RunRollup(time.Now())
+	assert.NoError(t, err)
+
+	// Test case 1: Referrer gộp rollup và click thô
+	t.Run("Referrer stats include rolled up clicks", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/rollup123/stats/referrers", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.ReferrerStatsResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, []entities.ReferrerCount{
+			{Host: "google.com", Channel: "search", Clicks: 2},
+			{Host: "facebook.com", Channel: "social", Clicks: 1},
+		}, response.Referrers)
+	})
+
+	// Test case 2: Time series đọc rollup theo giờ cho khoảng cũ
+	t.Run("Time series include rolled up clicks", func(t *testing.T) {
+		from := old.AddDate(0, 0, -1).Format("2006-01-02")
+		req, _ := http.NewRequest("GET", "/api/v1/urls/rollup123/stats/timeseries?interval=day&from="+from, nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.TimeSeriesResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, int64(3), response.TotalClicks)
+	})
+
+	// Test case 3: Timezone +05:30 không chia được rollup theo giờ, chỉ đọc click thô còn trong retention
+	t.Run("Time series with half-hour timezone read only raw clicks", func(t *testing.T) {
+		from := old.AddDate(0, 0, -1).Format("2006-01-02")
+		req, _ := http.NewRequest("GET", "/api/v1/urls/rollup123/stats/timeseries?interval=day&tz=Asia/Kolkata&from="+from, nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// Khoảng còn click thô đọc click thô thay vì rollup theo giờ
+		from = time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+		req, _ = http.NewRequest("GET", "/api/v1/urls/rollup123/stats/timeseries?interval=day&tz=Asia/Kolkata&from="+from, nil)
+		w = httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.TimeSeriesResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, int64(1), response.TotalClicks)
+
+		// Click rơi vào đúng ngày theo giờ Kolkata
+		kolkata, _ := time.LoadLocation("Asia/Kolkata")
+		local := recent.In(kolkata)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, kolkata)
+		for _, bucket := range response.Buckets {
+			if bucket.Start.Equal(day) {
+				assert.Equal(t, int64(1), bucket.Clicks)
+			} else {
+				assert.Equal(t, int64(0), bucket.Clicks)
+			}
+		}
+	})
+
+	// Test case 4: Breakdown trong stats
+	t.Run("Stats breakdown include rolled up clicks", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/urls/rollup123/stats", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.URLStatsResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, []entities.DimensionCount{
+			{Value: "Chrome", Clicks: 2},
+			{Value: "Safari", Clicks: 1},
+		}, response.Browsers)
+	})
+
+	// Test case 5: Rollup job chưa chạy tới thì click thô cũ hơn retention vẫn được đọc
+	t.Run("Stats read raw clicks the rollup has not reached", func(t *testing.T) {
+		laggingDB := setupTestDB()
+		laggingRepo := repositories.NewURLRepositoryImpl(laggingDB)
+		laggingHandler := handlers.NewURLHandler(usecases.NewURLUsecase(laggingRepo, repositories.NewWorkspaceRepositoryImpl(laggingDB), "http://localhost:8080", cfg, nil))
+		laggingRouter := gin.New()
+		laggingRouter.Use(systemCaller)
+		laggingRouter.GET("/api/v1/urls/:shortCode/stats/referrers", laggingHandler.GetReferrerStats)
+
+		lagging := &entities.URL{ShortCode: "lagging1", OriginalURL: "https://example.com", IsActive: true, ClickCount: 1}
+		laggingDB.Create(lagging)
+		laggingDB.Create(&entities.Analytics{URLID: lagging.ID, ReferrerHost: "google.com", ReferrerChannel: "search", ClickedAt: old})
+
+		req, _ := http.NewRequest("GET", "/api/v1/urls/lagging1/stats/referrers", nil)
+		w := httptest.NewRecorder()
+
+		laggingRouter.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.ReferrerStatsResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, []entities.ReferrerCount{{Host: "google.com", Channel: "search", Clicks: 1}}, response.Referrers)
+	})
+}
+
+func TestPrivacyAndErasure(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	cfg := getTestConfig()
+	cfg.Privacy = config.PrivacyConfig{IPMode: "truncate", IPHashSecret: "test-secret", HonorDNT: true}
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", cfg, nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.GET("/:shortCode", urlHandler.Redirect)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), repositories.NewUserRepositoryImpl(db), "admin-secret")
+	admin := router.Group("/api/v1/admin", middleware.APIKeyAuthMiddleware(apiKeyUsecase), middleware.RequireScope(entities.ScopeAdmin))
+	admin.POST("/analytics/erase", urlHandler.EraseAnalytics)
+
+	// Tạo URL test trước
+	db.Create(&entities.URL{
+		ShortCode:   "private123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	})
+
+	click := func(remoteAddr string, headers map[string]string) {
+		req := httptest.NewRequest("GET", "/private123", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", "Mozilla/5.0")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Test case 1: IP được cắt về /24 trước khi lưu
+	t.Run("Store truncated IP", func(t *testing.T) {
+		click("203.0.113.77:5000", nil)
+
+		var analytics entities.Analytics
+		db.Order("id DESC").First(&analytics)
+		assert.Equal(t, "203.0.113.0", analytics.IPAddress)
+		assert.NotEmpty(t, analytics.VisitorHash)
+	})
+
+	// Test case 2: DNT / Sec-GPC không lưu dữ liệu định danh
+	t.Run("Honor Sec-GPC header", func(t *testing.T) {
+		click("198.51.100.5:5000", map[string]string{"Sec-GPC": "1"})
+
+		var analytics entities.Analytics
+		db.Order("id DESC").First(&analytics)
+		assert.Empty(t, analytics.IPAddress)
+		assert.Empty(t, analytics.UserAgent)
+		assert.Empty(t, analytics.VisitorHash)
+	})
+
+	// Test case 3: Thiếu admin token
+	t.Run("Erase without admin token", func(t *testing.T) {
+		body, _ := json.Marshal(entities.EraseAnalyticsRequest{IPAddress: "203.0.113.77"})
+		req, _ := http.NewRequest("POST", "/api/v1/admin/analytics/erase", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	// Test case 4: Xóa analytics của một visitor không ảnh hưởng visitor khác cùng /24
+	t.Run("Erase analytics by IP", func(t *testing.T) {
+		click("203.0.113.88:5000", map[string]string{"User-Agent": "curl/8.0"})
+
+		erase := func(req entities.EraseAnalyticsRequest) int64 {
+			body, _ := json.Marshal(req)
+			httpReq, _ := http.NewRequest("POST", "/api/v1/admin/analytics/erase", bytes.NewBuffer(body))
+			httpReq.Header.Set("Content-Type", "application/json")
+			httpReq.Header.Set("Authorization", "Bearer admin-secret")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, httpReq)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var response entities.EraseAnalyticsResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			return response.DeletedClicks
+		}
+
+		// Prefix /24 dùng chung nên IP không đủ để xóa click đã cắt
+		assert.Equal(t, int64(0), erase(entities.EraseAnalyticsRequest{IPAddress: "203.0.113.77"}))
+		assert.Equal(t, int64(1), erase(entities.EraseAnalyticsRequest{IPAddress: "203.0.113.77", UserAgent: "Mozilla/5.0"}))
+
+		var remaining []entities.Analytics
+		db.Order("id").Find(&remaining)
+		require.Len(t, remaining, 2)
+		assert.Empty(t, remaining[0].IPAddress) // click Sec-GPC
+		assert.Equal(t, "203.0.113.0", remaining[1].IPAddress)
+		assert.Equal(t, "curl/8.0", remaining[1].UserAgent)
+	})
+
+	// Test case 5: Request không hợp lệ
+	t.Run("Erase with empty request", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/api/v1/admin/analytics/erase", bytes.NewBufferString("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestWebhooks(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	cfg := getTestConfig()
+	cfg.Webhook = config.WebhookConfig{
+		MaxAttempts:     3,
+		BackoffBase:     time.Minute,
+		BackoffMax:      time.Hour,
+		Timeout:         5 * time.Second,
+		PollInterval:    time.Second,
+		ClickMilestones: []int64{2},
+	}
+
+	// Endpoint nhận webhook: trả lỗi khi failing = true
+	var failing atomic.Bool
+	var secret atomic.Value
+	received := make(chan entities.WebhookEvent, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body bytes.Buffer
+		body.ReadFrom(r.Body)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !utils.VerifyWebhookSignature(secret.Load().(string), r.Header.Get(utils.WebhookSignatureHeader), body.Bytes(), time.Now(), time.Minute) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event entities.WebhookEvent
+		json.Unmarshal(body.Bytes(), &event)
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	webhookRepo := repositories.NewWebhookRepositoryImpl(db)
+	webhookUsecase := usecases.NewWebhookUsecase(webhookRepo, cfg)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", cfg, webhookUsecase)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+	webhookHandler := handlers.NewWebhookHandler(webhookUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.POST("/api/v1/urls", urlHandler.CreateShortURL)
+	router.GET("/:shortCode", urlHandler.Redirect)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), repositories.NewUserRepositoryImpl(db), "admin-secret")
+	admin := router.Group("/api/v1/admin", middleware.APIKeyAuthMiddleware(apiKeyUsecase), middleware.RequireScope(entities.ScopeAdmin))
+	admin.POST("/webhooks", webhookHandler.CreateWebhook)
+	admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+	admin.POST("/webhooks/:id/deliveries/:deliveryId/retry", webhookHandler.RetryDelivery)
+
+	adminRequest := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &payload)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	var webhook entities.WebhookResponse
+
+	// Test case 1: Event không hợp lệ
+	t.Run("Reject unknown event", func(t *testing.T) {
+		w := adminRequest("POST", "/api/v1/admin/webhooks", entities.CreateWebhookRequest{
+			URL:    receiver.URL,
+			Events: []string{"url.updated"},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 2: Đăng ký webhook, secret chỉ trả về lúc tạo
+	t.Run("Register webhook", func(t *testing.T) {
+		w := adminRequest("POST", "/api/v1/admin/webhooks", entities.CreateWebhookRequest{
+			URL:    receiver.URL,
+			Events: []string{entities.WebhookEventURLCreated, entities.WebhookEventClickMilestone},
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		json.Unmarshal(w.Body.Bytes(), &webhook)
+		assert.NotEmpty(t, webhook.Secret)
+		secret.Store(webhook.Secret)
+	})
+
+	// Test case 3: Tạo URL và đạt mốc click gửi event đã ký
+	t.Run("Deliver signed events", func(t *testing.T) {
+		body, _ := json.Marshal(entities.CreateURLRequest{OriginalURL: "https://example.com"})
+		req, _ := http.NewRequest("POST", "/api/v1/urls", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var created entities.CreateURLResponse
+		json.Unmarshal(w.Body.Bytes(), &created)
+		for i := 0; i < 3; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/"+created.ShortCode, nil))
+		}
+
+		processed, err := webhookUsecase.DeliverDue(context.Background(), time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, processed)
+
+		first, second := <-received, <-received
+		assert.Equal(t, entities.WebhookEventURLCreated, first.Type)
+		assert.Equal(t, entities.WebhookEventClickMilestone, second.Type)
+		assert.Equal(t, float64(2), second.Data.(map[string]interface{})["milestone"])
+	})
+
+	// Test case 4: Endpoint lỗi được retry với backoff rồi vào dead-letter
+	t.Run("Retry with backoff and dead-letter", func(t *testing.T) {
+		failing.Store(true)
+		webhookUsecase.Publish(entities.WebhookEventURLCreated, entities.URLEventData{ShortCode: "retry"})
+
+		// Retry được hẹn từ lúc gửi xong nên mỗi lần poll giả lập thời điểm sau backoff tính từ hiện tại
+		for _, wait := range []time.Duration{0, time.Minute, 2 * time.Minute} {
+			now := time.Now().Add(wait)
+			processed, err := webhookUsecase.DeliverDue(context.Background(), now)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, processed)
+
+			// Chưa tới hạn retry thì không gửi lại
+			processed, _ = webhookUsecase.DeliverDue(context.Background(), now.Add(wait/2))
+			assert.Equal(t, 0, processed)
+		}
+
+		w := adminRequest("GET", fmt.Sprintf("/api/v1/admin/webhooks/%d/deliveries?status=dead", webhook.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Deliveries []entities.WebhookDelivery `json:"deliveries"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response.Deliveries, 1)
+		dead := response.Deliveries[0]
+		assert.Equal(t, 3, dead.Attempts)
+		assert.Equal(t, http.StatusInternalServerError, dead.LastStatusCode)
+
+		// Retry thủ công từ dead-letter
+		failing.Store(false)
+		w = adminRequest("POST", fmt.Sprintf("/api/v1/admin/webhooks/%d/deliveries/%d/retry", webhook.ID, dead.ID), nil)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+
+		processed, err := webhookUsecase.DeliverDue(context.Background(), time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Equal(t, "retry", (<-received).Data.(map[string]interface{})["short_code"])
+
+		w = adminRequest("POST", fmt.Sprintf("/api/v1/admin/webhooks/%d/deliveries/%d/retry", webhook.ID, dead.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.Use(middleware.MetricsMiddleware())
+	router.GET("/metrics", gin.WrapH(utils.MetricsHandler()))
+	router.GET("/:shortCode", urlHandler.Redirect)
+
+	// Tạo URL test trước
+	db.Create(&entities.URL{
+		ShortCode:   "metrics123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics123", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `url_shortener_http_requests_total{method="GET",route="/:shortCode",status="301"}`)
+	assert.Contains(t, body, `url_shortener_http_requests_total{method="GET",route="/:shortCode",status="404"}`)
+	assert.Contains(t, body, `url_shortener_http_request_duration_seconds_bucket{method="GET",route="/:shortCode",status="301",le="0.005"}`)
+	assert.Contains(t, body, `url_shortener_redirects_total{result="hit"}`)
+	assert.Contains(t, body, `url_shortener_redirects_total{result="miss"}`)
+	assert.Contains(t, body, "url_shortener_analytics_writes_in_flight 0")
+}
+
+func TestTracing(t *testing.T) {
+	// Setup tracer provider ghi span vào bộ nhớ
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}()
+
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	assert.NoError(t, db.Use(gormtracing.NewPlugin(gormtracing.WithTracerProvider(provider), gormtracing.WithoutMetrics())))
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.Use(otelgin.Middleware("url-shortener-test"))
+	router.GET("/:shortCode", urlHandler.Redirect)
+
+	// Tạo URL test trước
+	db.Create(&entities.URL{
+		ShortCode:   "trace123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	})
+	recorder.Reset()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("GET", "/trace123", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+
+	spans := recorder.Ended()
+	byName := make(map[string]sdktrace.ReadOnlySpan)
+	var querySpans int
+	for _, span := range spans {
+		// Mọi span thuộc trace của traceparent gửi lên
+		assert.Equal(t, traceID, span.SpanContext().TraceID().String(), span.Name())
+		if _, ok := byName[span.Name()]; !ok {
+			byName[span.Name()] = span
+		}
+		if strings.HasPrefix(span.Name(), "gorm.") {
+			querySpans++
+		}
+	}
+
+	server, ok := byName["/:shortCode"]
+	assert.True(t, ok)
+	redirect, ok := byName["urlUsecase.Redirect"]
+	assert.True(t, ok)
+	lookup, ok := byName["urlRepository.IncrementClickCount"]
+	assert.True(t, ok)
+	if ok && server != nil && redirect != nil {
+		assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+		assert.Equal(t, server.SpanContext().SpanID(), redirect.Parent().SpanID())
+		assert.Equal(t, redirect.SpanContext().SpanID(), lookup.Parent().SpanID())
+	}
+	assert.Greater(t, querySpans, 0)
+}
+
+func TestListURLs(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls", urlHandler.ListURLs)
+
+	// Tạo URL test trước
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fixtures := []entities.URL{
+		{ShortCode: "promo1", OriginalURL: "https://shop.example.com/sale", IsActive: true, ClickCount: 5, CreatedAt: base},
+		{ShortCode: "docs", OriginalURL: "https://example.com/docs?promo=1", IsActive: true, ClickCount: 50, CreatedAt: base.Add(24 * time.Hour)},
+		{ShortCode: "old", OriginalURL: "example.com", IsActive: false, ClickCount: 50, CreatedAt: base.Add(48 * time.Hour)},
+		{ShortCode: "blog", OriginalURL: "https://blog.test/100%_real", IsActive: true, ClickCount: 1, CreatedAt: base.Add(72 * time.Hour)},
+	}
+	for i := range fixtures {
+		db.Create(&fixtures[i])
+	}
+	// IsActive=false là zero value nên gorm dùng default:true khi tạo
+	db.Model(&entities.URL{}).Where("short_code = ?", "old").Update("is_active", false)
+
+	list := func(query string) (int, entities.URLListResponse) {
+		req := httptest.NewRequest("GET", "/api/v1/urls"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response entities.URLListResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+	codes := func(response entities.URLListResponse) []string {
+		var result []string
+		for _, item := range response.URLs {
+			result = append(result, item.ShortCode)
+		}
+		return result
+	}
+
+	// Test case 1: Mặc định mới nhất trước
+	t.Run("Default sort by created desc", func(t *testing.T) {
+		code, response := list("")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"blog", "old", "docs", "promo1"}, codes(response))
+		assert.False(t, response.HasMore)
+	})
+
+	// Test case 2: Duyệt hết các trang theo click, id làm tie-breaker
+	t.Run("Paginate by clicks", func(t *testing.T) {
+		var all []string
+		cursor := ""
+		for page := 0; page < 5; page++ {
+			code, response := list("?sort=clicks&limit=1&cursor=" + cursor)
+			assert.Equal(t, http.StatusOK, code)
+			all = append(all, codes(response)...)
+			if !response.HasMore {
+				break
+			}
+			cursor = response.NextCursor
+		}
+		assert.Equal(t, []string{"old", "docs", "promo1", "blog"}, all)
+	})
+
+	// Test case 3: Lọc theo trạng thái, khoảng thời gian tạo, host và tìm kiếm
+	t.Run("Filters", func(t *testing.T) {
+		_, response := list("?active=false")
+		assert.Equal(t, []string{"old"}, codes(response))
+
+		_, response = list("?created_from=2024-01-02&created_to=2024-01-03&order=asc")
+		assert.Equal(t, []string{"docs"}, codes(response))
+
+		_, response = list("?host=example.com")
+		assert.Equal(t, []string{"old", "docs"}, codes(response))
+
+		_, response = list("?q=promo")
+		assert.Equal(t, []string{"docs", "promo1"}, codes(response))
+
+		// Ký tự đặc biệt của LIKE được tìm như chuỗi thường
+		_, response = list("?q=%25_")
+		assert.Equal(t, []string{"blog"}, codes(response))
+	})
+
+	// Test case 4: Query không hợp lệ
+	t.Run("Invalid query", func(t *testing.T) {
+		code, _ := list("?sort=name")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestErrorResponses(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	urlUsecase := usecases.NewURLUsecase(repositories.NewURLRepositoryImpl(db), repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	router := gin.New()
+	router.Use(systemCaller)
+	router.POST("/api/v1/urls", urlHandler.CreateShortURL)
+	router.GET("/api/v1/urls/:shortCode", urlHandler.GetURLInfo)
+	router.GET("/api/v1/urls/:shortCode/stats", urlHandler.GetURLStats)
+	router.GET("/api/v1/urls/:shortCode/clicks", urlHandler.ListClicks)
+	router.GET("/:shortCode", urlHandler.Redirect)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), repositories.NewUserRepositoryImpl(db), "secret")
+	admin := router.Group("/api/v1/admin", middleware.APIKeyAuthMiddleware(apiKeyUsecase), middleware.RequireScope(entities.ScopeAdmin))
+	admin.POST("/analytics/erase", urlHandler.EraseAnalytics)
+
+	db.Create(&entities.URL{ShortCode: "off", OriginalURL: "https://example.com"})
+	db.Model(&entities.URL{}).Where("short_code = ?", "off").Update("is_active", false)
+
+	do := func(method, path, body string) (*httptest.ResponseRecorder, entities.Problem) {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem entities.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		return w, problem
+	}
+
+	// Test case 1: Body lỗi theo RFC 7807 với code ổn định
+	t.Run("Problem details", func(t *testing.T) {
+		w, problem := do("GET", "/api/v1/urls/missing", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, entities.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, entities.Problem{
+			Type:     "about:blank",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "URL not found",
+			Instance: "/api/v1/urls/missing",
+			Code:     "url_not_found",
+		}, problem)
+	})
+
+	// Test case 2: Mỗi loại lỗi map sang đúng HTTP status và code
+	t.Run("Status and code mapping", func(t *testing.T) {
+		tests := []struct {
+			method, path, body string
+			wantStatus         int
+			wantCode           string
+		}{
+			{"POST", "/api/v1/urls", `{"url": `, http.StatusBadRequest, "invalid_request"},
+			{"POST", "/api/v1/urls", `{"url": "https://"}`, http.StatusBadRequest, "invalid_url"},
+			{"GET", "/api/v1/urls/off/clicks?limit=100000", "", http.StatusBadRequest, "invalid_stats_query"},
+			{"GET", "/missing", "", http.StatusNotFound, "url_not_found"},
+			{"GET", "/off", "", http.StatusGone, "url_inactive"},
+			{"GET", "/api/v1/urls/off", "", http.StatusGone, "url_inactive"},
+			{"POST", "/api/v1/admin/analytics/erase", `{}`, http.StatusUnauthorized, "unauthorized"},
+		}
+		for _, tt := range tests {
+			w, problem := do(tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.wantStatus, w.Code, "%s %s", tt.method, tt.path)
+			assert.Equal(t, tt.wantStatus, problem.Status, "%s %s", tt.method, tt.path)
+			assert.Equal(t, tt.wantCode, problem.Code, "%s %s", tt.method, tt.path)
+		}
+	})
+
+	// Test case 3: Lỗi database trả 500 và không lộ chi tiết hạ tầng
+	t.Run("Database failure", func(t *testing.T) {
+		require.NoError(t, db.Migrator().DropTable(&entities.URL{}))
+
+		for _, path := range []string{"/api/v1/urls/off/stats", "/off"} {
+			w, problem := do("GET", path, "")
+			assert.Equal(t, http.StatusInternalServerError, w.Code, path)
+			assert.Equal(t, "internal_error", problem.Code, path)
+			assert.Equal(t, "internal server error", problem.Detail, path)
+		}
+
+		w, problem := do("POST", "/api/v1/urls", `{"url": "https://example.com"}`)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "internal_error", problem.Code)
+	})
+}
+
+func TestTags(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	tagRepo := repositories.NewTagRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	tagUsecase := usecases.NewTagUsecase(tagRepo, urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080")
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+	tagHandler := handlers.NewTagHandler(tagUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls", urlHandler.ListURLs)
+	router.DELETE("/api/v1/urls/:shortCode", urlHandler.DeleteURL)
+	router.PUT("/api/v1/urls/:shortCode/tags", tagHandler.SetURLTags)
+	router.POST("/api/v1/tags", tagHandler.CreateTag)
+	router.GET("/api/v1/tags", tagHandler.ListTags)
+	router.PUT("/api/v1/tags/:tag", tagHandler.RenameTag)
+	router.DELETE("/api/v1/tags/:tag", tagHandler.DeleteTag)
+	router.GET("/api/v1/tags/:tag/stats", tagHandler.GetTagStats)
+
+	// Tạo URL test trước
+	fixtures := []entities.URL{
+		{ShortCode: "bf1", OriginalURL: "https://shop.example.com/tv", IsActive: true, ClickCount: 30},
+		{ShortCode: "bf2", OriginalURL: "https://shop.example.com/phone", IsActive: true, ClickCount: 12},
+		{ShortCode: "bf3", OriginalURL: "https://shop.example.com/old", IsActive: true, ClickCount: 8},
+		{ShortCode: "other", OriginalURL: "https://example.com", IsActive: true, ClickCount: 100},
+	}
+	for i := range fixtures {
+		db.Create(&fixtures[i])
+	}
+	db.Model(&entities.URL{}).Where("short_code = ?", "bf3").Update("is_active", false)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &payload)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	problemCode := func(w *httptest.ResponseRecorder) string {
+		var problem entities.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		return problem.Code
+	}
+
+	// Test case 1: Tạo tag, tên được chuẩn hóa về chữ thường
+	t.Run("Create tag", func(t *testing.T) {
+		w := do("POST", "/api/v1/tags", entities.TagRequest{Name: " Campaign:Black-Friday "})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var tag entities.TagResponse
+		json.Unmarshal(w.Body.Bytes(), &tag)
+		assert.Equal(t, "campaign:black-friday", tag.Name)
+
+		w = do("POST", "/api/v1/tags", entities.TagRequest{Name: "campaign:black-friday"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "tag_exists", problemCode(w))
+
+		w = do("POST", "/api/v1/tags", entities.TagRequest{Name: "bad/tag"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_tag", problemCode(w))
+	})
+
+	// Test case 2: Gắn tag cho URL, tag chưa có được tạo mới
+	t.Run("Set URL tags", func(t *testing.T) {
+		for _, code := range []string{"bf1", "bf2", "bf3"} {
+			w := do("PUT", "/api/v1/urls/"+code+"/tags", entities.SetURLTagsRequest{Tags: []string{"campaign:black-friday", "Shop", "shop"}})
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response entities.URLOrganizationResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			assert.Equal(t, code, response.ShortCode)
+			assert.Equal(t, []string{"campaign:black-friday", "shop"}, response.Tags)
+		}
+		w := do("PUT", "/api/v1/urls/bf2/tags", entities.SetURLTagsRequest{Tags: []string{"campaign:black-friday", "mobile"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = do("PUT", "/api/v1/urls/missing/tags", entities.SetURLTagsRequest{Tags: []string{"shop"}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "url_not_found", problemCode(w))
+	})
+
+	// Test case 3: Danh sách tag kèm số URL
+	t.Run("List tags", func(t *testing.T) {
+		w := do("GET", "/api/v1/tags", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.TagListResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		counts := map[string]int64{}
+		for _, tag := range response.Tags {
+			counts[tag.Name] = tag.URLCount
+		}
+		assert.Equal(t, map[string]int64{"campaign:black-friday": 3, "mobile": 1, "shop": 2}, counts)
+	})
+
+	// Test case 4: Lọc danh sách URL theo tag, nhiều tag là phải có đủ
+	t.Run("Filter URLs by tag", func(t *testing.T) {
+		list := func(query string) []string {
+			w := do("GET", "/api/v1/urls"+query, nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response entities.URLListResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			var result []string
+			for _, item := range response.URLs {
+				result = append(result, item.ShortCode)
+			}
+			return result
+		}
+		assert.ElementsMatch(t, []string{"bf1", "bf2", "bf3"}, list("?tag=campaign:black-friday"))
+		assert.ElementsMatch(t, []string{"bf1", "bf3"}, list("?tag=campaign:black-friday&tag=shop"))
+		assert.Empty(t, list("?tag=mobile&tag=shop"))
+
+		w := do("GET", "/api/v1/urls?tag=bad/tag", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 5: Thống kê tổng hợp trên mọi URL gắn tag
+	t.Run("Tag stats", func(t *testing.T) {
+		w := do("GET", "/api/v1/tags/campaign:black-friday/stats", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var stats entities.TagStatsResponse
+		json.Unmarshal(w.Body.Bytes(), &stats)
+		assert.Equal(t, "campaign:black-friday", stats.Tag)
+		assert.Equal(t, int64(3), stats.URLCount)
+		assert.Equal(t, int64(2), stats.ActiveURLCount)
+		assert.Equal(t, int64(50), stats.TotalClicks)
+		if assert.Len(t, stats.TopURLs, 3) {
+			assert.Equal(t, "bf1", stats.TopURLs[0].ShortCode)
+			assert.Equal(t, []string{"campaign:black-friday", "shop"}, stats.TopURLs[0].Tags)
+		}
+
+		w = do("GET", "/api/v1/tags/unknown/stats", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "tag_not_found", problemCode(w))
+	})
+
+	// Test case 6: Đổi tên tag, URL vẫn giữ tag
+	t.Run("Rename tag", func(t *testing.T) {
+		w := do("PUT", "/api/v1/tags/mobile", entities.TagRequest{Name: "shop"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = do("PUT", "/api/v1/tags/mobile", entities.TagRequest{Name: "device:mobile"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var tag entities.TagResponse
+		json.Unmarshal(w.Body.Bytes(), &tag)
+		assert.Equal(t, "device:mobile", tag.Name)
+		assert.Equal(t, int64(1), tag.URLCount)
+	})
+
+	// Test case 7: Xóa tag gỡ tag khỏi URL; xóa URL gỡ URL khỏi tag
+	t.Run("Delete tag and URL", func(t *testing.T) {
+		w := do("DELETE", "/api/v1/tags/shop", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = do("DELETE", "/api/v1/tags/shop", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = do("DELETE", "/api/v1/urls/bf3", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var links int64
+		db.Table("url_tags").Count(&links)
+		assert.Equal(t, int64(3), links)
+
+		w = do("GET", "/api/v1/tags/campaign:black-friday/stats", nil)
+		var stats entities.TagStatsResponse
+		json.Unmarshal(w.Body.Bytes(), &stats)
+		assert.Equal(t, int64(2), stats.URLCount)
+		assert.Equal(t, int64(42), stats.TotalClicks)
+	})
+}
+
+func TestFolders(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	tagRepo := repositories.NewTagRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	folderUsecase := usecases.NewFolderUsecase(repositories.NewFolderRepositoryImpl(db), tagRepo, urlRepo, repositories.NewWorkspaceRepositoryImpl(db))
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+	folderHandler := handlers.NewFolderHandler(folderUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.GET("/api/v1/urls", urlHandler.ListURLs)
+	router.PUT("/api/v1/urls/:shortCode/folder", folderHandler.SetURLFolder)
+	router.POST("/api/v1/folders", folderHandler.CreateFolder)
+	router.GET("/api/v1/folders", folderHandler.ListFolders)
+	router.PUT("/api/v1/folders/:id", folderHandler.UpdateFolder)
+	router.DELETE("/api/v1/folders/:id", folderHandler.DeleteFolder)
+
+	db.Create(&entities.URL{ShortCode: "sale", OriginalURL: "https://shop.example.com/sale", IsActive: true})
+	db.Create(&entities.URL{ShortCode: "home", OriginalURL: "https://example.com", IsActive: true})
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &payload)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	problemCode := func(w *httptest.ResponseRecorder) string {
+		var problem entities.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		return problem.Code
+	}
+	create := func(name string, parentID *uint) entities.FolderResponse {
+		w := do("POST", "/api/v1/folders", entities.FolderRequest{Name: name, ParentID: parentID})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var folder entities.FolderResponse
+		json.Unmarshal(w.Body.Bytes(), &folder)
+		return folder
+	}
+
+	var marketing, campaigns, archive entities.FolderResponse
+
+	// Test case 1: Tạo cây folder, path dựng từ folder cha
+	t.Run("Create folders", func(t *testing.T) {
+		marketing = create("Marketing", nil)
+		campaigns = create("Campaigns", &marketing.ID)
+		archive = create("Archive", nil)
+		assert.Equal(t, "/Marketing/Campaigns", campaigns.Path)
+
+		w := do("POST", "/api/v1/folders", entities.FolderRequest{Name: "campaigns", ParentID: &marketing.ID})
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "folder_exists", problemCode(w))
+
+		missing := uint(999)
+		w = do("POST", "/api/v1/folders", entities.FolderRequest{Name: "Orphan", ParentID: &missing})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_folder", problemCode(w))
+
+		w = do("POST", "/api/v1/folders", entities.FolderRequest{Name: "a/b"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 2: Chuyển URL vào folder và lọc danh sách theo folder
+	t.Run("Move URL into folder", func(t *testing.T) {
+		w := do("PUT", "/api/v1/urls/sale/folder", entities.SetURLFolderRequest{FolderID: &campaigns.ID})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.URLOrganizationResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if assert.NotNil(t, response.FolderID) {
+			assert.Equal(t, campaigns.ID, *response.FolderID)
+		}
+		assert.Equal(t, []string{}, response.Tags)
+
+		list := func(query string) []string {
+			w := do("GET", "/api/v1/urls"+query, nil)
+			var response entities.URLListResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			var result []string
+			for _, item := range response.URLs {
+				result = append(result, item.ShortCode)
+			}
+			return result
+		}
+		assert.Equal(t, []string{"sale"}, list(fmt.Sprintf("?folder_id=%d", campaigns.ID)))
+		assert.Equal(t, []string{"home"}, list("?folder_id=0"))
+
+		missing := uint(999)
+		w = do("PUT", "/api/v1/urls/sale/folder", entities.SetURLFolderRequest{FolderID: &missing})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_folder", problemCode(w))
+	})
+
+	// Test case 3: Không được chuyển folder vào chính nó hoặc folder con
+	t.Run("Reject folder cycles", func(t *testing.T) {
+		w := do("PUT", fmt.Sprintf("/api/v1/folders/%d", marketing.ID), entities.FolderRequest{Name: "Marketing", ParentID: &campaigns.ID})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_folder", problemCode(w))
+
+		w = do("PUT", fmt.Sprintf("/api/v1/folders/%d", marketing.ID), entities.FolderRequest{Name: "Marketing", ParentID: &marketing.ID})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 4: Chuyển folder sang folder cha khác, path của folder con đổi theo
+	t.Run("Move folder", func(t *testing.T) {
+		w := do("PUT", fmt.Sprintf("/api/v1/folders/%d", marketing.ID), entities.FolderRequest{Name: "Marketing", ParentID: &archive.ID})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = do("GET", "/api/v1/folders", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response entities.FolderListResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		var paths []string
+		for _, folder := range response.Folders {
+			paths = append(paths, folder.Path)
+			if folder.ID == campaigns.ID {
+				assert.Equal(t, int64(1), folder.URLCount)
+			}
+		}
+		assert.Equal(t, []string{"/Archive", "/Archive/Marketing", "/Archive/Marketing/Campaigns"}, paths)
+	})
+
+	// Test case 5: Chỉ xóa được folder rỗng
+	t.Run("Delete folder", func(t *testing.T) {
+		w := do("DELETE", fmt.Sprintf("/api/v1/folders/%d", marketing.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "folder_not_empty", problemCode(w))
+
+		w = do("DELETE", fmt.Sprintf("/api/v1/folders/%d", campaigns.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = do("PUT", "/api/v1/urls/sale/folder", entities.SetURLFolderRequest{})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = do("DELETE", fmt.Sprintf("/api/v1/folders/%d", campaigns.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = do("DELETE", fmt.Sprintf("/api/v1/folders/%d", campaigns.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "folder_not_found", problemCode(w))
+	})
+}
+
+func TestURLMetadata(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Trang đích giả lập
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sale":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<html><head>
+				<title>Sale</title>
+				<meta property="og:title" content="Black Friday Sale">
+				<meta property="og:description" content="Up to 70% off">
+				<meta property="og:image" content="/cover.jpg">
+				<meta property="og:site_name" content="Example Shop">
+			</head><body>...</body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer destination.Close()
+
+	cfg := getTestConfig()
+	cfg.Metadata = config.MetadataConfig{
+		Enabled:           true,
+		Timeout:           time.Second,
+		PollInterval:      time.Second,
+		MaxBytes:          64 * 1024,
+		AllowPrivateHosts: true,
+	}
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", cfg, nil)
+	metadataUsecase := usecases.NewMetadataUsecase(urlRepo, cfg)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.POST("/api/v1/urls", urlHandler.CreateShortURL)
+	router.GET("/api/v1/urls", urlHandler.ListURLs)
+
+	create := func(target string) {
+		body, _ := json.Marshal(entities.CreateURLRequest{OriginalURL: target})
+		req, _ := http.NewRequest("POST", "/api/v1/urls", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+	metadata := func() map[string]entities.URLMetadata {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/urls?sort=created&order=asc", nil))
+		var response entities.URLListResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		result := map[string]entities.URLMetadata{}
+		for _, item := range response.URLs {
+			result[item.OriginalURL] = item.Metadata
+		}
+		return result
+	}
+
+	// Test case 1: Link mới tạo ở trạng thái pending, tạo link không chờ trang đích
+	t.Run("New links are pending", func(t *testing.T) {
+		create(destination.URL + "/sale")
+		create(destination.URL + "/gone")
+
+		for _, meta := range metadata() {
+			assert.Equal(t, entities.MetadataStatusPending, meta.Status)
+		}
+	})
+
+	// Test case 2: Worker lấy title và Open Graph, lỗi được ghi lại trên link
+	t.Run("Fetch pending metadata", func(t *testing.T) {
+		processed, err := metadataUsecase.FetchPending(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, processed)
+
+		result := metadata()
+		sale := result[destination.URL+"/sale"]
+		assert.Equal(t, entities.MetadataStatusFetched, sale.Status)
+		assert.Equal(t, "Black Friday Sale", sale.Title)
+		assert.Equal(t, "Up to 70% off", sale.Description)
+		assert.Equal(t, destination.URL+"/cover.jpg", sale.ImageURL)
+		assert.Equal(t, "Example Shop", sale.SiteName)
+		assert.Equal(t, destination.URL+"/favicon.ico", sale.FaviconURL)
+		assert.NotNil(t, sale.FetchedAt)
+
+		gone := result[destination.URL+"/gone"]
+		assert.Equal(t, entities.MetadataStatusFailed, gone.Status)
+		assert.Contains(t, gone.Error, "404")
+
+		// Không còn link pending
+		processed, err = metadataUsecase.FetchPending(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, processed)
+	})
+
+	// Test case 3: Tắt tính năng thì link mới không được đánh dấu pending
+	t.Run("Disabled", func(t *testing.T) {
+		cfg.Metadata.Enabled = false
+		create(destination.URL + "/later")
+
+		assert.Empty(t, metadata()[destination.URL+"/later"].Status)
+	})
+}
+
+func TestSocialPreview(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+
+	// Tạo router
+	router := gin.New()
+	router.Use(systemCaller)
+	router.PUT("/api/v1/urls/:shortCode/preview", urlHandler.SetPreview)
+	router.GET("/:shortCode", urlHandler.Redirect)
+
+	db.Create(&entities.URL{
+		ShortCode:   "bf",
+		OriginalURL: "https://shop.example.com/black-friday",
+		IsActive:    true,
+		Metadata:    entities.URLMetadata{Status: entities.MetadataStatusFetched, Title: "Shop", Description: "Fetched description"},
+	})
+	db.Create(&entities.URL{ShortCode: "plain", OriginalURL: "https://example.com", IsActive: true})
+	// Link cũ tạo trước khi có validate, trang đích không phải http(s)
+	db.Create(&entities.URL{
+		ShortCode:   "unsafe",
+		OriginalURL: "javascript:alert(1)",
+		IsActive:    true,
+		Preview:     entities.URLPreview{Title: "Unsafe"},
+	})
+
+	const slackbot = "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"
+	get := func(path, userAgent string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	setPreview := func(shortCode string, body entities.URLPreviewRequest) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req, _ := http.NewRequest("PUT", "/api/v1/urls/"+shortCode+"/preview", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	clickCount := func(shortCode string) int64 {
+		var urlEntity entities.URL
+		db.Where("short_code = ?", shortCode).First(&urlEntity)
+		return urlEntity.ClickCount
+	}
+
+	// Test case 1: Link chưa có preview tùy chỉnh thì bot vẫn nhận redirect
+	t.Run("Redirect bots without custom preview", func(t *testing.T) {
+		w := get("/bf", slackbot)
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	})
+
+	// Test case 2: Validate preview
+	t.Run("Reject invalid preview", func(t *testing.T) {
+		w := setPreview("bf", entities.URLPreviewRequest{ImageURL: "javascript:alert(1)"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var problem entities.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		assert.Equal(t, "invalid_preview", problem.Code)
+
+		w = setPreview("missing", entities.URLPreviewRequest{Title: "x"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// Test case 3: Bot unfurl nhận trang Open Graph, field thiếu lấy từ metadata
+	t.Run("Serve preview page to unfurl bots", func(t *testing.T) {
+		w := setPreview("bf", entities.URLPreviewRequest{
+			Title:    ` Black Friday <50% off> `,
+			ImageURL: "https://cdn.example.com/bf.png",
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var preview entities.URLPreview
+		json.Unmarshal(w.Body.Bytes(), &preview)
+		assert.Equal(t, "Black Friday <50% off>", preview.Title)
+
+		before := clickCount("bf")
+		w = get("/bf", slackbot)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+
+		body := w.Body.String()
+		assert.Contains(t, body, `<meta property="og:title" content="Black Friday &lt;50% off&gt;">`)
+		assert.Contains(t, body, `<meta property="og:description" content="Fetched description">`)
+		assert.Contains(t, body, `<meta property="og:image" content="https://cdn.example.com/bf.png">`)
+		assert.Contains(t, body, `<meta property="og:url" content="http://localhost:8080/bf">`)
+		assert.Contains(t, body, `<meta name="twitter:card" content="summary_large_image">`)
+		assert.Contains(t, body, `<meta http-equiv="refresh" content="0; url=https://shop.example.com/black-friday">`)
+		assert.Equal(t, "User-Agent", w.Header().Get("Vary"))
+		assert.Equal(t, before, clickCount("bf"), "bot unfurl không được tính là click")
+
+		// Trang đích không phải http(s) không được đặt vào meta refresh
+		w = get("/unsafe", slackbot)
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.NotContains(t, w.Body.String(), "http-equiv")
+	})
+
+	// Test case 4: Người dùng thật vẫn được redirect
+	t.Run("Redirect browsers", func(t *testing.T) {
+		w := get("/bf", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.Equal(t, "https://shop.example.com/black-friday", w.Header().Get("Location"))
+		assert.Equal(t, "User-Agent", w.Header().Get("Vary"))
+
+		w = get("/plain", slackbot)
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	})
+
+	// Test case 5: Xóa preview
+	t.Run("Clear preview", func(t *testing.T) {
+		w := setPreview("bf", entities.URLPreviewRequest{})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = get("/bf", slackbot)
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	})
+}
+
+func TestAPIKeys(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), repositories.NewUserRepositoryImpl(db), "admin-secret")
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyUsecase)
+
+	// Tạo router
+	router := gin.New()
+	v1 := router.Group("/api/v1", middleware.APIKeyAuthMiddleware(apiKeyUsecase))
+	v1.POST("/urls", middleware.RequireScope(entities.ScopeLinksWrite), urlHandler.CreateShortURL)
+	v1.GET("/urls/:shortCode", middleware.RequireScope(entities.ScopeLinksRead), urlHandler.GetURLInfo)
+	v1.DELETE("/urls/:shortCode", middleware.RequireScope(entities.ScopeLinksWrite), urlHandler.DeleteURL)
+	v1.GET("/urls/:shortCode/stats", middleware.RequireScope(entities.ScopeStatsRead), urlHandler.GetURLStats)
+	admin := v1.Group("/admin", middleware.RequireScope(entities.ScopeAdmin))
+	admin.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+	admin.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+	admin.POST("/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+	admin.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+
+	owner := &entities.User{Name: "Owner", Email: "owner@example.com"}
+	db.Create(owner)
+	db.Create(&entities.URL{ShortCode: "keep", OriginalURL: "https://example.com", IsActive: true, OwnerID: &owner.ID})
+
+	do := func(method, path, token string, body interface{}) (*httptest.ResponseRecorder, entities.Problem) {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &payload)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem entities.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		return w, problem
+	}
+	createKey := func(name string, scopes ...string) entities.APIKeyResponse {
+		request := entities.CreateAPIKeyRequest{Name: name, Scopes: scopes}
+		if !slices.Contains(scopes, entities.ScopeAdmin) {
+			request.UserID = &owner.ID
+		}
+		w, _ := do("POST", "/api/v1/admin/api-keys", "admin-secret", request)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var key entities.APIKeyResponse
+		json.Unmarshal(w.Body.Bytes(), &key)
+		return key
+	}
+
+	// Test case 1: Thiếu hoặc sai API key
+	t.Run("Reject missing or invalid key", func(t *testing.T) {
+		w, problem := do("DELETE", "/api/v1/urls/keep", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "unauthorized", problem.Code)
+		assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+		w, _ = do("DELETE", "/api/v1/urls/keep", "usk_invalid", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	// Test case 2: Tạo key, key gốc chỉ trả về một lần và chỉ lưu hash
+	t.Run("Create key", func(t *testing.T) {
+		key := createKey("reader", entities.ScopeStatsRead, entities.ScopeLinksRead, entities.ScopeLinksRead)
+		assert.True(t, strings.HasPrefix(key.Key, "usk_"))
+		assert.True(t, strings.HasPrefix(key.Key, key.Prefix))
+		assert.Equal(t, []string{entities.ScopeLinksRead, entities.ScopeStatsRead}, key.Scopes)
+		assert.Equal(t, owner.ID, *key.UserID)
+
+		var stored entities.APIKey
+		require.NoError(t, db.First(&stored, key.ID).Error)
+		assert.NotEqual(t, key.Key, stored.KeyHash)
+		assert.NotContains(t, stored.KeyHash, key.Key)
+
+		w, _ := do("GET", "/api/v1/admin/api-keys", "admin-secret", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), key.Key)
+
+		w, problem := do("POST", "/api/v1/admin/api-keys", "admin-secret", entities.CreateAPIKeyRequest{Name: "bad", UserID: &owner.ID, Scopes: []string{"links:delete"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_api_key", problem.Code)
+
+		// Key không có scope admin phải gắn với một user có thật
+		w, problem = do("POST", "/api/v1/admin/api-keys", "admin-secret", entities.CreateAPIKeyRequest{Name: "orphan", Scopes: []string{entities.ScopeLinksRead}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_api_key", problem.Code)
+		missing := uint(999)
+		w, problem = do("POST", "/api/v1/admin/api-keys", "admin-secret", entities.CreateAPIKeyRequest{Name: "ghost", UserID: &missing, Scopes: []string{entities.ScopeLinksRead}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "user_not_found", problem.Code)
+	})
+
+	// Test case 3: Scope giới hạn thao tác
+	t.Run("Enforce scopes", func(t *testing.T) {
+		reader := createKey("reader", entities.ScopeLinksRead)
+
+		w, _ := do("GET", "/api/v1/urls/keep", reader.Key, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		for _, req := range []struct{ method, path string }{
+			{"DELETE", "/api/v1/urls/keep"},
+			{"GET", "/api/v1/urls/keep/stats"},
+			{"GET", "/api/v1/admin/api-keys"},
+		} {
+			w, problem := do(req.method, req.path, reader.Key, nil)
+			assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", req.method, req.path)
+			assert.Equal(t, "insufficient_scope", problem.Code, "%s %s", req.method, req.path)
+		}
+
+		var stored entities.APIKey
+		db.First(&stored, reader.ID)
+		assert.NotNil(t, stored.LastUsedAt)
+
+		// Scope admin bao gồm mọi scope khác
+		adminKey := createKey("ops", entities.ScopeAdmin)
+		w, _ = do("GET", "/api/v1/urls/keep/stats", adminKey.Key, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w, _ = do("GET", "/api/v1/admin/api-keys", adminKey.Key, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// Test case 4: Rotate thay key, key cũ hết hiệu lực ngay
+	t.Run("Rotate key", func(t *testing.T) {
+		writer := createKey("writer", entities.ScopeLinksWrite)
+
+		w, _ := do("POST", fmt.Sprintf("/api/v1/admin/api-keys/%d/rotate", writer.ID), "admin-secret", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var rotated entities.APIKeyResponse
+		json.Unmarshal(w.Body.Bytes(), &rotated)
+		assert.NotEqual(t, writer.Key, rotated.Key)
+		assert.Equal(t, writer.Scopes, rotated.Scopes)
+
+		w, _ = do("POST", "/api/v1/urls", writer.Key, map[string]string{"url": "https://example.com/old"})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		w, _ = do("POST", "/api/v1/urls", rotated.Key, map[string]string{"url": "https://example.com/new"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w, _ = do("POST", "/api/v1/admin/api-keys/999/rotate", "admin-secret", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// Test case 5: Revoke chặn key nhưng vẫn giữ trong danh sách
+	t.Run("Revoke key", func(t *testing.T) {
+		writer := createKey("to-revoke", entities.ScopeLinksWrite)
+
+		w, _ := do("DELETE", fmt.Sprintf("/api/v1/admin/api-keys/%d", writer.ID), "admin-secret", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var revoked entities.APIKeyResponse
+		json.Unmarshal(w.Body.Bytes(), &revoked)
+		assert.NotNil(t, revoked.RevokedAt)
+
+		w, _ = do("DELETE", "/api/v1/urls/keep", writer.Key, nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w, problem := do("DELETE", fmt.Sprintf("/api/v1/admin/api-keys/%d", writer.ID), "admin-secret", nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "api_key_revoked", problem.Code)
+
+		var count int64
+		db.Model(&entities.APIKey{}).Where("id = ?", writer.ID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestURLOwnership(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	tagRepo := repositories.NewTagRepositoryImpl(db)
+	urlUsecase := usecases.NewURLUsecase(urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080", getTestConfig(), nil)
+	urlHandler := handlers.NewURLHandler(urlUsecase)
+	tagHandler := handlers.NewTagHandler(usecases.NewTagUsecase(tagRepo, urlRepo, repositories.NewWorkspaceRepositoryImpl(db), "http://localhost:8080"))
+	folderHandler := handlers.NewFolderHandler(usecases.NewFolderUsecase(repositories.NewFolderRepositoryImpl(db), tagRepo, urlRepo, repositories.NewWorkspaceRepositoryImpl(db)))
+	userRepo := repositories.NewUserRepositoryImpl(db)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), userRepo, "admin-secret")
+	userHandler := handlers.NewUserHandler(usecases.NewUserUsecase(userRepo))
+
+	// Tạo router
+	router := gin.New()
+	v1 := router.Group("/api/v1", middleware.APIKeyAuthMiddleware(apiKeyUsecase))
+	v1.POST("/urls", urlHandler.CreateShortURL)
+	v1.GET("/urls", urlHandler.ListURLs)
+	v1.GET("/urls/:shortCode", urlHandler.GetURLInfo)
+	v1.DELETE("/urls/:shortCode", urlHandler.DeleteURL)
+	v1.GET("/urls/:shortCode/stats", urlHandler.GetURLStats)
+	v1.PUT("/urls/:shortCode/tags", tagHandler.SetURLTags)
+	v1.PUT("/urls/:shortCode/folder", folderHandler.SetURLFolder)
+	v1.GET("/tags", tagHandler.ListTags)
+	v1.PUT("/tags/:tag", tagHandler.RenameTag)
+	v1.DELETE("/tags/:tag", tagHandler.DeleteTag)
+	v1.GET("/tags/:tag/stats", tagHandler.GetTagStats)
+	v1.POST("/folders", folderHandler.CreateFolder)
+	v1.GET("/folders", folderHandler.ListFolders)
+	v1.PUT("/folders/:id", folderHandler.UpdateFolder)
+	v1.DELETE("/folders/:id", folderHandler.DeleteFolder)
+	admin := v1.Group("/admin", middleware.RequireScope(entities.ScopeAdmin))
+	admin.POST("/users", userHandler.CreateUser)
+	admin.GET("/users", userHandler.ListUsers)
+
+	do := func(method, path, token string, body interface{}) (*httptest.ResponseRecorder, entities.Problem) {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &payload)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem entities.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		return w, problem
+	}
+	createUser := func(name, email string) (entities.User, string) {
+		w, _ := do("POST", "/api/v1/admin/users", "admin-secret", entities.CreateUserRequest{Name: name, Email: email})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var user entities.User
+		json.Unmarshal(w.Body.Bytes(), &user)
+
+		key, err := apiKeyUsecase.CreateAPIKey(context.Background(), entities.CreateAPIKeyRequest{
+			Name:   name,
+			UserID: &user.ID,
+			Scopes: []string{entities.ScopeLinksRead, entities.ScopeLinksWrite, entities.ScopeStatsRead},
+		})
+		require.NoError(t, err)
+		return user, key.Key
+	}
+	createURL := func(token, url string) entities.CreateURLResponse {
+		w, _ := do("POST", "/api/v1/urls", token, map[string]string{"url": url})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var created entities.CreateURLResponse
+		json.Unmarshal(w.Body.Bytes(), &created)
+		return created
+	}
+	listCodes := func(token, query string) (int, []string) {
+		w, _ := do("GET", "/api/v1/urls"+query, token, nil)
+		var response entities.URLListResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		codes := make([]string, 0, len(response.URLs))
+		for _, item := range response.URLs {
+			codes = append(codes, item.ShortCode)
+		}
+		return w.Code, codes
+	}
+
+	alice, aliceKey := createUser("Alice", "Alice@Example.com")
+	bob, bobKey := createUser("Bob", "bob@example.com")
+	aliceURL := createURL(aliceKey, "https://example.com/alice")
+	bobURL := createURL(bobKey, "https://example.com/bob")
+
+	// Test case 1: Quản lý user
+	t.Run("Manage users", func(t *testing.T) {
+		assert.Equal(t, "alice@example.com", alice.Email)
+
+		w, problem := do("POST", "/api/v1/admin/users", "admin-secret", entities.CreateUserRequest{Name: "Alice 2", Email: "ALICE@example.com"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "user_exists", problem.Code)
+
+		w, problem = do("POST", "/api/v1/admin/users", "admin-secret", entities.CreateUserRequest{Name: "Bad", Email: "not-an-email"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_user", problem.Code)
+
+		w, _ = do("GET", "/api/v1/admin/users", "admin-secret", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var users entities.UserListResponse
+		json.Unmarshal(w.Body.Bytes(), &users)
+		assert.Len(t, users.Users, 2)
+
+		w, _ = do("GET", "/api/v1/admin/users", aliceKey, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	// Test case 2: Link mới thuộc về user của API key
+	t.Run("Assign owner on create", func(t *testing.T) {
+		var stored entities.URL
+		require.NoError(t, db.Where("short_code = ?", aliceURL.ShortCode).First(&stored).Error)
+		require.NotNil(t, stored.OwnerID)
+		assert.Equal(t, alice.ID, *stored.OwnerID)
+	})
+
+	// Test case 3: Chỉ owner hoặc admin được xem thống kê, sửa, xóa
+	t.Run("Enforce ownership", func(t *testing.T) {
+		for _, req := range []struct {
+			method, path string
+			body         interface{}
+		}{
+			{"GET", "/api/v1/urls/" + aliceURL.ShortCode + "/stats", nil},
+			{"PUT", "/api/v1/urls/" + aliceURL.ShortCode + "/tags", entities.SetURLTagsRequest{Tags: []string{"hijack"}}},
+			{"DELETE", "/api/v1/urls/" + aliceURL.ShortCode, nil},
+		} {
+			w, problem := do(req.method, req.path, bobKey, req.body)
+			assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", req.method, req.path)
+			assert.Equal(t, "url_forbidden", problem.Code, "%s %s", req.method, req.path)
+		}
+
+		// Xem thông tin URL không kiểm tra owner
+		w, _ := do("GET", "/api/v1/urls/"+aliceURL.ShortCode, bobKey, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w, _ = do("GET", "/api/v1/urls/"+aliceURL.ShortCode+"/stats", aliceKey, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w, _ = do("GET", "/api/v1/urls/"+bobURL.ShortCode+"/stats", "admin-secret", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// Test case 4: Danh sách mặc định chỉ gồm link của caller
+	t.Run("Scope listing", func(t *testing.T) {
+		code, codes := listCodes(aliceKey, "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{aliceURL.ShortCode}, codes)
+
+		code, codes = listCodes(aliceKey, fmt.Sprintf("?owner=%d", alice.ID))
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{aliceURL.ShortCode}, codes)
+
+		code, _ = listCodes(aliceKey, "?owner=all")
+		assert.Equal(t, http.StatusForbidden, code)
+		code, _ = listCodes(aliceKey, fmt.Sprintf("?owner=%d", bob.ID))
+		assert.Equal(t, http.StatusForbidden, code)
+		code, _ = listCodes(aliceKey, "?owner=someone")
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, codes = listCodes("admin-secret", "?owner=all")
+		assert.Equal(t, http.StatusOK, code)
+		assert.ElementsMatch(t, []string{aliceURL.ShortCode, bobURL.ShortCode}, codes)
+		code, codes = listCodes("admin-secret", fmt.Sprintf("?owner=%d", bob.ID))
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{bobURL.ShortCode}, codes)
+	})
+
+	// Test case 5: Thống kê tag chỉ tính link của caller
+	t.Run("Scope tag stats", func(t *testing.T) {
+		for _, req := range []struct{ token, shortCode string }{
+			{aliceKey, aliceURL.ShortCode},
+			{bobKey, bobURL.ShortCode},
+		} {
+			w, _ := do("PUT", "/api/v1/urls/"+req.shortCode+"/tags", req.token, entities.SetURLTagsRequest{Tags: []string{"shared"}})
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		}
+
+		for token, expected := range map[string]int64{aliceKey: 1, bobKey: 1, "admin-secret": 2} {
+			w, _ := do("GET", "/api/v1/tags/shared/stats", token, nil)
+			require.Equal(t, http.StatusOK, w.Code)
+			var stats entities.TagStatsResponse
+			json.Unmarshal(w.Body.Bytes(), &stats)
+			assert.Equal(t, expected, stats.URLCount)
+			assert.Len(t, stats.TopURLs, int(expected))
+		}
+	})
+
+	// Test case 6: Tag và folder dùng chung nên chỉ admin được sửa, xóa; số URL chỉ tính link của caller
+	t.Run("Shared tags and folders", func(t *testing.T) {
+		w, _ := do("POST", "/api/v1/folders", aliceKey, entities.FolderRequest{Name: "Campaigns"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var folder entities.FolderResponse
+		json.Unmarshal(w.Body.Bytes(), &folder)
+		for _, req := range []struct{ token, shortCode string }{
+			{aliceKey, aliceURL.ShortCode},
+			{bobKey, bobURL.ShortCode},
+		} {
+			w, _ := do("PUT", "/api/v1/urls/"+req.shortCode+"/folder", req.token, entities.SetURLFolderRequest{FolderID: &folder.ID})
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		}
+
+		for _, req := range []struct {
+			method, path string
+			body         interface{}
+		}{
+			{"PUT", "/api/v1/tags/shared", entities.TagRequest{Name: "hijacked"}},
+			{"DELETE", "/api/v1/tags/shared", nil},
+			{"PUT", fmt.Sprintf("/api/v1/folders/%d", folder.ID), entities.FolderRequest{Name: "Hijacked"}},
+			{"DELETE", fmt.Sprintf("/api/v1/folders/%d", folder.ID), nil},
+		} {
+			w, problem := do(req.method, req.path, bobKey, req.body)
+			assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", req.method, req.path)
+			assert.Equal(t, "insufficient_scope", problem.Code, "%s %s", req.method, req.path)
+		}
+
+		w, _ = do("GET", "/api/v1/tags", aliceKey, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var tags entities.TagListResponse
+		json.Unmarshal(w.Body.Bytes(), &tags)
+		require.Len(t, tags.Tags, 1)
+		assert.Equal(t, int64(1), tags.Tags[0].URLCount)
+
+		w, _ = do("GET", "/api/v1/folders", bobKey, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var folders entities.FolderListResponse
+		json.Unmarshal(w.Body.Bytes(), &folders)
+		require.Len(t, folders.Folders, 1)
+		assert.Equal(t, int64(1), folders.Folders[0].URLCount)
+
+		w, _ = do("GET", "/api/v1/folders", "admin-secret", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &folders)
+		assert.Equal(t, int64(2), folders.Folders[0].URLCount)
+
+		w, _ = do("PUT", "/api/v1/tags/shared", "admin-secret", entities.TagRequest{Name: "team"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var tag entities.TagResponse
+		json.Unmarshal(w.Body.Bytes(), &tag)
+		assert.Equal(t, int64(2), tag.URLCount)
+	})
+
+	// Test case 7: Owner xóa được link của mình
+	t.Run("Owner deletes", func(t *testing.T) {
+		w, _ := do("DELETE", "/api/v1/urls/"+aliceURL.ShortCode, aliceKey, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestWorkspaces(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	// Khởi tạo dependencies
+	urlRepo := repositories.NewURLRepositoryImpl(db)
+	workspaceRepo := repositories.NewWorkspaceRepositoryImpl(db)
+	userRepo := repositories.NewUserRepositoryImpl(db)
+	urlHandler := handlers.NewURLHandler(usecases.NewURLUsecase(urlRepo, workspaceRepo, "http://localhost:8080", getTestConfig(), nil))
+	tagHandler := handlers.NewTagHandler(usecases.NewTagUsecase(repositories.NewTagRepositoryImpl(db), urlRepo, workspaceRepo, "http://localhost:8080"))
+	workspaceHandler := handlers.NewWorkspaceHandler(usecases.NewWorkspaceUsecase(workspaceRepo, userRepo))
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(repositories.NewAPIKeyRepositoryImpl(db), userRepo, "admin-secret")
+	userUsecase := usecases.NewUserUsecase(userRepo)
+
+	// Tạo router
+	router := gin.New()
+	router.GET("/:shortCode", urlHandler.Redirect)
+	v1 := router.Group("/api/v1", middleware.APIKeyAuthMiddleware(apiKeyUsecase))
+	v1.POST("/urls", urlHandler.CreateShortURL)
+	v1.GET("/urls", urlHandler.ListURLs)
+	v1.DELETE("/urls/:shortCode", urlHandler.DeleteURL)
+	v1.GET("/urls/:shortCode/stats", urlHandler.GetURLStats)
+	v1.PUT("/urls/:shortCode/tags", tagHandler.SetURLTags)
+	v1.GET("/tags/:tag/stats", tagHandler.GetTagStats)
+	v1.POST("/workspaces", workspaceHandler.CreateWorkspace)
+	v1.GET("/workspaces", workspaceHandler.ListWorkspaces)
+	v1.GET("/workspaces/:id", workspaceHandler.GetWorkspace)
+	v1.PUT("/workspaces/:id/settings", workspaceHandler.UpdateSettings)
+	v1.GET("/workspaces/:id/members", workspaceHandler.ListMembers)
+	v1.POST("/workspaces/:id/members", workspaceHandler.InviteMember)
+	v1.PUT("/workspaces/:id/members/:userId", workspaceHandler.UpdateMember)
+	v1.DELETE("/workspaces/:id/members/:userId", workspaceHandler.RemoveMember)
+
+	do := func(method, path, token string, body interface{}) (*httptest.ResponseRecorder, entities.Problem) {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &payload)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem entities.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		return w, problem
+	}
+	createUser := func(name, email string) (*entities.User, string) {
+		user, err := userUsecase.CreateUser(context.Background(), entities.CreateUserRequest{Name: name, Email: email})
+		require.NoError(t, err)
+		key, err := apiKeyUsecase.CreateAPIKey(context.Background(), entities.CreateAPIKeyRequest{
+			Name:   name,
+			UserID: &user.ID,
+			Scopes: []string{entities.ScopeLinksRead, entities.ScopeLinksWrite, entities.ScopeStatsRead},
+		})
+		require.NoError(t, err)
+		return user, key.Key
+	}
+
+	owner, ownerKey := createUser("Owner", "owner@example.com")
+	editor, editorKey := createUser("Editor", "editor@example.com")
+	analyst, analystKey := createUser("Analyst", "analyst@example.com")
+	viewer, viewerKey := createUser("Viewer", "viewer@example.com")
+	_, outsiderKey := createUser("Outsider", "outsider@example.com")
+
+	var workspace entities.Workspace
+	workspacePath := func(suffix string) string {
+		return fmt.Sprintf("/api/v1/workspaces/%d%s", workspace.ID, suffix)
+	}
+
+	// Test case 1: Người tạo là owner của workspace
+	t.Run("Create workspace", func(t *testing.T) {
+		w, problem := do("POST", "/api/v1/workspaces", ownerKey, entities.CreateWorkspaceRequest{Name: " "})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_workspace", problem.Code)
+
+		w, problem = do("POST", "/api/v1/workspaces", ownerKey, entities.CreateWorkspaceRequest{Name: "Other", OwnerID: &editor.ID})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "insufficient_scope", problem.Code)
+
+		w, _ = do("POST", "/api/v1/workspaces", ownerKey, entities.CreateWorkspaceRequest{Name: "Marketing"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		json.Unmarshal(w.Body.Bytes(), &workspace)
+		assert.Equal(t, "Marketing", workspace.Name)
+		assert.Equal(t, entities.DefaultRedirectType, workspace.Settings.DefaultRedirectType)
+
+		w, problem = do("GET", workspacePath(""), outsiderKey, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "workspace_forbidden", problem.Code)
+
+		w, problem = do("GET", "/api/v1/workspaces/999", ownerKey, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "workspace_not_found", problem.Code)
+	})
+
+	// Test case 2: Owner mời, đổi role và xóa member
+	t.Run("Manage members", func(t *testing.T) {
+		for _, invite := range []struct {
+			email, role string
+		}{
+			{"editor@example.com", entities.WorkspaceRoleEditor},
+			{"analyst@example.com", entities.WorkspaceRoleViewer},
+			{"viewer@example.com", entities.WorkspaceRoleViewer},
+		} {
+			w, _ := do("POST", workspacePath("/members"), ownerKey, entities.InviteMemberRequest{Email: invite.email, Role: invite.role})
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		}
+
+		w, problem := do("POST", workspacePath("/members"), ownerKey, entities.InviteMemberRequest{Email: "viewer@example.com", Role: entities.WorkspaceRoleViewer})
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "member_exists", problem.Code)
+		w, problem = do("POST", workspacePath("/members"), ownerKey, entities.InviteMemberRequest{Email: "nobody@example.com", Role: entities.WorkspaceRoleViewer})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "user_not_found", problem.Code)
+		w, problem = do("POST", workspacePath("/members"), ownerKey, entities.InviteMemberRequest{Email: "outsider@example.com", Role: "admin"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_workspace", problem.Code)
+		w, problem = do("POST", workspacePath("/members"), editorKey, entities.InviteMemberRequest{Email: "outsider@example.com", Role: entities.WorkspaceRoleViewer})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "workspace_forbidden", problem.Code)
+
+		w, _ = do("PUT", workspacePath(fmt.Sprintf("/members/%d", analyst.ID)), ownerKey, entities.UpdateMemberRequest{Role: entities.WorkspaceRoleAnalyst})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var member entities.WorkspaceMember
+		json.Unmarshal(w.Body.Bytes(), &member)
+		assert.Equal(t, entities.WorkspaceRoleAnalyst, member.Role)
+
+		// Không hạ role hoặc xóa owner cuối cùng
+		w, problem = do("PUT", workspacePath(fmt.Sprintf("/members/%d", owner.ID)), ownerKey, entities.UpdateMemberRequest{Role: entities.WorkspaceRoleEditor})
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "last_workspace_owner", problem.Code)
+		w, problem = do("DELETE", workspacePath(fmt.Sprintf("/members/%d", owner.ID)), ownerKey, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "last_workspace_owner", problem.Code)
+
+		w, problem = do("DELETE", workspacePath("/members/999"), ownerKey, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "member_not_found", problem.Code)
+
+		w, _ = do("GET", workspacePath("/members"), viewerKey, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var members entities.WorkspaceMemberListResponse
+		json.Unmarshal(w.Body.Bytes(), &members)
+		assert.Len(t, members.Members, 4)
+
+		w, _ = do("GET", "/api/v1/workspaces", viewerKey, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var workspaces entities.WorkspaceListResponse
+		json.Unmarshal(w.Body.Bytes(), &workspaces)
+		require.Len(t, workspaces.Workspaces, 1)
+		assert.Equal(t, workspace.ID, workspaces.Workspaces[0].ID)
+
+		w, _ = do("GET", "/api/v1/workspaces", outsiderKey, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &workspaces)
+		assert.Empty(t, workspaces.Workspaces)
+	})
+
+	// Test case 3: Chỉ owner sửa settings, giá trị được kiểm tra
+	t.Run("Update settings", func(t *testing.T) {
+		settings := entities.WorkspaceSettings{
+			DefaultRedirectType: http.StatusFound,
+			DefaultUTM:          entities.UTMParams{Source: " newsletter ", Medium: "email"},
+		}
+		w, problem := do("PUT", workspacePath("/settings"), editorKey, settings)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "workspace_forbidden", problem.Code)
+
+		w, problem = do("PUT", workspacePath("/settings"), ownerKey, entities.WorkspaceSettings{DefaultRedirectType: http.StatusOK})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_workspace", problem.Code)
+
+		w, _ = do("PUT", workspacePath("/settings"), ownerKey, settings)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		json.Unmarshal(w.Body.Bytes(), &workspace)
+		assert.Equal(t, http.StatusFound, workspace.Settings.DefaultRedirectType)
+		assert.Equal(t, "newsletter", workspace.Settings.DefaultUTM.Source)
+	})
+
+	var workspaceURL entities.CreateURLResponse
+
+	// Test case 4: Link trong workspace dùng settings mặc định, viewer không tạo được link
+	t.Run("Create links in workspace", func(t *testing.T) {
+		body := map[string]interface{}{"url": "https://example.com/sale?utm_source=ads", "workspace_id": workspace.ID}
+		for _, token := range []string{viewerKey, analystKey, outsiderKey} {
+			w, problem := do("POST", "/api/v1/urls", token, body)
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Equal(t, "workspace_forbidden", problem.Code)
+		}
+		w, problem := do("POST", "/api/v1/urls", editorKey, map[string]interface{}{"url": "https://example.com", "workspace_id": 999})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "workspace_not_found", problem.Code)
+		w, problem = do("POST", "/api/v1/urls", editorKey, map[string]interface{}{"url": "https://example.com", "redirect_type": 303})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_request", problem.Code)
+
+		w, _ = do("POST", "/api/v1/urls", editorKey, body)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		json.Unmarshal(w.Body.Bytes(), &workspaceURL)
+		assert.Equal(t, "https://example.com/sale?utm_source=ads&utm_medium=email", workspaceURL.OriginalURL)
+
+		req, _ := http.NewRequest("GET", "/"+workspaceURL.ShortCode, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusFound, resp.Code)
+		assert.Equal(t, workspaceURL.OriginalURL, resp.Header().Get("Location"))
+
+		// redirect_type trong request được ưu tiên hơn settings
+		w, _ = do("POST", "/api/v1/urls", editorKey, map[string]interface{}{"url": "https://example.com/temp", "workspace_id": workspace.ID, "redirect_type": 307})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var created entities.CreateURLResponse
+		json.Unmarshal(w.Body.Bytes(), &created)
+		req, _ = http.NewRequest("GET", "/"+created.ShortCode, nil)
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusTemporaryRedirect, resp.Code)
+	})
+
+	// Test case 5: Danh sách theo workspace_id, mặc định chỉ link cá nhân
+	t.Run("List workspace links", func(t *testing.T) {
+		w, _ := do("POST", "/api/v1/urls", editorKey, map[string]string{"url": "https://example.com/personal"})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		w, _ = do("GET", fmt.Sprintf("/api/v1/urls?workspace_id=%d", workspace.ID), viewerKey, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response entities.URLListResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		require.Len(t, response.URLs, 2)
+		for _, item := range response.URLs {
+			require.NotNil(t, item.WorkspaceID)
+			assert.Equal(t, workspace.ID, *item.WorkspaceID)
+		}
+
+		w, _ = do("GET", "/api/v1/urls", editorKey, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &response)
+		require.Len(t, response.URLs, 1)
+		assert.Nil(t, response.URLs[0].WorkspaceID)
+		assert.Equal(t, entities.DefaultRedirectType, response.URLs[0].RedirectType)
+
+		w, problem := do("GET", fmt.Sprintf("/api/v1/urls?workspace_id=%d", workspace.ID), outsiderKey, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "workspace_forbidden", problem.Code)
+	})
+
+	// Test case 6: Role quyết định quyền trên link của workspace
+	t.Run("Gate link actions by role", func(t *testing.T) {
+		statsPath := "/api/v1/urls/" + workspaceURL.ShortCode + "/stats"
+		for token, status := range map[string]int{viewerKey: http.StatusForbidden, outsiderKey: http.StatusForbidden, analystKey: http.StatusOK, ownerKey: http.StatusOK} {
+			w, _ := do("GET", statsPath, token, nil)
+			assert.Equal(t, status, w.Code)
+		}
+
+		w, problem := do("PUT", "/api/v1/urls/"+workspaceURL.ShortCode+"/tags", analystKey, entities.SetURLTagsRequest{Tags: []string{"sale"}})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "url_forbidden", problem.Code)
+		w, _ = do("PUT", "/api/v1/urls/"+workspaceURL.ShortCode+"/tags", editorKey, entities.SetURLTagsRequest{Tags: []string{"sale"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		tagStatsPath := fmt.Sprintf("/api/v1/tags/sale/stats?workspace_id=%d", workspace.ID)
+		w, _ = do("GET", tagStatsPath, viewerKey, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w, _ = do("GET", tagStatsPath, analystKey, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var stats entities.TagStatsResponse
+		json.Unmarshal(w.Body.Bytes(), &stats)
+		assert.Equal(t, int64(1), stats.URLCount)
+
+		w, problem = do("DELETE", "/api/v1/urls/"+workspaceURL.ShortCode, analystKey, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "url_forbidden", problem.Code)
+
+		// Member bị xóa mất quyền trên link của workspace
+		w, _ = do("DELETE", workspacePath(fmt.Sprintf("/members/%d", analyst.ID)), ownerKey, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		w, _ = do("GET", statsPath, analystKey, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// Editor xóa được link của workspace do người khác tạo
+		w, _ = do("PUT", workspacePath(fmt.Sprintf("/members/%d", viewer.ID)), ownerKey, entities.UpdateMemberRequest{Role: entities.WorkspaceRoleEditor})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		w, _ = do("DELETE", "/api/v1/urls/"+workspaceURL.ShortCode, viewerKey, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Member tự rời workspace
+		w, _ = do("DELETE", workspacePath(fmt.Sprintf("/members/%d", editor.ID)), editorKey, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// Test case 7: Điều kiện owner cuối cùng nằm trong câu UPDATE/DELETE, không dựa vào lần đếm trước đó
+	t.Run("Last owner guard is atomic", func(t *testing.T) {
+		w, _ := do("PUT", workspacePath(fmt.Sprintf("/members/%d", viewer.ID)), ownerKey, entities.UpdateMemberRequest{Role: entities.WorkspaceRoleOwner})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		// Hai request cùng thấy hai owner rồi mới ghi: chỉ lần ghi đầu thành công
+		require.NoError(t, workspaceRepo.DeleteMember(workspace.ID, owner.ID))
+		err := workspaceRepo.UpdateMember(&entities.WorkspaceMember{WorkspaceID: workspace.ID, UserID: viewer.ID, Role: entities.WorkspaceRoleEditor})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, workspaceRepo.DeleteMember(workspace.ID, viewer.ID), gorm.ErrRecordNotFound)
+
+		member, err := workspaceRepo.GetMember(workspace.ID, viewer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.WorkspaceRoleOwner, member.Role)
+
+		w, problem := do("DELETE", workspacePath(fmt.Sprintf("/members/%d", viewer.ID)), viewerKey, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "last_workspace_owner", problem.Code)
+	})
+}